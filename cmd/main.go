@@ -22,31 +22,44 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"net/http"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 
+	veneerv1alpha1 "github.com/nextdoor/veneer/pkg/apis/v1alpha1"
 	"github.com/nextdoor/veneer/pkg/config"
 	"github.com/nextdoor/veneer/pkg/metrics"
 	"github.com/nextdoor/veneer/pkg/overlay"
 	"github.com/nextdoor/veneer/pkg/preference"
 	"github.com/nextdoor/veneer/pkg/prometheus"
 	"github.com/nextdoor/veneer/pkg/reconciler"
+	"github.com/nextdoor/veneer/pkg/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -67,14 +80,20 @@ func init() {
 	)
 	metav1.AddToGroupVersion(scheme, karpenterv1alpha1GV)
 
-	// Register Karpenter v1 types (NodePool) for preference-based overlays
+	// Register Karpenter v1 types (NodePool) for preference-based overlays, and
+	// NodeClaim for counting launches since the last Lumina refresh
 	karpenterv1GV := schema.GroupVersion{Group: "karpenter.sh", Version: "v1"}
 	scheme.AddKnownTypes(karpenterv1GV,
 		&karpenterv1.NodePool{},
 		&karpenterv1.NodePoolList{},
+		&karpenterv1.NodeClaim{},
+		&karpenterv1.NodeClaimList{},
 	)
 	metav1.AddToGroupVersion(scheme, karpenterv1GV)
 
+	// Register Veneer types (VeneerPreference, VeneerConfig)
+	utilruntime.Must(veneerv1alpha1.AddToScheme(scheme))
+
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -114,10 +133,13 @@ func main() {
 
 	// Load controller configuration
 	cfg, err := config.Load(configFile)
+	configFileLoaded := err == nil
 	if err != nil {
 		if _, statErr := os.Stat(configFile); os.IsNotExist(statErr) {
 			setupLog.Info("config file not found, using defaults", "config-file", configFile)
 			cfg = &config.Config{}
+			// The zero value disables preferences, which would clean up existing preference overlays
+			cfg.Preferences.Enabled = config.DefaultPreferencesEnabled
 		} else {
 			setupLog.Error(err, "failed to load configuration", "config-file", configFile)
 			os.Exit(1)
@@ -133,7 +155,16 @@ func main() {
 		cfg.Overlays.Disabled = true
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	ctx := ctrl.SetupSignalHandler()
+	restConfig := ctrl.GetConfigOrDie()
+
+	// Components read the configuration through the store, so config file reloads and
+	// VeneerConfig changes reach them. The VeneerConfig is read before anything else uses the
+	// configuration, so it also supplies the settings only read at startup.
+	configStore := newConfigStore(ctx, restConfig, cfg, overlayDisabled)
+	cfg = configStore.Current()
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
@@ -141,6 +172,11 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "veneer.nextdoor.com",
+		// The webhook server is only started if a webhook is registered below
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Port:    cfg.Webhook.Port,
+			CertDir: cfg.Webhook.CertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -162,7 +198,7 @@ func main() {
 	}
 
 	// Create decision engine and generator for NodeOverlay lifecycle management
-	decisionEngine := overlay.NewDecisionEngine(cfg)
+	decisionEngine := overlay.NewDecisionEngine(configStore)
 	generator := overlay.NewGeneratorWithOptions(cfg.Overlays.Disabled)
 
 	// Log disabled mode status at startup
@@ -180,13 +216,16 @@ func main() {
 	// Create and start metrics reconciler
 	metricsReconciler := &reconciler.MetricsReconciler{
 		PrometheusClient: promClient,
-		Config:           cfg,
+		Config:           configStore,
 		DecisionEngine:   decisionEngine,
 		Generator:        generator,
 		Logger:           ctrl.Log.WithName("metrics-reconciler"),
 		Client:           mgr.GetClient(),
 		Metrics:          veneerMetrics,
-		// Use default 5 minute interval
+		Hysteresis:       overlay.NewHysteresisTracker(),
+		Recorder:         mgr.GetEventRecorder(reconciler.EventRecorderName),
+		Interval:         cfg.Reconcile.ReconcileInterval(),
+		PollInterval:     cfg.Reconcile.FreshnessPollInterval(),
 	}
 
 	// Add metrics reconciler as a runnable
@@ -195,14 +234,79 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Serve the endpoint that forces a reconcile on its own address. It is unauthenticated,
+	// so it is only served when an address is configured.
+	if cfg.TriggerBindAddress != "" {
+		triggerMux := http.NewServeMux()
+		triggerMux.Handle(reconciler.TriggerPath, metricsReconciler.TriggerHandler())
+		if err := mgr.Add(&manager.Server{
+			Name: "reconcile trigger",
+			Server: &http.Server{
+				Addr:              cfg.TriggerBindAddress,
+				Handler:           triggerMux,
+				ReadHeaderTimeout: 10 * time.Second,
+			},
+		}); err != nil {
+			setupLog.Error(err, "unable to add reconcile trigger endpoint to manager")
+			os.Exit(1)
+		}
+		setupLog.Info("reconcile trigger endpoint enabled", "address", cfg.TriggerBindAddress)
+	}
+
+	// Config file reloads and VeneerConfig changes are applied by the reloader, which also
+	// updates the generators and config metrics, and notifies the reconcilers subscribed below.
+	reloader := &configReloader{
+		store:               configStore,
+		overlayDisabledFlag: overlayDisabled,
+		overlayGenerator:    generator,
+		metrics:             veneerMetrics,
+		log:                 ctrl.Log.WithName("config-reloader"),
+	}
+
+	// Set up the VeneerConfig reconciler, if the CRD is installed. Helm doesn't upgrade CRDs,
+	// so clusters upgraded from older charts may not have it yet.
+	veneerConfigGK := veneerv1alpha1.GroupVersion.WithKind("VeneerConfig").GroupKind()
+	_, err = mgr.GetRESTMapper().RESTMapping(veneerConfigGK, veneerv1alpha1.GroupVersion.Version)
+	switch {
+	case meta.IsNoMatchError(err):
+		setupLog.Info("VeneerConfig CRD not installed, skipping VeneerConfig reconciler")
+	case err != nil:
+		setupLog.Error(err, "unable to look up the VeneerConfig CRD")
+		os.Exit(1)
+	default:
+		veneerConfigReconciler := &reconciler.VeneerConfigReconciler{
+			Client:            mgr.GetClient(),
+			Logger:            ctrl.Log.WithName("veneerconfig-reconciler"),
+			Store:             configStore,
+			OverlaysDisabled:  overlayDisabled,
+			OnChange:          reloader.apply,
+			ConfigReloaded:    reloader.subscribe(),
+			MetricsReconciler: metricsReconciler,
+			Elected:           mgr.Elected(),
+			Metrics:           veneerMetrics,
+		}
+		if err := veneerConfigReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to setup VeneerConfig reconciler")
+			os.Exit(1)
+		}
+		setupLog.Info("VeneerConfig reconciler configured")
+	}
+
+	// Preference reconcilers always run and read preferences.enabled on every reconcile. While
+	// preferences are disabled they remove the overlays generated while they were enabled.
+	preferenceGenerator := preference.NewGeneratorWithOptions(cfg.Overlays.Disabled)
+	reloader.preferenceGenerator = preferenceGenerator
+
 	// Create and setup NodePool reconciler for preference-based overlays
 	// This watches NodePools and generates NodeOverlays from veneer.io/preference.N annotations
-	preferenceGenerator := preference.NewGeneratorWithOptions(cfg.Overlays.Disabled)
 	nodePoolReconciler := &reconciler.NodePoolReconciler{
-		Client:    mgr.GetClient(),
-		Logger:    ctrl.Log.WithName("nodepool-reconciler"),
-		Generator: preferenceGenerator,
-		Metrics:   veneerMetrics,
+		Client:         mgr.GetClient(),
+		Logger:         ctrl.Log.WithName("nodepool-reconciler"),
+		Generator:      preferenceGenerator,
+		Metrics:        veneerMetrics,
+		Config:         configStore,
+		ConfigReloaded: reloader.subscribe(),
+		Recorder:       mgr.GetEventRecorder(reconciler.EventRecorderName),
 	}
 	if err := nodePoolReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to setup NodePool reconciler")
@@ -210,6 +314,75 @@ func main() {
 	}
 	setupLog.Info("NodePool reconciler configured for preference-based overlays")
 
+	// Create and setup VeneerPreference reconciler, if the CRD is installed. Helm doesn't
+	// upgrade CRDs, so clusters upgraded from older charts may not have it yet.
+	veneerPreferenceGK := veneerv1alpha1.GroupVersion.WithKind("VeneerPreference").GroupKind()
+	_, err = mgr.GetRESTMapper().RESTMapping(veneerPreferenceGK, veneerv1alpha1.GroupVersion.Version)
+	switch {
+	case meta.IsNoMatchError(err):
+		setupLog.Info("VeneerPreference CRD not installed, skipping VeneerPreference reconciler")
+	case err != nil:
+		setupLog.Error(err, "unable to look up the VeneerPreference CRD")
+		os.Exit(1)
+	default:
+		veneerPreferenceReconciler := &reconciler.VeneerPreferenceReconciler{
+			Client:         mgr.GetClient(),
+			Logger:         ctrl.Log.WithName("veneerpreference-reconciler"),
+			Generator:      preferenceGenerator,
+			Metrics:        veneerMetrics,
+			Config:         configStore,
+			ConfigReloaded: reloader.subscribe(),
+			Recorder:       mgr.GetEventRecorder(reconciler.EventRecorderName),
+		}
+		if err := veneerPreferenceReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to setup VeneerPreference reconciler")
+			os.Exit(1)
+		}
+		setupLog.Info("VeneerPreference reconciler configured for preference-based overlays")
+	}
+
+	// Set up ClusterPreference reconciler for cluster-wide preferences from the config file
+	clusterPreferenceReconciler := &reconciler.ClusterPreferenceReconciler{
+		Client:         mgr.GetClient(),
+		Logger:         ctrl.Log.WithName("clusterpreference-reconciler"),
+		Generator:      preferenceGenerator,
+		Metrics:        veneerMetrics,
+		Config:         configStore,
+		ConfigReloaded: reloader.subscribe(),
+		Recorder:       mgr.GetEventRecorder(reconciler.EventRecorderName),
+	}
+	if err := clusterPreferenceReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup ClusterPreference reconciler")
+		os.Exit(1)
+	}
+	setupLog.Info("ClusterPreference reconciler configured for cluster-wide preferences",
+		"preferences", len(cfg.Preferences.ClusterPreferences))
+
+	// Register the validating webhook for veneer.io/preference.N annotations
+	if cfg.Webhook.Enabled {
+		mgr.GetWebhookServer().Register(webhook.NodePoolValidatorPath, &ctrlwebhook.Admission{
+			Handler: &webhook.NodePoolValidator{
+				Config: configStore,
+				Logger: ctrl.Log.WithName("nodepool-webhook"),
+			},
+		})
+		setupLog.Info("NodePool preference validating webhook registered",
+			"path", webhook.NodePoolValidatorPath,
+			"port", cfg.Webhook.Port,
+			"fail-open", cfg.Webhook.FailOpen,
+			"warn-only", cfg.Webhook.WarnOnly)
+	}
+
+	// Watch the config file, so config changes apply without a restart. Every replica
+	// watches, since the webhook serves from non-leaders too.
+	if configFileLoaded {
+		if err := config.Watch(configFile, reloader.reload); err != nil {
+			setupLog.Error(err, "unable to watch config file", "config-file", configFile)
+			os.Exit(1)
+		}
+		setupLog.Info("watching config file for changes", "config-file", configFile)
+	}
+
 	// Setup health checks
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -219,10 +392,122 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if cfg.Webhook.Enabled {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// newConfigStore returns the config store for cfg, with the VeneerConfig applied if one exists.
+// Failing to read it isn't fatal: the VeneerConfig reconciler applies it once it can, though
+// settings only read at startup then need a restart.
+func newConfigStore(
+	ctx context.Context,
+	restConfig *rest.Config,
+	cfg *config.Config,
+	overlayDisabled bool,
+) *config.Store {
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client for reading the VeneerConfig")
+		return config.NewStore(cfg)
+	}
+
+	var vconfig veneerv1alpha1.VeneerConfig
+	err = c.Get(ctx, client.ObjectKey{Name: veneerv1alpha1.VeneerConfigName}, &vconfig)
+	switch {
+	case meta.IsNoMatchError(err), apierrors.IsNotFound(err):
+		return config.NewStore(cfg)
+	case err != nil:
+		setupLog.Error(err, "unable to read the VeneerConfig, starting with the config file")
+		return config.NewStore(cfg)
+	}
+
+	store, err := config.NewStoreWithOverrides(cfg, reconciler.VeneerConfigOverrides(&vconfig.Spec, overlayDisabled))
+	if err != nil {
+		setupLog.Error(err, "invalid VeneerConfig, starting with the config file")
+		return config.NewStore(cfg)
+	}
+	setupLog.Info("applied VeneerConfig", "name", vconfig.Name)
+	return store
+}
+
+// configReloader applies configuration changes, from config file reloads and the VeneerConfig,
+// to the running controller.
+type configReloader struct {
+	store *config.Store
+
+	// overlayDisabledFlag is the --overlay-disabled flag, which overrides every reloaded config.
+	// The VeneerConfig reconciler applies it to VeneerConfig changes.
+	overlayDisabledFlag bool
+
+	// overlayGenerator and preferenceGenerator get the reloaded overlays.disabled setting.
+	overlayGenerator    *overlay.Generator
+	preferenceGenerator *preference.Generator
+
+	metrics *metrics.Metrics
+	log     logr.Logger
+
+	// subscribers are notified of every applied reload.
+	subscribers []chan event.TypedGenericEvent[*config.Config]
+}
+
+// subscribe returns a channel that receives an event for every applied reload. Events are
+// dropped while one is already pending, since a single reconcile picks up every reload.
+func (r *configReloader) subscribe() <-chan event.TypedGenericEvent[*config.Config] {
+	ch := make(chan event.TypedGenericEvent[*config.Config], 1)
+	r.subscribers = append(r.subscribers, ch)
+	return ch
+}
+
+// reload applies a reloaded config file. Invalid configs are rejected and the current one kept.
+func (r *configReloader) reload(next *config.Config, err error) {
+	if err == nil {
+		if r.overlayDisabledFlag {
+			next.Overlays.Disabled = true
+		}
+		var change config.Change
+		change, err = r.store.Update(next)
+		if err == nil {
+			r.apply(change)
+			return
+		}
+	}
+	r.log.Error(err, "Rejected reloaded configuration, keeping the current configuration")
+	r.metrics.RecordConfigReload(metrics.ResultError)
+}
+
+// apply propagates an applied config change to the components that don't read the store.
+// The VeneerConfig reconciler calls it for VeneerConfig changes.
+func (r *configReloader) apply(change config.Change) {
+	r.metrics.RecordConfigReload(metrics.ResultSuccess)
+	if len(change.RestartRequired) > 0 {
+		r.log.Info("Some configuration changes only take effect after a restart",
+			"fields", change.RestartRequired)
+	}
+	if len(change.Applied) == 0 {
+		r.log.V(1).Info("Reloaded configuration has no applicable changes")
+		return
+	}
+
+	cfg := r.store.Current()
+	r.log.Info("Reloaded configuration", "changed", change.Applied)
+	r.overlayGenerator.SetDisabled(cfg.Overlays.Disabled)
+	r.preferenceGenerator.SetDisabled(cfg.Overlays.Disabled)
+	r.metrics.SetConfigMetrics(cfg.Overlays.Disabled, cfg.Overlays.UtilizationThreshold)
+
+	for _, ch := range r.subscribers {
+		select {
+		case ch <- event.TypedGenericEvent[*config.Config]{Object: cfg}:
+		default:
+		}
+	}
+}
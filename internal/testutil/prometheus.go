@@ -523,6 +523,36 @@ func LuminaMetricsWithSPCapacity() MetricFixture {
 	}
 }
 
+// LuminaMetricsWithRegionalRI returns Reserved Instance metrics for one regional m5.xlarge RI,
+// which applies to matching instances in any AZ of us-west-2 and is size-flexible.
+// Scenario: the RI has no availability_zone, unlike the zonal RI in LuminaMetricsWithSPCapacity.
+//
+// Load it after LuminaMetricsWithSPCapacity to replace that fixture's zonal RI.
+func LuminaMetricsWithRegionalRI() MetricFixture {
+	response := `{
+		"status": "success",
+		"data": {
+			"resultType": "vector",
+			"result": [
+				{
+					"metric": {
+						"account_id": "123456789012",
+						"region": "us-west-2",
+						"instance_type": "m5.xlarge"
+					},
+					"value": [1640000000, "1"]
+				}
+			]
+		}
+	}`
+	return MetricFixture{
+		`ec2_reserved_instance`:                                                                           response,
+		`ec2_reserved_instance{instance_type="m5.xlarge"}`:                                                response,
+		`ec2_reserved_instance{account_id="123456789012", region="us-west-2"}`:                            response,
+		`ec2_reserved_instance{account_id="123456789012", region="us-west-2", instance_type="m5.xlarge"}`: response,
+	}
+}
+
 // LuminaMetricsWithNoCapacity returns metrics showing exhausted Savings Plans capacity.
 // Scenario: All SP capacity is fully utilized (0 remaining).
 //
@@ -704,11 +734,37 @@ func LuminaMetricsEmpty() MetricFixture {
 }
 
 // LuminaMetricsWithSpotPrices returns metrics including spot pricing data.
-// Scenario: Spot prices available for m5 family instances.
+// Scenario: Spot prices available for m5 family instances, collected 30 seconds ago.
 //
 // Use this fixture when testing cost comparison logic (spot vs RI/SP).
 func LuminaMetricsWithSpotPrices() MetricFixture {
 	return MetricFixture{
+		// Pricing data freshness
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="pricing"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [
+					{
+						"metric": {"account_id": "123456789012", "data_type": "pricing"},
+						"value": [1640000000, "30"]
+					}
+				]
+			}
+		}`,
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="spot-pricing"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [
+					{
+						"metric": {"account_id": "123456789012", "data_type": "spot-pricing"},
+						"value": [1640000000, "30"]
+					}
+				]
+			}
+		}`,
+
 		// Spot pricing for m5.xlarge with selector
 		`ec2_spot_price{instance_type="m5.xlarge"}`: `{
 			"status": "success",
@@ -763,6 +819,32 @@ func LuminaMetricsWithSpotPrices() MetricFixture {
 			}
 		}`,
 
+		// On-demand pricing (all instance types)
+		`ec2_ondemand_price`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [
+					{
+						"metric": {
+							"instance_type": "m5.xlarge",
+							"region": "us-west-2",
+							"operating_system": "Linux"
+						},
+						"value": [1640000000, "0.192"]
+					},
+					{
+						"metric": {
+							"instance_type": "m5.xlarge",
+							"region": "us-west-2",
+							"operating_system": "Windows"
+						},
+						"value": [1640000000, "0.376"]
+					}
+				]
+			}
+		}`,
+
 		// On-demand pricing (old format)
 		`ec2_ondemand_price{instance_type="m5.xlarge",region="us-west-2"}`: `{
 			"status": "success",
@@ -0,0 +1,400 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VeneerConfigName is the name of the VeneerConfig the controller reads. Other names are
+// rejected, since a cluster has a single Veneer configuration.
+const VeneerConfigName = "default"
+
+// ReasonApplied means the VeneerConfig's settings are part of the configuration in effect.
+const ReasonApplied = "Applied"
+
+// VeneerConfigSpec defines the desired state of VeneerConfig.
+//
+// It mirrors the controller's config file. Every field is optional: unset fields keep the
+// value from the config file (or its defaults and VENEER_* environment variables), and set
+// fields override it. The metrics and health probe bind addresses are process settings and
+// stay in the config file.
+type VeneerConfigSpec struct {
+	// PrometheusURL is the URL of the Prometheus server to query for Lumina metrics.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	PrometheusURL *string `json:"prometheusUrl,omitempty"`
+
+	// LogLevel controls the verbosity of logs.
+	// +optional
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	LogLevel *string `json:"logLevel,omitempty"`
+
+	// AWS identifies the account and region the cluster runs in.
+	// +optional
+	AWS *AWSSettings `json:"aws,omitempty"`
+
+	// Reconcile configures how often Lumina data is analyzed and how old it may be.
+	// +optional
+	Reconcile *ReconcileSettings `json:"reconcile,omitempty"`
+
+	// Overlays configures NodeOverlay lifecycle behavior.
+	// +optional
+	Overlays *OverlaySettings `json:"overlays,omitempty"`
+
+	// Preferences configures instance preference overlay behavior.
+	// +optional
+	Preferences *PreferenceSettings `json:"preferences,omitempty"`
+
+	// Webhook configures the validating admission webhook for NodePool preference annotations.
+	// +optional
+	Webhook *WebhookSettings `json:"webhook,omitempty"`
+}
+
+// AWSSettings identifies the AWS account and region the cluster runs in.
+type AWSSettings struct {
+	// AccountID is the 12-digit AWS account ID.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]{12}$`
+	AccountID *string `json:"accountId,omitempty"`
+
+	// Region is the AWS region, e.g. us-west-2.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	Region *string `json:"region,omitempty"`
+}
+
+// ReconcileSettings configures the metrics reconciliation loop.
+type ReconcileSettings struct {
+	// Interval is the time between metrics reconciliation cycles, e.g. 5m. 0 uses the default.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// PollInterval is the time between polls of Lumina data freshness, e.g. 30s. A reconcile
+	// runs as soon as a poll sees new data. 0 uses the default.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+	// MaxFreshness is the maximum age of each type of Lumina data before it's considered stale.
+	// +optional
+	MaxFreshness *MaxFreshnessSettings `json:"maxFreshness,omitempty"`
+}
+
+// MaxFreshnessSettings defines the maximum age of each type of Lumina data, e.g. 65m.
+// 0 uses the default.
+type MaxFreshnessSettings struct {
+	// SavingsPlans is the limit for Savings Plan data.
+	// +optional
+	SavingsPlans *metav1.Duration `json:"savingsPlans,omitempty"`
+
+	// ReservedInstances is the limit for Reserved Instance data.
+	// +optional
+	ReservedInstances *metav1.Duration `json:"reservedInstances,omitempty"`
+
+	// SpotPricing is the limit for spot price data.
+	// +optional
+	SpotPricing *metav1.Duration `json:"spotPricing,omitempty"`
+
+	// Pricing is the limit for on-demand price data.
+	// +optional
+	Pricing *metav1.Duration `json:"pricing,omitempty"`
+}
+
+// OverlaySettings configures NodeOverlay lifecycle behavior.
+// +kubebuilder:validation:XValidation:rule="!has(self.utilizationCreateThreshold) || !has(self.utilizationDeleteThreshold) || self.utilizationCreateThreshold <= self.utilizationDeleteThreshold",message="utilizationCreateThreshold must not exceed utilizationDeleteThreshold"
+type OverlaySettings struct {
+	// Disabled creates overlays with an impossible requirement, so they don't affect
+	// Karpenter's provisioning decisions.
+	// +optional
+	Disabled *bool `json:"disabled,omitempty"`
+
+	// UtilizationThreshold is the SP/RI utilization percentage at which overlays are deleted.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	UtilizationThreshold *float64 `json:"utilizationThreshold,omitempty"`
+
+	// UtilizationCreateThreshold is the utilization percentage below which overlays are created.
+	// Unset uses UtilizationThreshold.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	UtilizationCreateThreshold *float64 `json:"utilizationCreateThreshold,omitempty"`
+
+	// UtilizationDeleteThreshold is the utilization percentage at or above which overlays are
+	// deleted. Unset uses UtilizationThreshold.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	UtilizationDeleteThreshold *float64 `json:"utilizationDeleteThreshold,omitempty"`
+
+	// MinStateDuration is the minimum time an overlay must stay created or deleted before
+	// Veneer flips it again, e.g. 15m. 0 disables it.
+	// +optional
+	MinStateDuration *metav1.Duration `json:"minStateDuration,omitempty"`
+
+	// Weights controls overlay precedence when multiple overlays target the same instances.
+	// +optional
+	Weights *OverlayWeights `json:"weights,omitempty"`
+
+	// Naming controls overlay name prefixes.
+	// +optional
+	Naming *OverlayNaming `json:"naming,omitempty"`
+
+	// Discounts are the discount percentages advertised for covered instances.
+	// +optional
+	Discounts *OverlayDiscounts `json:"discounts,omitempty"`
+
+	// ReservedInstanceMatching controls how regional Reserved Instances are matched to instances.
+	// +optional
+	// +kubebuilder:validation:Enum=exact;size-flexible
+	ReservedInstanceMatching *string `json:"reservedInstanceMatching,omitempty"`
+
+	// ForceConflicts makes Veneer take ownership of NodeOverlay fields another field manager
+	// also sets.
+	// +optional
+	ForceConflicts *bool `json:"forceConflicts,omitempty"`
+}
+
+// OverlayWeights defines the NodeOverlay weight of each capacity type.
+type OverlayWeights struct {
+	// ReservedInstance is the weight of RI-backed overlays.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ReservedInstance *int32 `json:"reservedInstance,omitempty"`
+
+	// EC2InstanceSavingsPlan is the weight of EC2 Instance SP overlays.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	EC2InstanceSavingsPlan *int32 `json:"ec2InstanceSavingsPlan,omitempty"`
+
+	// ComputeSavingsPlan is the weight of Compute SP overlays.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ComputeSavingsPlan *int32 `json:"computeSavingsPlan,omitempty"`
+}
+
+// OverlayNaming defines the NodeOverlay name prefix of each capacity type.
+type OverlayNaming struct {
+	// ReservedInstancePrefix is the prefix for RI-backed overlay names.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ReservedInstancePrefix *string `json:"reservedInstancePrefix,omitempty"`
+
+	// EC2InstanceSavingsPlanPrefix is the prefix for EC2 Instance SP overlay names.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	EC2InstanceSavingsPlanPrefix *string `json:"ec2InstanceSavingsPlanPrefix,omitempty"`
+
+	// ComputeSavingsPlanPrefix is the prefix for Compute SP overlay names.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ComputeSavingsPlanPrefix *string `json:"computeSavingsPlanPrefix,omitempty"`
+}
+
+// OverlayDiscounts defines the discount percentage of each capacity type.
+// Unset discounts use the defaults; 0 advertises covered instances at their on-demand price.
+type OverlayDiscounts struct {
+	// ReservedInstance is the discount percentage for RI-covered instances.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ReservedInstance *float64 `json:"reservedInstance,omitempty"`
+
+	// EC2InstanceSavingsPlan is the discount percentage for EC2 Instance SP-covered instances.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	EC2InstanceSavingsPlan *float64 `json:"ec2InstanceSavingsPlan,omitempty"`
+
+	// ComputeSavingsPlan is the discount percentage for Compute SP-covered instances.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ComputeSavingsPlan *float64 `json:"computeSavingsPlan,omitempty"`
+}
+
+// PreferenceSettings configures instance preference overlay behavior.
+// +kubebuilder:validation:XValidation:rule="!has(self.minAdjustment) || !has(self.maxAdjustment) || self.minAdjustment <= self.maxAdjustment",message="minAdjustment must not exceed maxAdjustment"
+type PreferenceSettings struct {
+	// Enabled controls whether preference-based overlays are processed.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinAdjustment is the lowest price adjustment percentage a preference may use.
+	// Unset uses the default, -100.
+	// +optional
+	// +kubebuilder:validation:Minimum=-100
+	MinAdjustment *float64 `json:"minAdjustment,omitempty"`
+
+	// MaxAdjustment is the highest price adjustment percentage a preference may use.
+	// Unset uses the default, 100. 0 allows no markups.
+	// +optional
+	MaxAdjustment *float64 `json:"maxAdjustment,omitempty"`
+
+	// MaxAbsoluteAdjustment is the largest dollar amount per hour an absolute adjustment may
+	// change the price by, and the highest fixed price a preference may set.
+	// Unset uses the default, 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxAbsoluteAdjustment *float64 `json:"maxAbsoluteAdjustment,omitempty"`
+
+	// ClusterPreferences are cluster-wide preferences applied to every NodePool matching a
+	// label selector. When set, they replace the config file's list; an empty list removes them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ClusterPreferences []ClusterPreference `json:"clusterPreferences,omitempty"`
+
+	// SupportedLabels is the set of label keys preference matchers may use. When set, it
+	// replaces the config file's list; an empty list uses the default.
+	// +optional
+	// +listType=set
+	SupportedLabels []string `json:"supportedLabels,omitempty"`
+}
+
+// ClusterPreference is a cluster-wide preference targeting NodePools by label selector.
+type ClusterPreference struct {
+	// Name identifies the preference. The generated overlay is named cluster-pref-{name}.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=240
+	// +kubebuilder:validation:Pattern=`^[-a-z0-9]*[a-z0-9](\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	Name string `json:"name"`
+
+	// NodePoolSelector is a label selector string (e.g., "team=platform,tier!=batch")
+	// choosing the NodePools the preference applies to. Empty selects every NodePool.
+	// +optional
+	NodePoolSelector string `json:"nodePoolSelector,omitempty"`
+
+	// Preference uses the veneer.io/preference.N annotation value syntax,
+	// e.g., "karpenter.k8s.aws/instance-family=m7g,c7g adjust=-20%".
+	// +kubebuilder:validation:MinLength=1
+	Preference string `json:"preference"`
+
+	// Weight is the weight of the generated NodeOverlay. 0 uses the default, 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// WebhookSettings configures the validating admission webhook.
+type WebhookSettings struct {
+	// Enabled controls whether the controller serves the validating webhook.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Port is the port the webhook server listens on.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// CertDir is the directory containing the webhook server's tls.crt and tls.key.
+	// +optional
+	CertDir *string `json:"certDir,omitempty"`
+
+	// FailOpen admits requests the webhook can't evaluate, with a warning.
+	// +optional
+	FailOpen *bool `json:"failOpen,omitempty"`
+
+	// WarnOnly admits NodePools with invalid preference annotations and returns the problems
+	// as admission warnings instead of rejecting them.
+	// +optional
+	WarnOnly *bool `json:"warnOnly,omitempty"`
+}
+
+// DataFreshness reports the age of one type of Lumina data.
+type DataFreshness struct {
+	// Type is the Lumina data type, e.g. savings_plans.
+	Type string `json:"type"`
+
+	// CollectedAt is when Lumina collected the data.
+	CollectedAt metav1.Time `json:"collectedAt"`
+
+	// Stale is true when the data was too old to analyze at the last reconcile.
+	Stale bool `json:"stale"`
+}
+
+// VeneerConfigStatus defines the observed state of VeneerConfig.
+type VeneerConfigStatus struct {
+	// ObservedGeneration is the generation of the spec the status was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Effective is the configuration in effect: the config file merged with the spec.
+	// +optional
+	Effective *VeneerConfigSpec `json:"effective,omitempty"`
+
+	// RestartRequired lists the changed settings that only take effect after the controller
+	// restarts, named as in the config file (e.g. aws.region).
+	// +optional
+	RestartRequired []string `json:"restartRequired,omitempty"`
+
+	// LastReconcileTime is when the controller last finished analyzing Lumina data.
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// DataFreshness reports the age of each type of Lumina data at the last reconcile.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	DataFreshness []DataFreshness `json:"dataFreshness,omitempty"`
+
+	// OverlayCount is the number of NodeOverlays managed by Veneer.
+	// +optional
+	OverlayCount int32 `json:"overlayCount"`
+
+	// Conditions describe the state of the configuration.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=vconfig
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="the VeneerConfig must be named default"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Overlays",type=integer,JSONPath=`.status.overlayCount`
+// +kubebuilder:printcolumn:name="Last Reconcile",type=date,JSONPath=`.status.lastReconcileTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// VeneerConfig configures the Veneer controller from inside the cluster, as an alternative
+// to the config file. The controller reads the VeneerConfig named default and merges its spec
+// over the config file; its status reports the configuration in effect and the state of the
+// last reconcile.
+type VeneerConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VeneerConfigSpec   `json:"spec,omitempty"`
+	Status VeneerConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VeneerConfigList contains a list of VeneerConfig.
+type VeneerConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VeneerConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VeneerConfig{}, &VeneerConfigList{})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VeneerPreference condition types and reasons.
+const (
+	// ConditionTypeReady is true when the preference's NodeOverlays match its spec.
+	ConditionTypeReady = "Ready"
+
+	// ReasonSynced means every NodeOverlay for the preference was written.
+	ReasonSynced = "Synced"
+
+	// ReasonInvalidSpec means the spec failed validation, so no NodeOverlays are generated.
+	ReasonInvalidSpec = "InvalidSpec"
+
+	// ReasonSyncFailed means some NodeOverlays for the preference could not be written.
+	ReasonSyncFailed = "SyncFailed"
+
+	// ReasonPreferencesDisabled means preferences are disabled in the Veneer configuration,
+	// so no NodeOverlays are generated.
+	ReasonPreferencesDisabled = "PreferencesDisabled"
+)
+
+// LabelMatcher selects instances by a well-known Karpenter label, in the same way as a
+// matcher in a veneer.io/preference.N annotation.
+type LabelMatcher struct {
+	// Key is the label key to match against, e.g. karpenter.k8s.aws/instance-family.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Operator is the comparison operator.
+	// +kubebuilder:validation:Enum=In;NotIn;Gt;Lt;Exists;DoesNotExist
+	Operator string `json:"operator"`
+
+	// Values are the values to compare against. Gt and Lt take exactly one integer value,
+	// and Exists and DoesNotExist take none.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// VeneerPreferenceSpec defines the desired state of VeneerPreference.
+type VeneerPreferenceSpec struct {
+	// NodePoolSelector selects the NodePools the preference applies to. An empty selector
+	// selects every NodePool.
+	NodePoolSelector metav1.LabelSelector `json:"nodePoolSelector"`
+
+	// Requirements select the instances whose price is adjusted. All requirements must match.
+	// +kubebuilder:validation:MinItems=1
+	Requirements []LabelMatcher `json:"requirements"`
+
+	// Adjustment is the price adjustment percentage. Negative values (e.g., -20) make
+	// matching instances cheaper, so Karpenter prefers them.
+	// +kubebuilder:validation:Minimum=-100
+	// +kubebuilder:validation:Maximum=100
+	Adjustment int32 `json:"adjustment"`
+
+	// Weight is the weight of the generated NodeOverlays. Keep it below 10 so cost-aware
+	// overlays take precedence. Karpenter rejects NodeOverlay weights above 10000.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	Weight int32 `json:"weight"`
+}
+
+// GeneratedOverlay identifies a NodeOverlay generated for a VeneerPreference.
+type GeneratedOverlay struct {
+	// Name is the name of the NodeOverlay.
+	Name string `json:"name"`
+
+	// NodePool is the NodePool the overlay is scoped to.
+	NodePool string `json:"nodePool"`
+}
+
+// VeneerPreferenceStatus defines the observed state of VeneerPreference.
+type VeneerPreferenceStatus struct {
+	// ObservedGeneration is the generation of the spec the status was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Overlays are the NodeOverlays generated for the preference, one per selected NodePool.
+	// +optional
+	Overlays []GeneratedOverlay `json:"overlays,omitempty"`
+
+	// Conditions describe the state of the preference.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=vpref
+// +kubebuilder:printcolumn:name="Adjustment",type=integer,JSONPath=`.spec.adjustment`
+// +kubebuilder:printcolumn:name="Weight",type=integer,JSONPath=`.spec.weight`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// VeneerPreference adjusts the price of matching instances in the selected NodePools.
+// It is a structured alternative to veneer.io/preference.N NodePool annotations; Veneer
+// generates one NodeOverlay per selected NodePool.
+type VeneerPreference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VeneerPreferenceSpec   `json:"spec,omitempty"`
+	Status VeneerPreferenceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VeneerPreferenceList contains a list of VeneerPreference.
+type VeneerPreferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VeneerPreference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VeneerPreference{}, &VeneerPreferenceList{})
+}
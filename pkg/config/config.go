@@ -22,8 +22,13 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
 )
 
 // Configuration key constants for viper SetDefault and BindEnv calls.
@@ -32,17 +37,40 @@ const (
 	KeyLogLevel                            = "logLevel"
 	KeyMetricsBindAddress                  = "metricsBindAddress"
 	KeyHealthProbeBindAddress              = "healthProbeBindAddress"
+	KeyTriggerBindAddress                  = "triggerBindAddress"
 	KeyAWSAccountID                        = "aws.accountId"
 	KeyAWSRegion                           = "aws.region"
+	KeyReconcileInterval                   = "reconcile.interval"
+	KeyReconcilePollInterval               = "reconcile.pollInterval"
+	KeyMaxFreshnessSavingsPlans            = "reconcile.maxFreshness.savingsPlans"
+	KeyMaxFreshnessReservedInstances       = "reconcile.maxFreshness.reservedInstances"
+	KeyMaxFreshnessSpotPricing             = "reconcile.maxFreshness.spotPricing"
+	KeyMaxFreshnessPricing                 = "reconcile.maxFreshness.pricing"
 	KeyOverlayDisabled                     = "overlays.disabled"
 	KeyOverlayUtilizationThreshold         = "overlays.utilizationThreshold"
+	KeyOverlayUtilizationCreateThreshold   = "overlays.utilizationCreateThreshold"
+	KeyOverlayUtilizationDeleteThreshold   = "overlays.utilizationDeleteThreshold"
+	KeyOverlayMinStateDuration             = "overlays.minStateDuration"
 	KeyOverlayWeightReservedInstance       = "overlays.weights.reservedInstance"
 	KeyOverlayWeightEC2InstanceSavingsPlan = "overlays.weights.ec2InstanceSavingsPlan"
 	KeyOverlayWeightComputeSavingsPlan     = "overlays.weights.computeSavingsPlan"
 	KeyOverlayNamingReservedInstancePrefix = "overlays.naming.reservedInstancePrefix"
 	KeyOverlayNamingEC2InstanceSPPrefix    = "overlays.naming.ec2InstanceSavingsPlanPrefix"
 	KeyOverlayNamingComputeSPPrefix        = "overlays.naming.computeSavingsPlanPrefix"
+	KeyOverlayDiscountReservedInstance     = "overlays.discounts.reservedInstance"
+	KeyOverlayDiscountEC2InstanceSP        = "overlays.discounts.ec2InstanceSavingsPlan"
+	KeyOverlayDiscountComputeSP            = "overlays.discounts.computeSavingsPlan"
+	KeyOverlayReservedInstanceMatching     = "overlays.reservedInstanceMatching"
+	KeyOverlayForceConflicts               = "overlays.forceConflicts"
 	KeyPreferencesEnabled                  = "preferences.enabled"
+	KeyPreferencesMinAdjustment            = "preferences.minAdjustment"
+	KeyPreferencesMaxAdjustment            = "preferences.maxAdjustment"
+	KeyPreferencesMaxAbsoluteAdjustment    = "preferences.maxAbsoluteAdjustment"
+	KeyWebhookEnabled                      = "webhook.enabled"
+	KeyWebhookPort                         = "webhook.port"
+	KeyWebhookCertDir                      = "webhook.certDir"
+	KeyWebhookFailOpen                     = "webhook.failOpen"
+	KeyWebhookWarnOnly                     = "webhook.warnOnly"
 )
 
 // Environment variable name constants.
@@ -51,9 +79,16 @@ const (
 	EnvLogLevel               = "VENEER_LOG_LEVEL"
 	EnvMetricsBindAddress     = "VENEER_METRICS_BIND_ADDRESS"
 	EnvHealthProbeBindAddress = "VENEER_HEALTH_PROBE_BIND_ADDRESS"
+	EnvTriggerBindAddress     = "VENEER_TRIGGER_BIND_ADDRESS"
 	EnvAWSAccountID           = "VENEER_AWS_ACCOUNT_ID"
 	EnvAWSRegion              = "VENEER_AWS_REGION"
 	EnvOverlayDisabled        = "VENEER_OVERLAY_DISABLED"
+	EnvReconcileInterval      = "VENEER_RECONCILE_INTERVAL"
+	EnvReconcilePollInterval  = "VENEER_RECONCILE_POLL_INTERVAL"
+	EnvMaxFreshnessSP         = "VENEER_MAX_FRESHNESS_SAVINGS_PLANS"
+	EnvMaxFreshnessRI         = "VENEER_MAX_FRESHNESS_RESERVED_INSTANCES"
+	EnvMaxFreshnessSpot       = "VENEER_MAX_FRESHNESS_SPOT_PRICING"
+	EnvMaxFreshnessPricing    = "VENEER_MAX_FRESHNESS_PRICING"
 	EnvPrefix                 = "VENEER"
 )
 
@@ -63,14 +98,31 @@ const (
 	DefaultLogLevel                            = "info"
 	DefaultMetricsBindAddress                  = ":8080"
 	DefaultHealthProbeBindAddress              = ":8081"
+	DefaultReconcileInterval                   = 5 * time.Minute         // Time between metrics reconciliation cycles
+	DefaultReconcilePollInterval               = 30 * time.Second        // Time between Lumina data freshness polls
+	DefaultMaxFreshnessSavingsPlans            = 65 * time.Minute        // Lumina refreshes hourly, plus a buffer
+	DefaultMaxFreshnessReservedInstances       = 65 * time.Minute        // Lumina refreshes hourly, plus a buffer
+	DefaultMaxFreshnessSpotPricing             = 5 * time.Minute         // Lumina refreshes every 15 seconds
+	DefaultMaxFreshnessPricing                 = 25 * time.Hour          // Lumina refreshes every ~24 hours
 	DefaultOverlayUtilizationThreshold         = 95.0                    // Delete overlays at 95% utilization
+	DefaultOverlayMinStateDuration             = time.Duration(0)        // Disabled: overlays follow utilization
 	DefaultOverlayWeightReservedInstance       = 30                      // Highest priority (most specific)
 	DefaultOverlayWeightEC2InstanceSavingsPlan = 20                      // Medium priority (family-specific)
 	DefaultOverlayWeightComputeSavingsPlan     = 10                      // Lowest priority (global)
 	DefaultOverlayNamingReservedInstancePrefix = "cost-aware-ri"         // RI overlay name prefix
 	DefaultOverlayNamingEC2InstanceSPPrefix    = "cost-aware-ec2-sp"     // EC2 Instance SP overlay name prefix
 	DefaultOverlayNamingComputeSPPrefix        = "cost-aware-compute-sp" // Compute SP overlay name prefix
+	DefaultOverlayDiscountReservedInstance     = 37.0                    // Typical 1-year no-upfront standard RI discount
+	DefaultOverlayDiscountEC2InstanceSP        = 37.0                    // Typical 1-year no-upfront EC2 Instance SP discount
+	DefaultOverlayDiscountComputeSP            = 28.0                    // Typical 1-year no-upfront Compute SP discount
+	DefaultOverlayReservedInstanceMatching     = "exact"                 // Match RIs by exact instance type
 	DefaultPreferencesEnabled                  = true                    // Instance preferences enabled by default
+	DefaultPreferencesMinAdjustment            = -100.0                  // Largest discount: price can't go below zero
+	DefaultPreferencesMaxAdjustment            = 100.0                   // Largest markup: double the price
+	DefaultPreferencesMaxAbsoluteAdjustment    = 10.0                    // Largest dollar adjustment or fixed price, in $/hour
+	DefaultClusterPreferenceWeight             = 1                       // Lowest preference overlay weight
+	DefaultWebhookPort                         = 9443                    // controller-runtime webhook server default
+	DefaultWebhookFailOpen                     = true                    // Never block NodePool changes on webhook errors
 )
 
 // Config represents the complete controller configuration.
@@ -88,14 +140,24 @@ type Config struct {
 	// HealthProbeBindAddress is the address the health probe endpoint binds to.
 	HealthProbeBindAddress string `yaml:"healthProbeBindAddress,omitempty"`
 
+	// TriggerBindAddress is the address the endpoint that forces a metrics reconcile binds to.
+	// The endpoint is unauthenticated, so it is disabled by default (empty).
+	TriggerBindAddress string `yaml:"triggerBindAddress,omitempty"`
+
 	// AWS contains AWS-specific configuration for the cluster context.
 	AWS AWSConfig `yaml:"aws,omitempty"`
 
+	// Reconcile configures how often Lumina data is analyzed and how old it may be.
+	Reconcile ReconcileConfig `yaml:"reconcile,omitempty"`
+
 	// Overlays configures NodeOverlay lifecycle behavior.
 	Overlays OverlayManagementConfig `yaml:"overlays,omitempty"`
 
 	// Preferences configures instance preference overlay behavior.
 	Preferences PreferencesConfig `yaml:"preferences,omitempty"`
+
+	// Webhook configures the validating admission webhook for NodePool preference annotations.
+	Webhook WebhookConfig `yaml:"webhook,omitempty"`
 }
 
 // PreferencesConfig controls preference-based NodeOverlay generation.
@@ -110,6 +172,115 @@ type PreferencesConfig struct {
 	//
 	// Default: true (preferences are enabled)
 	Enabled bool `yaml:"enabled,omitempty"`
+
+	// MinAdjustment is the lowest price adjustment percentage a preference may use.
+	// Preferences below it are rejected like any other invalid annotation.
+	//
+	// Default: -100 (unset uses the default)
+	// Valid range: -100 to MaxAdjustment
+	MinAdjustment *float64 `yaml:"minAdjustment,omitempty"`
+
+	// MaxAdjustment is the highest price adjustment percentage a preference may use.
+	// 0 allows no markups.
+	//
+	// Default: 100 (unset uses the default)
+	MaxAdjustment *float64 `yaml:"maxAdjustment,omitempty"`
+
+	// MaxAbsoluteAdjustment is the largest dollar amount per hour an absolute adjustment
+	// (adjust=[+-]N) may change the price by, in either direction, and the highest fixed
+	// price (price=N) a preference may set. It catches percentages missing their "%".
+	// Raise it for instance types whose prices exceed it.
+	//
+	// Default: 10 (unset uses the default)
+	MaxAbsoluteAdjustment *float64 `yaml:"maxAbsoluteAdjustment,omitempty"`
+
+	// ClusterPreferences are cluster-wide preferences applied to every NodePool matching a
+	// label selector, instead of being copied into each NodePool's annotations.
+	// Veneer generates one NodeOverlay per cluster preference, scoped to all matching NodePools.
+	ClusterPreferences []ClusterPreference `yaml:"clusterPreferences,omitempty"`
+
+	// SupportedLabels is the set of label keys preference matchers may use.
+	// Matchers on any other key are rejected, to catch typos.
+	//
+	// Default: the Karpenter AWS well-known labels (empty uses the default)
+	SupportedLabels []string `yaml:"supportedLabels,omitempty"`
+}
+
+// ClusterPreference is a cluster-wide preference targeting NodePools by label selector.
+type ClusterPreference struct {
+	// Name identifies the preference. The generated overlay is named cluster-pref-{name}.
+	// Must be unique and a valid Kubernetes resource name.
+	Name string `yaml:"name"`
+
+	// NodePoolSelector is a Kubernetes label selector (e.g., "team=platform,tier!=batch")
+	// choosing the NodePools the preference applies to. Empty selects every NodePool.
+	NodePoolSelector string `yaml:"nodePoolSelector,omitempty"`
+
+	// Preference uses the veneer.io/preference.N annotation value syntax,
+	// e.g., "karpenter.k8s.aws/instance-family=m7g,c7g adjust=-20%".
+	Preference string `yaml:"preference"`
+
+	// Weight is the weight of the generated NodeOverlay.
+	//
+	// Default: 1 (0 uses the default)
+	Weight int32 `yaml:"weight,omitempty"`
+}
+
+// OverlayWeight returns the effective weight of the preference's NodeOverlay.
+func (p ClusterPreference) OverlayWeight() int32 {
+	if p.Weight == 0 {
+		return DefaultClusterPreferenceWeight
+	}
+	return p.Weight
+}
+
+// AdjustmentRange returns the effective range of preference price adjustments.
+// Unset bounds fall back to their defaults.
+func (p PreferencesConfig) AdjustmentRange() (minAdjustment, maxAdjustment float64) {
+	return ptr.Deref(p.MinAdjustment, DefaultPreferencesMinAdjustment),
+		ptr.Deref(p.MaxAdjustment, DefaultPreferencesMaxAdjustment)
+}
+
+// AbsoluteAdjustmentLimit returns the effective limit on absolute preference adjustments
+// and fixed prices, in dollars per hour.
+func (p PreferencesConfig) AbsoluteAdjustmentLimit() float64 {
+	return ptr.Deref(p.MaxAbsoluteAdjustment, DefaultPreferencesMaxAbsoluteAdjustment)
+}
+
+// WebhookConfig controls the validating admission webhook that checks veneer.io/preference.N
+// annotations when NodePools are created or updated.
+//
+// The webhook server requires a TLS certificate in CertDir, and a ValidatingWebhookConfiguration
+// pointing at it (the Helm chart provides both when the webhook is enabled).
+type WebhookConfig struct {
+	// Enabled controls whether the controller serves the validating webhook.
+	//
+	// Default: false
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Port is the port the webhook server listens on.
+	//
+	// Default: 9443
+	Port int `yaml:"port,omitempty"`
+
+	// CertDir is the directory containing the webhook server's tls.crt and tls.key.
+	//
+	// Default: "" (controller-runtime's default, <temp-dir>/k8s-webhook-server/serving-certs)
+	CertDir string `yaml:"certDir,omitempty"`
+
+	// FailOpen controls what happens when the webhook can't evaluate a request. When true,
+	// the request is admitted with a warning, and the Helm chart sets the webhook's
+	// failurePolicy to Ignore so NodePool changes aren't blocked while Veneer is unavailable.
+	//
+	// Default: true
+	FailOpen bool `yaml:"failOpen,omitempty"`
+
+	// WarnOnly admits NodePools with invalid preference annotations and returns the
+	// problems as admission warnings instead of rejecting them. Useful while rolling out
+	// the webhook to clusters with existing annotations.
+	//
+	// Default: false
+	WarnOnly bool `yaml:"warnOnly,omitempty"`
 }
 
 // AWSConfig contains AWS-specific configuration for scoping Savings Plans and Reserved Instances.
@@ -136,6 +307,94 @@ type AWSConfig struct {
 	Region string `yaml:"region,omitempty"`
 }
 
+// ReconcileConfig controls the metrics reconciliation loop.
+//
+// Between the reconciliation cycles, Veneer polls lumina_data_freshness_seconds and reconciles
+// as soon as Lumina publishes new data, so Interval is only a backstop.
+//
+// Lumina refreshes each type of data at its own rate, reported by the
+// lumina_data_freshness_seconds metric. Data older than its MaxFreshness limit is
+// considered stale and isn't used, so overlays are never changed based on outdated data.
+// Deployments whose Lumina refreshes less often need higher limits.
+type ReconcileConfig struct {
+	// Interval is the time between metrics reconciliation cycles.
+	//
+	// Default: 5m (0 uses the default)
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// PollInterval is the time between polls of Lumina data freshness. A poll only queries
+	// lumina_data_freshness_seconds, and triggers a full reconciliation when the age of the
+	// Savings Plan, Reserved Instance, or on-demand pricing data resets.
+	//
+	// Default: 30s (0 uses the default)
+	PollInterval time.Duration `yaml:"pollInterval,omitempty"`
+
+	// MaxFreshness is the maximum age of each type of Lumina data.
+	MaxFreshness MaxFreshnessConfig `yaml:"maxFreshness,omitempty"`
+}
+
+// MaxFreshnessConfig defines the maximum age of each type of Lumina data before it's
+// considered stale. Unset (zero) limits use their defaults.
+//
+// Lumina's EC2 instance data has no limit because Veneer doesn't use it: on-demand instances
+// are counted from the cluster's Nodes and NodeClaims.
+type MaxFreshnessConfig struct {
+	// SavingsPlans is the limit for Savings Plan data. Stale data skips Savings Plan analysis.
+	// Default: 65m
+	SavingsPlans time.Duration `yaml:"savingsPlans,omitempty"`
+
+	// ReservedInstances is the limit for Reserved Instance data. Stale data skips Reserved
+	// Instance analysis.
+	// Default: 65m
+	ReservedInstances time.Duration `yaml:"reservedInstances,omitempty"`
+
+	// SpotPricing is the limit for spot price data. Stale data skips the spot comparison.
+	// Default: 5m
+	SpotPricing time.Duration `yaml:"spotPricing,omitempty"`
+
+	// Pricing is the limit for on-demand price data. Stale data falls back to price
+	// adjustments and skips the spot comparison.
+	// Default: 25h
+	Pricing time.Duration `yaml:"pricing,omitempty"`
+}
+
+// ReconcileInterval returns the effective time between reconciliation cycles.
+// An unset (zero) interval falls back to the default.
+func (r ReconcileConfig) ReconcileInterval() time.Duration {
+	if r.Interval == 0 {
+		return DefaultReconcileInterval
+	}
+	return r.Interval
+}
+
+// FreshnessPollInterval returns the effective time between Lumina data freshness polls.
+// An unset (zero) interval falls back to the default.
+func (r ReconcileConfig) FreshnessPollInterval() time.Duration {
+	if r.PollInterval == 0 {
+		return DefaultReconcilePollInterval
+	}
+	return r.PollInterval
+}
+
+// Limits returns the effective maximum age of each type of Lumina data.
+// Unset (zero) limits fall back to their defaults.
+func (f MaxFreshnessConfig) Limits() MaxFreshnessConfig {
+	limits := f
+	if limits.SavingsPlans == 0 {
+		limits.SavingsPlans = DefaultMaxFreshnessSavingsPlans
+	}
+	if limits.ReservedInstances == 0 {
+		limits.ReservedInstances = DefaultMaxFreshnessReservedInstances
+	}
+	if limits.SpotPricing == 0 {
+		limits.SpotPricing = DefaultMaxFreshnessSpotPricing
+	}
+	if limits.Pricing == 0 {
+		limits.Pricing = DefaultMaxFreshnessPricing
+	}
+	return limits
+}
+
 // OverlayManagementConfig controls when overlays are created/deleted based on capacity utilization.
 //
 // Overlays are created when Savings Plans or Reserved Instances have available capacity,
@@ -158,6 +417,29 @@ type OverlayManagementConfig struct {
 	// Valid range: 0-100
 	UtilizationThreshold float64 `yaml:"utilizationThreshold,omitempty"`
 
+	// UtilizationCreateThreshold is the utilization percentage below which overlays are created.
+	// Together with UtilizationDeleteThreshold it forms a hysteresis band: while utilization
+	// sits between the two thresholds, an overlay keeps its current state instead of flapping.
+	//
+	// Default: unset (use UtilizationThreshold, i.e. no hysteresis band)
+	// Valid range: 0-100, must not exceed the delete threshold
+	UtilizationCreateThreshold *float64 `yaml:"utilizationCreateThreshold,omitempty"`
+
+	// UtilizationDeleteThreshold is the utilization percentage at or above which overlays are deleted.
+	//
+	// Default: unset (use UtilizationThreshold)
+	// Valid range: 0-100
+	UtilizationDeleteThreshold *float64 `yaml:"utilizationDeleteThreshold,omitempty"`
+
+	// MinStateDuration is the minimum time an overlay must stay created or deleted before
+	// Veneer will flip it again. Tracked across reconcile cycles; after a restart the
+	// overlay's creation timestamp is used for overlays that already exist.
+	// It applies to every cost-aware overlay, including RI overlays, so e.g. 15m
+	// (three default reconcile cycles) keeps a deleted RI overlay away for 15 minutes.
+	//
+	// Default: 0 (disabled)
+	MinStateDuration time.Duration `yaml:"minStateDuration,omitempty"`
+
 	// Weights controls overlay precedence when multiple overlays target the same instances.
 	// Higher weights win. Reserved Instances (most specific) should have highest weight,
 	// followed by EC2 Instance SPs (family-specific), then Compute SPs (global).
@@ -165,6 +447,78 @@ type OverlayManagementConfig struct {
 
 	// Naming controls overlay naming conventions.
 	Naming OverlayNamingConfig `yaml:"naming,omitempty"`
+
+	// Discounts controls the effective price advertised for instances covered by
+	// pre-paid capacity. Covered instances are priced at their on-demand rate minus
+	// the discount, so Karpenter compares real numbers against spot.
+	Discounts OverlayDiscountsConfig `yaml:"discounts,omitempty"`
+
+	// ReservedInstanceMatching controls how regional Reserved Instances are matched to instances.
+	//
+	// "exact" creates one overlay per RI instance type. "size-flexible" aggregates regional RIs
+	// by instance family in AWS normalized units and creates one overlay per family covering
+	// every size that fits within the remaining units, since AWS applies regional Linux RIs
+	// to any size in their family. Zonal RIs are always matched by exact instance type.
+	//
+	// Default: "exact"
+	ReservedInstanceMatching string `yaml:"reservedInstanceMatching,omitempty"`
+
+	// ForceConflicts controls whether Veneer takes ownership of NodeOverlay fields that
+	// another field manager (e.g., a GitOps tool) also sets to a different value.
+	// Veneer writes overlays with server-side apply and only owns the fields it sets;
+	// when false, a conflicting apply is reported and the overlay is left unchanged.
+	//
+	// Default: false
+	ForceConflicts bool `yaml:"forceConflicts,omitempty"`
+}
+
+// Reserved Instance matching modes for OverlayManagementConfig.ReservedInstanceMatching.
+const (
+	ReservedInstanceMatchingExact        = "exact"
+	ReservedInstanceMatchingSizeFlexible = "size-flexible"
+)
+
+// SizeFlexibleReservedInstances reports whether regional RIs are matched by normalized units.
+func (o OverlayManagementConfig) SizeFlexibleReservedInstances() bool {
+	return o.ReservedInstanceMatching == ReservedInstanceMatchingSizeFlexible
+}
+
+// UtilizationThresholds returns the effective create and delete utilization thresholds.
+// Unset thresholds fall back to UtilizationThreshold.
+func (o OverlayManagementConfig) UtilizationThresholds() (create, del float64) {
+	return ptr.Deref(o.UtilizationCreateThreshold, o.UtilizationThreshold),
+		ptr.Deref(o.UtilizationDeleteThreshold, o.UtilizationThreshold)
+}
+
+// OverlayDiscountsConfig defines the discount percentage applied by each capacity type.
+//
+// These should match the rates of the Savings Plans and Reserved Instances actually purchased.
+// A discount of 100 prices covered instances at $0.00/hour, which makes every covered
+// instance look equally free regardless of size and always beats spot. A discount of 0 prices
+// covered instances at their on-demand rate. Unset discounts use the defaults.
+type OverlayDiscountsConfig struct {
+	// ReservedInstance is the discount percentage for RI-covered instances.
+	// Default: 37.0
+	// Valid range: 0-100
+	ReservedInstance *float64 `yaml:"reservedInstance,omitempty"`
+
+	// EC2InstanceSavingsPlan is the discount percentage for EC2 Instance SP-covered instances.
+	// Default: 37.0
+	// Valid range: 0-100
+	EC2InstanceSavingsPlan *float64 `yaml:"ec2InstanceSavingsPlan,omitempty"`
+
+	// ComputeSavingsPlan is the discount percentage for Compute SP-covered instances.
+	// Default: 28.0
+	// Valid range: 0-100
+	ComputeSavingsPlan *float64 `yaml:"computeSavingsPlan,omitempty"`
+}
+
+// Percentages returns the effective discount percentage of each capacity type.
+// Unset discounts fall back to their defaults.
+func (d OverlayDiscountsConfig) Percentages() (reservedInstance, ec2InstanceSavingsPlan, computeSavingsPlan float64) {
+	return ptr.Deref(d.ReservedInstance, DefaultOverlayDiscountReservedInstance),
+		ptr.Deref(d.EC2InstanceSavingsPlan, DefaultOverlayDiscountEC2InstanceSP),
+		ptr.Deref(d.ComputeSavingsPlan, DefaultOverlayDiscountComputeSP)
 }
 
 // OverlayWeightsConfig defines precedence for different capacity types.
@@ -224,14 +578,30 @@ func Load(path string) (*Config, error) {
 	v.SetDefault(KeyLogLevel, DefaultLogLevel)
 	v.SetDefault(KeyMetricsBindAddress, DefaultMetricsBindAddress)
 	v.SetDefault(KeyHealthProbeBindAddress, DefaultHealthProbeBindAddress)
+	v.SetDefault(KeyReconcileInterval, DefaultReconcileInterval)
+	v.SetDefault(KeyReconcilePollInterval, DefaultReconcilePollInterval)
+	v.SetDefault(KeyMaxFreshnessSavingsPlans, DefaultMaxFreshnessSavingsPlans)
+	v.SetDefault(KeyMaxFreshnessReservedInstances, DefaultMaxFreshnessReservedInstances)
+	v.SetDefault(KeyMaxFreshnessSpotPricing, DefaultMaxFreshnessSpotPricing)
+	v.SetDefault(KeyMaxFreshnessPricing, DefaultMaxFreshnessPricing)
 	v.SetDefault(KeyOverlayUtilizationThreshold, DefaultOverlayUtilizationThreshold)
+	v.SetDefault(KeyOverlayMinStateDuration, DefaultOverlayMinStateDuration)
 	v.SetDefault(KeyOverlayWeightReservedInstance, DefaultOverlayWeightReservedInstance)
 	v.SetDefault(KeyOverlayWeightEC2InstanceSavingsPlan, DefaultOverlayWeightEC2InstanceSavingsPlan)
 	v.SetDefault(KeyOverlayWeightComputeSavingsPlan, DefaultOverlayWeightComputeSavingsPlan)
 	v.SetDefault(KeyOverlayNamingReservedInstancePrefix, DefaultOverlayNamingReservedInstancePrefix)
 	v.SetDefault(KeyOverlayNamingEC2InstanceSPPrefix, DefaultOverlayNamingEC2InstanceSPPrefix)
 	v.SetDefault(KeyOverlayNamingComputeSPPrefix, DefaultOverlayNamingComputeSPPrefix)
+	v.SetDefault(KeyOverlayDiscountReservedInstance, DefaultOverlayDiscountReservedInstance)
+	v.SetDefault(KeyOverlayDiscountEC2InstanceSP, DefaultOverlayDiscountEC2InstanceSP)
+	v.SetDefault(KeyOverlayDiscountComputeSP, DefaultOverlayDiscountComputeSP)
+	v.SetDefault(KeyOverlayReservedInstanceMatching, DefaultOverlayReservedInstanceMatching)
 	v.SetDefault(KeyPreferencesEnabled, DefaultPreferencesEnabled)
+	v.SetDefault(KeyPreferencesMinAdjustment, DefaultPreferencesMinAdjustment)
+	v.SetDefault(KeyPreferencesMaxAdjustment, DefaultPreferencesMaxAdjustment)
+	v.SetDefault(KeyPreferencesMaxAbsoluteAdjustment, DefaultPreferencesMaxAbsoluteAdjustment)
+	v.SetDefault(KeyWebhookPort, DefaultWebhookPort)
+	v.SetDefault(KeyWebhookFailOpen, DefaultWebhookFailOpen)
 
 	// Enable environment variable overrides with VENEER_ prefix
 	v.SetEnvPrefix(EnvPrefix)
@@ -239,9 +609,16 @@ func Load(path string) (*Config, error) {
 	_ = v.BindEnv(KeyLogLevel, EnvLogLevel)
 	_ = v.BindEnv(KeyMetricsBindAddress, EnvMetricsBindAddress)
 	_ = v.BindEnv(KeyHealthProbeBindAddress, EnvHealthProbeBindAddress)
+	_ = v.BindEnv(KeyTriggerBindAddress, EnvTriggerBindAddress)
 	_ = v.BindEnv(KeyAWSAccountID, EnvAWSAccountID)
 	_ = v.BindEnv(KeyAWSRegion, EnvAWSRegion)
 	_ = v.BindEnv(KeyOverlayDisabled, EnvOverlayDisabled)
+	_ = v.BindEnv(KeyReconcileInterval, EnvReconcileInterval)
+	_ = v.BindEnv(KeyReconcilePollInterval, EnvReconcilePollInterval)
+	_ = v.BindEnv(KeyMaxFreshnessSavingsPlans, EnvMaxFreshnessSP)
+	_ = v.BindEnv(KeyMaxFreshnessReservedInstances, EnvMaxFreshnessRI)
+	_ = v.BindEnv(KeyMaxFreshnessSpotPricing, EnvMaxFreshnessSpot)
+	_ = v.BindEnv(KeyMaxFreshnessPricing, EnvMaxFreshnessPricing)
 
 	// Read configuration file
 	if err := v.ReadInConfig(); err != nil {
@@ -298,6 +675,17 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log level %q, must be one of: debug, info, warn, error", c.LogLevel)
 	}
 
+	// Validate reconcile interval and freshness limits
+	if c.Reconcile.Interval < 0 {
+		return fmt.Errorf("reconcile interval must be non-negative, got %s", c.Reconcile.Interval)
+	}
+	if c.Reconcile.PollInterval < 0 {
+		return fmt.Errorf("reconcile poll interval must be non-negative, got %s", c.Reconcile.PollInterval)
+	}
+	if err := c.Reconcile.MaxFreshness.validate(); err != nil {
+		return err
+	}
+
 	// Validate overlay configuration
 	if c.Overlays.UtilizationThreshold < 0 || c.Overlays.UtilizationThreshold > 100 {
 		return fmt.Errorf(
@@ -305,6 +693,22 @@ func (c *Config) Validate() error {
 			c.Overlays.UtilizationThreshold,
 		)
 	}
+	create, del := c.Overlays.UtilizationThresholds()
+	if create < 0 || create > 100 {
+		return fmt.Errorf("overlay utilization create threshold must be between 0 and 100, got %f", create)
+	}
+	if del < 0 || del > 100 {
+		return fmt.Errorf("overlay utilization delete threshold must be between 0 and 100, got %f", del)
+	}
+	if create > del {
+		return fmt.Errorf(
+			"overlay utilization create threshold (%f) must not exceed delete threshold (%f)",
+			create, del,
+		)
+	}
+	if c.Overlays.MinStateDuration < 0 {
+		return fmt.Errorf("overlay min state duration must be non-negative, got %s", c.Overlays.MinStateDuration)
+	}
 
 	// Validate weights are positive
 	if c.Overlays.Weights.ReservedInstance < 0 {
@@ -326,5 +730,118 @@ func (c *Config) Validate() error {
 		)
 	}
 
+	// Validate discounts are percentages
+	riDiscount, ec2SPDiscount, computeSPDiscount := c.Overlays.Discounts.Percentages()
+	if riDiscount < 0 || riDiscount > 100 {
+		return fmt.Errorf("reserved instance discount must be between 0 and 100, got %f", riDiscount)
+	}
+	if ec2SPDiscount < 0 || ec2SPDiscount > 100 {
+		return fmt.Errorf("ec2 instance savings plan discount must be between 0 and 100, got %f", ec2SPDiscount)
+	}
+	if computeSPDiscount < 0 || computeSPDiscount > 100 {
+		return fmt.Errorf("compute savings plan discount must be between 0 and 100, got %f", computeSPDiscount)
+	}
+
+	// Validate Reserved Instance matching mode
+	switch c.Overlays.ReservedInstanceMatching {
+	case "", ReservedInstanceMatchingExact, ReservedInstanceMatchingSizeFlexible:
+	default:
+		return fmt.Errorf(
+			"invalid reserved instance matching %q, must be one of: %s, %s",
+			c.Overlays.ReservedInstanceMatching,
+			ReservedInstanceMatchingExact, ReservedInstanceMatchingSizeFlexible,
+		)
+	}
+
+	// Validate preference adjustment range
+	if minAdj, maxAdj := c.Preferences.AdjustmentRange(); minAdj < -100 || minAdj > maxAdj {
+		return fmt.Errorf(
+			"preference adjustment range must satisfy -100 <= minAdjustment <= maxAdjustment, got [%f, %f]",
+			minAdj, maxAdj,
+		)
+	}
+	if limit := c.Preferences.AbsoluteAdjustmentLimit(); limit < 0 {
+		return fmt.Errorf("preferences.maxAbsoluteAdjustment must not be negative, got %f", limit)
+	}
+
+	// Validate preference label keys
+	for i, key := range c.Preferences.SupportedLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("preferences.supportedLabels[%d]: invalid label key %q: %s",
+				i, key, strings.Join(errs, "; "))
+		}
+	}
+
+	// Validate cluster-wide preferences
+	if err := validateClusterPreferences(c.Preferences.ClusterPreferences); err != nil {
+		return err
+	}
+
+	// Validate webhook port
+	if c.Webhook.Port < 0 || c.Webhook.Port > 65535 {
+		return fmt.Errorf("webhook port must be between 0 and 65535, got %d", c.Webhook.Port)
+	}
+
+	return nil
+}
+
+// validate checks that the freshness limits are non-negative.
+func (f MaxFreshnessConfig) validate() error {
+	limits := []struct {
+		key   string
+		value time.Duration
+	}{
+		{KeyMaxFreshnessSavingsPlans, f.SavingsPlans},
+		{KeyMaxFreshnessReservedInstances, f.ReservedInstances},
+		{KeyMaxFreshnessSpotPricing, f.SpotPricing},
+		{KeyMaxFreshnessPricing, f.Pricing},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("%s must be non-negative, got %s", limit.key, limit.value)
+		}
+	}
+	return nil
+}
+
+// MaxClusterPreferenceWeight is the largest cluster preference weight, since Karpenter rejects
+// NodeOverlay weights above it.
+const MaxClusterPreferenceWeight = 10000
+
+// validateClusterPreferences checks that cluster-wide preferences have unique, valid names,
+// parseable NodePool selectors, and weights from 0 to MaxClusterPreferenceWeight. Names must
+// also be valid label values, since they label the generated overlay. Preference values are
+// parsed by the reconciler, which reports them like invalid annotations.
+func validateClusterPreferences(prefs []ClusterPreference) error {
+	seen := make(map[string]bool, len(prefs))
+	for i, pref := range prefs {
+		if pref.Name == "" {
+			return fmt.Errorf("preferences.clusterPreferences[%d]: name is required", i)
+		}
+		if errs := validation.IsDNS1123Subdomain("cluster-pref-" + pref.Name); len(errs) > 0 {
+			return fmt.Errorf("preferences.clusterPreferences[%d]: invalid name %q: %s",
+				i, pref.Name, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(pref.Name); len(errs) > 0 {
+			return fmt.Errorf("preferences.clusterPreferences[%d]: invalid name %q: %s",
+				i, pref.Name, strings.Join(errs, "; "))
+		}
+		if seen[pref.Name] {
+			return fmt.Errorf("preferences.clusterPreferences[%d]: duplicate name %q", i, pref.Name)
+		}
+		seen[pref.Name] = true
+
+		if _, err := labels.Parse(pref.NodePoolSelector); err != nil {
+			return fmt.Errorf("preferences.clusterPreferences[%d] (%s): invalid nodePoolSelector: %w",
+				i, pref.Name, err)
+		}
+		if pref.Preference == "" {
+			return fmt.Errorf("preferences.clusterPreferences[%d] (%s): preference is required", i, pref.Name)
+		}
+		if pref.Weight < 0 || pref.Weight > MaxClusterPreferenceWeight {
+			return fmt.Errorf("preferences.clusterPreferences[%d] (%s): weight must be between 0 and %d, got %d",
+				i, pref.Name, MaxClusterPreferenceWeight, pref.Weight)
+		}
+	}
 	return nil
 }
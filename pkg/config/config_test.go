@@ -19,7 +19,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
 )
 
 func TestLoad(t *testing.T) {
@@ -75,6 +79,33 @@ aws:
 				}
 			},
 		},
+		{
+			name: "zero percentages are kept",
+			configYAML: `
+prometheusUrl: "http://prom:9090"
+aws:
+  accountId: "123456789012"
+  region: "us-east-1"
+overlays:
+  utilizationCreateThreshold: 0
+  discounts:
+    computeSavingsPlan: 0
+preferences:
+  maxAdjustment: 0
+`,
+			wantErr: false,
+			validate: func(t *testing.T, c *Config) {
+				if _, _, computeSP := c.Overlays.Discounts.Percentages(); computeSP != 0 {
+					t.Errorf("ComputeSavingsPlan discount = %f, want 0", computeSP)
+				}
+				if create, _ := c.Overlays.UtilizationThresholds(); create != 0 {
+					t.Errorf("create threshold = %f, want 0", create)
+				}
+				if _, maxAdj := c.Preferences.AdjustmentRange(); maxAdj != 0 {
+					t.Errorf("max adjustment = %f, want 0", maxAdj)
+				}
+			},
+		},
 		{
 			name: "invalid log level",
 			configYAML: `
@@ -331,6 +362,32 @@ aws:
 			DefaultOverlayWeightComputeSavingsPlan,
 		)
 	}
+	if ri, ec2SP, computeSP := cfg.Overlays.Discounts.Percentages(); ri != DefaultOverlayDiscountReservedInstance ||
+		ec2SP != DefaultOverlayDiscountEC2InstanceSP || computeSP != DefaultOverlayDiscountComputeSP {
+		t.Errorf("Discounts.Percentages() = (%f, %f, %f), want (%f, %f, %f)",
+			ri, ec2SP, computeSP,
+			DefaultOverlayDiscountReservedInstance, DefaultOverlayDiscountEC2InstanceSP, DefaultOverlayDiscountComputeSP)
+	}
+	if cfg.Overlays.MinStateDuration != DefaultOverlayMinStateDuration {
+		t.Errorf("MinStateDuration = %s, want %s", cfg.Overlays.MinStateDuration, DefaultOverlayMinStateDuration)
+	}
+	if cfg.Overlays.ReservedInstanceMatching != DefaultOverlayReservedInstanceMatching {
+		t.Errorf(
+			"ReservedInstanceMatching = %q, want %q",
+			cfg.Overlays.ReservedInstanceMatching,
+			DefaultOverlayReservedInstanceMatching,
+		)
+	}
+	if cfg.Overlays.SizeFlexibleReservedInstances() {
+		t.Error("SizeFlexibleReservedInstances() = true, want false by default")
+	}
+	if cfg.Overlays.ForceConflicts {
+		t.Error("ForceConflicts = true, want false by default")
+	}
+	if create, del := cfg.Overlays.UtilizationThresholds(); create != DefaultOverlayUtilizationThreshold ||
+		del != DefaultOverlayUtilizationThreshold {
+		t.Errorf("UtilizationThresholds() = (%f, %f), want both %f", create, del, DefaultOverlayUtilizationThreshold)
+	}
 }
 
 func TestOverlayManagementCustomValues(t *testing.T) {
@@ -344,6 +401,10 @@ aws:
   region: "us-west-2"
 overlays:
   utilizationThreshold: 90.0
+  utilizationCreateThreshold: 85.0
+  minStateDuration: 10m
+  reservedInstanceMatching: size-flexible
+  forceConflicts: true
   weights:
     reservedInstance: 100
     ec2InstanceSavingsPlan: 50
@@ -372,6 +433,19 @@ overlays:
 	if cfg.Overlays.Weights.ComputeSavingsPlan != 25 {
 		t.Errorf("ComputeSavingsPlan weight = %d, want 25", cfg.Overlays.Weights.ComputeSavingsPlan)
 	}
+	if cfg.Overlays.MinStateDuration != 10*time.Minute {
+		t.Errorf("MinStateDuration = %s, want 10m", cfg.Overlays.MinStateDuration)
+	}
+	if !cfg.Overlays.SizeFlexibleReservedInstances() {
+		t.Errorf("SizeFlexibleReservedInstances() = false for matching %q", cfg.Overlays.ReservedInstanceMatching)
+	}
+	if !cfg.Overlays.ForceConflicts {
+		t.Error("ForceConflicts = false, want true")
+	}
+	// Delete threshold is unset, so it falls back to utilizationThreshold
+	if create, del := cfg.Overlays.UtilizationThresholds(); create != 85.0 || del != 90.0 {
+		t.Errorf("UtilizationThresholds() = (%f, %f), want (85.0, 90.0)", create, del)
+	}
 }
 
 func TestValidateOverlayManagement(t *testing.T) {
@@ -416,6 +490,83 @@ func TestValidateOverlayManagement(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "discount above 100",
+			config: OverlayManagementConfig{
+				UtilizationThreshold: 95.0,
+				Discounts: OverlayDiscountsConfig{
+					ComputeSavingsPlan: ptr.To(120.0),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative discount",
+			config: OverlayManagementConfig{
+				UtilizationThreshold: 95.0,
+				Discounts: OverlayDiscountsConfig{
+					ReservedInstance: ptr.To(-5.0),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "hysteresis band",
+			config: OverlayManagementConfig{
+				UtilizationCreateThreshold: ptr.To(90.0),
+				UtilizationDeleteThreshold: ptr.To(95.0),
+				MinStateDuration:           15 * time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "create threshold above delete threshold",
+			config: OverlayManagementConfig{
+				UtilizationCreateThreshold: ptr.To(96.0),
+				UtilizationDeleteThreshold: ptr.To(95.0),
+			},
+			wantErr: true,
+		},
+		{
+			name: "create threshold above inherited delete threshold",
+			config: OverlayManagementConfig{
+				UtilizationThreshold:       95.0,
+				UtilizationCreateThreshold: ptr.To(97.0),
+			},
+			wantErr: true,
+		},
+		{
+			name: "delete threshold above 100",
+			config: OverlayManagementConfig{
+				UtilizationThreshold:       95.0,
+				UtilizationDeleteThreshold: ptr.To(101.0),
+			},
+			wantErr: true,
+		},
+		{
+			name: "size-flexible reserved instance matching",
+			config: OverlayManagementConfig{
+				UtilizationThreshold:     95.0,
+				ReservedInstanceMatching: ReservedInstanceMatchingSizeFlexible,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown reserved instance matching",
+			config: OverlayManagementConfig{
+				UtilizationThreshold:     95.0,
+				ReservedInstanceMatching: "normalized",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative min state duration",
+			config: OverlayManagementConfig{
+				UtilizationThreshold: 95.0,
+				MinStateDuration:     -time.Minute,
+			},
+			wantErr: true,
+		},
 		{
 			name: "zero weights are valid",
 			config: OverlayManagementConfig{
@@ -448,3 +599,455 @@ func TestValidateOverlayManagement(t *testing.T) {
 		})
 	}
 }
+
+func TestPreferencesAndWebhookDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configYAML := `
+prometheusUrl: "http://prometheus:9090"
+aws:
+  accountId: "123456789012"
+  region: "us-west-2"
+`
+	if err := os.WriteFile(configPath, []byte(configYAML), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if minAdj, maxAdj := cfg.Preferences.AdjustmentRange(); minAdj != DefaultPreferencesMinAdjustment ||
+		maxAdj != DefaultPreferencesMaxAdjustment {
+		t.Errorf("AdjustmentRange() = (%f, %f), want (%f, %f)",
+			minAdj, maxAdj, DefaultPreferencesMinAdjustment, DefaultPreferencesMaxAdjustment)
+	}
+	if limit := cfg.Preferences.AbsoluteAdjustmentLimit(); limit != DefaultPreferencesMaxAbsoluteAdjustment {
+		t.Errorf("AbsoluteAdjustmentLimit() = %f, want %f", limit, DefaultPreferencesMaxAbsoluteAdjustment)
+	}
+	if cfg.Webhook.Enabled {
+		t.Error("Webhook.Enabled = true, want false by default")
+	}
+	if cfg.Webhook.Port != DefaultWebhookPort {
+		t.Errorf("Webhook.Port = %d, want %d", cfg.Webhook.Port, DefaultWebhookPort)
+	}
+	if !cfg.Webhook.FailOpen {
+		t.Error("Webhook.FailOpen = false, want true by default")
+	}
+	if cfg.Webhook.WarnOnly {
+		t.Error("Webhook.WarnOnly = true, want false by default")
+	}
+}
+
+func TestPreferencesAndWebhookCustomValues(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configYAML := `
+prometheusUrl: "http://prometheus:9090"
+aws:
+  accountId: "123456789012"
+  region: "us-west-2"
+preferences:
+  minAdjustment: -50
+  maxAdjustment: 25
+  maxAbsoluteAdjustment: 100
+webhook:
+  enabled: true
+  port: 10250
+  certDir: /certs
+  failOpen: false
+  warnOnly: true
+`
+	if err := os.WriteFile(configPath, []byte(configYAML), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if minAdj, maxAdj := cfg.Preferences.AdjustmentRange(); minAdj != -50 || maxAdj != 25 {
+		t.Errorf("AdjustmentRange() = (%f, %f), want (-50, 25)", minAdj, maxAdj)
+	}
+	if limit := cfg.Preferences.AbsoluteAdjustmentLimit(); limit != 100 {
+		t.Errorf("AbsoluteAdjustmentLimit() = %f, want 100", limit)
+	}
+	want := WebhookConfig{Enabled: true, Port: 10250, CertDir: "/certs", FailOpen: false, WarnOnly: true}
+	if cfg.Webhook != want {
+		t.Errorf("Webhook = %+v, want %+v", cfg.Webhook, want)
+	}
+}
+
+func TestValidatePreferencesAndWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences PreferencesConfig
+		webhook     WebhookConfig
+		wantErr     bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:        "custom adjustment range",
+			preferences: PreferencesConfig{MinAdjustment: ptr.To(-30.0), MaxAdjustment: ptr.To(50.0)},
+		},
+		{
+			name:        "min adjustment below -100",
+			preferences: PreferencesConfig{MinAdjustment: ptr.To(-150.0)},
+			wantErr:     true,
+		},
+		{
+			name:        "min adjustment above max",
+			preferences: PreferencesConfig{MinAdjustment: ptr.To(60.0), MaxAdjustment: ptr.To(50.0)},
+			wantErr:     true,
+		},
+		{
+			name:        "negative absolute adjustment limit",
+			preferences: PreferencesConfig{MaxAbsoluteAdjustment: ptr.To(-1.0)},
+			wantErr:     true,
+		},
+		{
+			name:        "custom supported labels",
+			preferences: PreferencesConfig{SupportedLabels: []string{"kubernetes.io/arch", "example.com/team"}},
+		},
+		{
+			name:        "invalid supported label key",
+			preferences: PreferencesConfig{SupportedLabels: []string{"example.com/bad key"}},
+			wantErr:     true,
+		},
+		{
+			name:    "webhook port out of range",
+			webhook: WebhookConfig{Enabled: true, Port: 70000},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				PrometheusURL: "http://prometheus:9090",
+				AWS: AWSConfig{
+					AccountID: "123456789012",
+					Region:    "us-west-2",
+				},
+				Preferences: tt.preferences,
+				Webhook:     tt.webhook,
+			}
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcileLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		want ReconcileConfig
+	}{
+		{
+			name: "defaults",
+			want: ReconcileConfig{
+				Interval:     DefaultReconcileInterval,
+				PollInterval: DefaultReconcilePollInterval,
+				MaxFreshness: MaxFreshnessConfig{
+					SavingsPlans:      DefaultMaxFreshnessSavingsPlans,
+					ReservedInstances: DefaultMaxFreshnessReservedInstances,
+					SpotPricing:       DefaultMaxFreshnessSpotPricing,
+					Pricing:           DefaultMaxFreshnessPricing,
+				},
+			},
+		},
+		{
+			name: "custom values",
+			yaml: `
+reconcile:
+  interval: 10m
+  pollInterval: 1m
+  maxFreshness:
+    savingsPlans: 2h
+    reservedInstances: 2h
+    spotPricing: 1m
+    pricing: 48h
+`,
+			want: ReconcileConfig{
+				Interval:     10 * time.Minute,
+				PollInterval: time.Minute,
+				MaxFreshness: MaxFreshnessConfig{
+					SavingsPlans:      2 * time.Hour,
+					ReservedInstances: 2 * time.Hour,
+					SpotPricing:       time.Minute,
+					Pricing:           48 * time.Hour,
+				},
+			},
+		},
+		{
+			name: "environment variables",
+			env: map[string]string{
+				EnvReconcileInterval:     "2m",
+				EnvReconcilePollInterval: "15s",
+				EnvMaxFreshnessSP:        "125m",
+				EnvMaxFreshnessRI:        "125m",
+			},
+			want: ReconcileConfig{
+				Interval:     2 * time.Minute,
+				PollInterval: 15 * time.Second,
+				MaxFreshness: MaxFreshnessConfig{
+					SavingsPlans:      125 * time.Minute,
+					ReservedInstances: 125 * time.Minute,
+					SpotPricing:       DefaultMaxFreshnessSpotPricing,
+					Pricing:           DefaultMaxFreshnessPricing,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			configYAML := `
+prometheusUrl: "http://prometheus:9090"
+aws:
+  accountId: "123456789012"
+  region: "us-west-2"
+` + tt.yaml
+			if err := os.WriteFile(configPath, []byte(configYAML), 0600); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load(configPath)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Reconcile != tt.want {
+				t.Errorf("Reconcile = %+v, want %+v", cfg.Reconcile, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReconcile(t *testing.T) {
+	tests := []struct {
+		name      string
+		reconcile ReconcileConfig
+		wantErr   bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:      "custom values",
+			reconcile: ReconcileConfig{Interval: time.Minute, MaxFreshness: MaxFreshnessConfig{Pricing: 48 * time.Hour}},
+		},
+		{
+			name:      "negative interval",
+			reconcile: ReconcileConfig{Interval: -time.Minute},
+			wantErr:   true,
+		},
+		{
+			name:      "negative poll interval",
+			reconcile: ReconcileConfig{PollInterval: -time.Second},
+			wantErr:   true,
+		},
+		{
+			name:      "negative freshness limit",
+			reconcile: ReconcileConfig{MaxFreshness: MaxFreshnessConfig{SpotPricing: -time.Second}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				PrometheusURL: "http://prometheus:9090",
+				AWS: AWSConfig{
+					AccountID: "123456789012",
+					Region:    "us-west-2",
+				},
+				Reconcile: tt.reconcile,
+			}
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcileConfigDefaults(t *testing.T) {
+	var r ReconcileConfig
+	if got := r.ReconcileInterval(); got != DefaultReconcileInterval {
+		t.Errorf("ReconcileInterval() = %s, want %s", got, DefaultReconcileInterval)
+	}
+	if got := r.FreshnessPollInterval(); got != DefaultReconcilePollInterval {
+		t.Errorf("FreshnessPollInterval() = %s, want %s", got, DefaultReconcilePollInterval)
+	}
+	limits := MaxFreshnessConfig{Pricing: 48 * time.Hour}.Limits()
+	if limits.Pricing != 48*time.Hour {
+		t.Errorf("Limits().Pricing = %s, want the configured 48h", limits.Pricing)
+	}
+	if limits.SavingsPlans != DefaultMaxFreshnessSavingsPlans {
+		t.Errorf("Limits().SavingsPlans = %s, want %s", limits.SavingsPlans, DefaultMaxFreshnessSavingsPlans)
+	}
+}
+
+func TestClusterPreferencesLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configYAML := `
+prometheusUrl: "http://prometheus:9090"
+aws:
+  accountId: "123456789012"
+  region: "us-west-2"
+preferences:
+  clusterPreferences:
+    - name: prefer-graviton
+      nodePoolSelector: "team=platform"
+      preference: "karpenter.k8s.aws/instance-family=m7g,c7g adjust=-20%"
+      weight: 5
+    - name: prefer-spot
+      preference: "karpenter.sh/capacity-type=spot adjust=-10%"
+  supportedLabels:
+    - kubernetes.io/arch
+    - example.com/team
+`
+	if err := os.WriteFile(configPath, []byte(configYAML), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []ClusterPreference{
+		{
+			Name:             "prefer-graviton",
+			NodePoolSelector: "team=platform",
+			Preference:       "karpenter.k8s.aws/instance-family=m7g,c7g adjust=-20%",
+			Weight:           5,
+		},
+		{
+			Name:       "prefer-spot",
+			Preference: "karpenter.sh/capacity-type=spot adjust=-10%",
+		},
+	}
+	if len(cfg.Preferences.ClusterPreferences) != len(want) {
+		t.Fatalf("ClusterPreferences = %+v, want %+v", cfg.Preferences.ClusterPreferences, want)
+	}
+	for i := range want {
+		if cfg.Preferences.ClusterPreferences[i] != want[i] {
+			t.Errorf("ClusterPreferences[%d] = %+v, want %+v", i, cfg.Preferences.ClusterPreferences[i], want[i])
+		}
+	}
+	if got := cfg.Preferences.SupportedLabels; len(got) != 2 || got[0] != "kubernetes.io/arch" || got[1] != "example.com/team" {
+		t.Errorf("SupportedLabels = %v, want [kubernetes.io/arch example.com/team]", got)
+	}
+	if got := cfg.Preferences.ClusterPreferences[0].OverlayWeight(); got != 5 {
+		t.Errorf("OverlayWeight() = %d, want 5", got)
+	}
+	if got := cfg.Preferences.ClusterPreferences[1].OverlayWeight(); got != DefaultClusterPreferenceWeight {
+		t.Errorf("OverlayWeight() = %d, want default %d", got, DefaultClusterPreferenceWeight)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateClusterPreferences(t *testing.T) {
+	valid := ClusterPreference{
+		Name:             "prefer-graviton",
+		NodePoolSelector: "team=platform",
+		Preference:       "kubernetes.io/arch=arm64 adjust=-20%",
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*ClusterPreference)
+		extra   *ClusterPreference
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			mutate: func(*ClusterPreference) {},
+		},
+		{
+			name:   "empty selector selects all NodePools",
+			mutate: func(p *ClusterPreference) { p.NodePoolSelector = "" },
+		},
+		{
+			name:    "missing name",
+			mutate:  func(p *ClusterPreference) { p.Name = "" },
+			wantErr: "name is required",
+		},
+		{
+			name:    "invalid name",
+			mutate:  func(p *ClusterPreference) { p.Name = "Prefer_Graviton" },
+			wantErr: "invalid name",
+		},
+		{
+			name:    "name too long for a label value",
+			mutate:  func(p *ClusterPreference) { p.Name = strings.Repeat("a", 64) },
+			wantErr: "invalid name",
+		},
+		{
+			name:    "duplicate name",
+			mutate:  func(*ClusterPreference) {},
+			extra:   &valid,
+			wantErr: "duplicate name",
+		},
+		{
+			name:    "invalid selector",
+			mutate:  func(p *ClusterPreference) { p.NodePoolSelector = "team in (platform" },
+			wantErr: "invalid nodePoolSelector",
+		},
+		{
+			name:    "missing preference",
+			mutate:  func(p *ClusterPreference) { p.Preference = "" },
+			wantErr: "preference is required",
+		},
+		{
+			name:    "negative weight",
+			mutate:  func(p *ClusterPreference) { p.Weight = -1 },
+			wantErr: "weight must be between 0 and 10000",
+		},
+		{
+			name:    "weight above the NodeOverlay maximum",
+			mutate:  func(p *ClusterPreference) { p.Weight = 10001 },
+			wantErr: "weight must be between 0 and 10000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pref := valid
+			tt.mutate(&pref)
+			prefs := []ClusterPreference{pref}
+			if tt.extra != nil {
+				prefs = append(prefs, *tt.extra)
+			}
+
+			err := validateClusterPreferences(prefs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateClusterPreferences() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateClusterPreferences() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
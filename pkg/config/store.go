@@ -0,0 +1,279 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Provider supplies the configuration currently in effect.
+//
+// Components hold a Provider instead of a *Config so the configuration can be replaced while
+// the controller runs. They should call Current once per reconcile and use that snapshot
+// throughout, so a reload never takes effect halfway through a decision.
+type Provider interface {
+	Current() *Config
+}
+
+// Current returns c itself, making a fixed *Config usable wherever a Provider is expected.
+func (c *Config) Current() *Config {
+	return c
+}
+
+// Store is a Provider whose configuration can be replaced at runtime, either directly with
+// Update or by Watch when the config file changes. It is safe for concurrent use.
+//
+// The configuration in effect is a base configuration, normally the config file, with
+// overrides applied on top, e.g. the settings of a VeneerConfig resource. Either can be
+// replaced independently.
+type Store struct {
+	current atomic.Pointer[Config]
+
+	// mu serializes updates, so concurrent reloads can't interleave their diffs. It also
+	// guards base and overrides.
+	mu sync.Mutex
+
+	// base is the configuration the overrides are applied to.
+	base *Config
+
+	// overrides modifies a copy of base to produce the configuration in effect. May be nil.
+	overrides func(*Config)
+}
+
+// NewStore returns a Store holding cfg, with no overrides.
+func NewStore(cfg *Config) *Store {
+	s := &Store{base: cfg}
+	s.current.Store(cfg)
+	return s
+}
+
+// NewStoreWithOverrides returns a Store holding base with overrides applied. Unlike
+// SetOverrides, every setting is taken from the result, including those only read at startup.
+// It returns an error if the result is invalid.
+func NewStoreWithOverrides(base *Config, overrides func(*Config)) (*Store, error) {
+	cfg := withOverrides(base, overrides)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	s := &Store{base: base, overrides: overrides}
+	s.current.Store(cfg)
+	return s, nil
+}
+
+// Current returns the configuration currently in effect. Callers must not modify it.
+func (s *Store) Current() *Config {
+	return s.current.Load()
+}
+
+// Change describes the effect of a configuration update.
+type Change struct {
+	// Applied lists the configuration keys (e.g. "overlays.utilizationThreshold") whose new
+	// values took effect.
+	Applied []string
+
+	// RestartRequired lists the changed keys that are only read at startup. Their old values
+	// are kept until the controller restarts.
+	RestartRequired []string
+}
+
+// Empty reports whether the update changed nothing.
+func (c Change) Empty() bool {
+	return len(c.Applied) == 0 && len(c.RestartRequired) == 0
+}
+
+// Update replaces the base configuration with next and, if the result with the current
+// overrides applied is valid, atomically makes it the configuration in effect. An invalid
+// configuration is rejected and the current one kept.
+//
+// Settings that are only read at startup (see keepStartupSettings) keep their current values;
+// changes to them are reported in Change.RestartRequired rather than applied.
+func (s *Store) Update(next *Config) (Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, err := s.apply(next, s.overrides)
+	if err != nil {
+		return Change{}, err
+	}
+	s.base = next
+	return change, nil
+}
+
+// SetOverrides replaces the overrides applied to the base configuration, like Update. A nil
+// overrides removes them, reverting to the base configuration.
+func (s *Store) SetOverrides(overrides func(*Config)) (Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change, err := s.apply(s.base, overrides)
+	if err != nil {
+		return Change{}, err
+	}
+	s.overrides = overrides
+	return change, nil
+}
+
+// RestartRequired returns the configuration keys whose requested values differ from the
+// configuration in effect because they are only read at startup.
+func (s *Store) RestartRequired() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return changedKeys(s.current.Load(), withOverrides(s.base, s.overrides))
+}
+
+// withOverrides returns a copy of base with overrides applied.
+func withOverrides(base *Config, overrides func(*Config)) *Config {
+	next := base.DeepCopy()
+	if overrides != nil {
+		overrides(next)
+	}
+	return next
+}
+
+// apply makes base with overrides applied the configuration in effect, if it is valid.
+// The caller must hold mu.
+func (s *Store) apply(base *Config, overrides func(*Config)) (Change, error) {
+	next := withOverrides(base, overrides)
+	if err := next.Validate(); err != nil {
+		return Change{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	current := s.current.Load()
+	requested := changedKeys(current, next)
+
+	keepStartupSettings(next, current)
+	applied := changedKeys(current, next)
+
+	change := Change{Applied: applied}
+	appliedSet := make(map[string]bool, len(applied))
+	for _, key := range applied {
+		appliedSet[key] = true
+	}
+	for _, key := range requested {
+		if !appliedSet[key] {
+			change.RestartRequired = append(change.RestartRequired, key)
+		}
+	}
+
+	if len(applied) > 0 {
+		s.current.Store(next)
+	}
+	return change, nil
+}
+
+// keepStartupSettings copies the settings that are only read at startup from current to next:
+// addresses and clients built before the manager starts, the AWS account and region,
+// the metrics reconcile and poll intervals, and the webhook server.
+func keepStartupSettings(next, current *Config) {
+	next.PrometheusURL = current.PrometheusURL
+	next.LogLevel = current.LogLevel
+	next.MetricsBindAddress = current.MetricsBindAddress
+	next.HealthProbeBindAddress = current.HealthProbeBindAddress
+	next.TriggerBindAddress = current.TriggerBindAddress
+	next.AWS = current.AWS
+	next.Reconcile.Interval = current.Reconcile.Interval
+	next.Reconcile.PollInterval = current.Reconcile.PollInterval
+	next.Webhook.Enabled = current.Webhook.Enabled
+	next.Webhook.Port = current.Webhook.Port
+	next.Webhook.CertDir = current.Webhook.CertDir
+}
+
+// Watch reloads the config file at path whenever it changes, including when a mounted
+// ConfigMap is updated, and passes the result to onReload. The reloaded configuration is
+// loaded and validated like Load; it is not applied until the caller passes it to Update.
+//
+// Watch returns once the watch is established. It watches until the process exits.
+func Watch(path string, onReload func(*Config, error)) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// Viper only serves as the file watcher: the file is reloaded with Load, so a reload sees
+	// the same defaults, environment overrides, and validation as startup did.
+	v.OnConfigChange(func(fsnotify.Event) {
+		onReload(Load(path))
+	})
+	v.WatchConfig()
+	return nil
+}
+
+// DeepCopy returns a copy of c that shares no slices or pointers with it.
+func (c *Config) DeepCopy() *Config {
+	out := *c
+	out.Overlays.UtilizationCreateThreshold = clonePtr(c.Overlays.UtilizationCreateThreshold)
+	out.Overlays.UtilizationDeleteThreshold = clonePtr(c.Overlays.UtilizationDeleteThreshold)
+	out.Overlays.Discounts.ReservedInstance = clonePtr(c.Overlays.Discounts.ReservedInstance)
+	out.Overlays.Discounts.EC2InstanceSavingsPlan = clonePtr(c.Overlays.Discounts.EC2InstanceSavingsPlan)
+	out.Overlays.Discounts.ComputeSavingsPlan = clonePtr(c.Overlays.Discounts.ComputeSavingsPlan)
+	out.Preferences.MinAdjustment = clonePtr(c.Preferences.MinAdjustment)
+	out.Preferences.MaxAdjustment = clonePtr(c.Preferences.MaxAdjustment)
+	out.Preferences.MaxAbsoluteAdjustment = clonePtr(c.Preferences.MaxAbsoluteAdjustment)
+	out.Preferences.ClusterPreferences = append([]ClusterPreference(nil), c.Preferences.ClusterPreferences...)
+	out.Preferences.SupportedLabels = append([]string(nil), c.Preferences.SupportedLabels...)
+	return &out
+}
+
+// clonePtr returns a pointer to a copy of *p, or nil if p is nil.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// changedKeys returns the configuration keys whose values differ between a and b, named as
+// in the config file (e.g. "overlays.weights.reservedInstance"). Lists are compared whole.
+func changedKeys(a, b *Config) []string {
+	var keys []string
+	diffStruct("", reflect.ValueOf(*a), reflect.ValueOf(*b), &keys)
+	return keys
+}
+
+// diffStruct appends the keys of the fields that differ between the structs a and b,
+// recursing into nested structs.
+func diffStruct(prefix string, a, b reflect.Value, keys *[]string) {
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		key := prefix + name
+
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Struct {
+			diffStruct(key+".", fa, fb, keys)
+			continue
+		}
+		if fa.Kind() == reflect.Slice && fa.Len() == 0 && fb.Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			*keys = append(*keys, key)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/utils/ptr"
+)
+
+// testStoreConfig returns a minimal valid configuration.
+func testStoreConfig() *Config {
+	return &Config{
+		PrometheusURL: "http://prometheus:9090",
+		AWS: AWSConfig{
+			AccountID: "123456789012",
+			Region:    "us-west-2",
+		},
+		Overlays: OverlayManagementConfig{
+			UtilizationThreshold: 95,
+			Weights:              OverlayWeightsConfig{ReservedInstance: 30},
+		},
+		Preferences: PreferencesConfig{Enabled: true},
+	}
+}
+
+func TestStore_Update(t *testing.T) {
+	tests := []struct {
+		name                string
+		modify              func(*Config)
+		wantErr             bool
+		wantApplied         []string
+		wantRestartRequired []string
+		validate            func(*testing.T, *Config)
+	}{
+		{
+			name:   "no changes",
+			modify: func(*Config) {},
+		},
+		{
+			name: "runtime settings are applied",
+			modify: func(c *Config) {
+				c.Overlays.Disabled = true
+				c.Overlays.UtilizationThreshold = 80
+				c.Overlays.Weights.ReservedInstance = 40
+				c.Preferences.ClusterPreferences = []ClusterPreference{
+					{Name: "prefer-spot", Preference: "karpenter.sh/capacity-type=spot adjust=-10%"},
+				}
+			},
+			wantApplied: []string{
+				"overlays.disabled",
+				"overlays.utilizationThreshold",
+				"overlays.weights.reservedInstance",
+				"preferences.clusterPreferences",
+			},
+			validate: func(t *testing.T, c *Config) {
+				if !c.Overlays.Disabled || c.Overlays.UtilizationThreshold != 80 {
+					t.Errorf("expected reloaded overlay settings, got %+v", c.Overlays)
+				}
+				if len(c.Preferences.ClusterPreferences) != 1 {
+					t.Errorf("expected 1 cluster preference, got %v", c.Preferences.ClusterPreferences)
+				}
+			},
+		},
+		{
+			name: "startup settings are kept",
+			modify: func(c *Config) {
+				c.PrometheusURL = "http://other-prometheus:9090"
+				c.TriggerBindAddress = "127.0.0.1:8082"
+				c.Preferences.Enabled = false
+				c.Webhook.Port = 8443
+				c.Reconcile.Interval = 10 * time.Minute
+				c.Reconcile.PollInterval = time.Minute
+				c.Reconcile.MaxFreshness.SavingsPlans = 2 * time.Hour
+				c.Overlays.MinStateDuration = time.Minute
+			},
+			wantApplied: []string{
+				"reconcile.maxFreshness.savingsPlans", "overlays.minStateDuration", "preferences.enabled",
+			},
+			wantRestartRequired: []string{
+				"prometheusUrl", "triggerBindAddress", "reconcile.interval", "reconcile.pollInterval", "webhook.port",
+			},
+			validate: func(t *testing.T, c *Config) {
+				if c.PrometheusURL != "http://prometheus:9090" {
+					t.Errorf("PrometheusURL = %q, want the startup value", c.PrometheusURL)
+				}
+				if c.Preferences.Enabled {
+					t.Error("expected preferences.enabled to be applied")
+				}
+				if c.Overlays.MinStateDuration != time.Minute {
+					t.Errorf("MinStateDuration = %s, want 1m", c.Overlays.MinStateDuration)
+				}
+			},
+		},
+		{
+			name: "only startup settings changed",
+			modify: func(c *Config) {
+				c.AWS.Region = "us-east-1"
+			},
+			wantRestartRequired: []string{"aws.region"},
+		},
+		{
+			name: "invalid config is rejected",
+			modify: func(c *Config) {
+				c.Overlays.UtilizationThreshold = 150
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initial := testStoreConfig()
+			store := NewStore(initial)
+
+			next := testStoreConfig()
+			tt.modify(next)
+			change, err := store.Update(next)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if store.Current() != initial {
+					t.Error("expected the current config to be kept after a rejected update")
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(change.Applied, tt.wantApplied) {
+				t.Errorf("Applied = %v, want %v", change.Applied, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(change.RestartRequired, tt.wantRestartRequired) {
+				t.Errorf("RestartRequired = %v, want %v", change.RestartRequired, tt.wantRestartRequired)
+			}
+			if len(tt.wantApplied) == 0 && store.Current() != initial {
+				t.Error("expected the current config to be kept when nothing applicable changed")
+			}
+			if tt.validate != nil {
+				tt.validate(t, store.Current())
+			}
+		})
+	}
+}
+
+func TestStore_SetOverrides(t *testing.T) {
+	store := NewStore(testStoreConfig())
+	lowerThreshold := func(c *Config) {
+		c.Overlays.UtilizationThreshold = 80
+		c.AWS.Region = "us-east-1"
+	}
+
+	change, err := store.SetOverrides(lowerThreshold)
+	if err != nil {
+		t.Fatalf("SetOverrides() error = %v", err)
+	}
+	if !reflect.DeepEqual(change.Applied, []string{"overlays.utilizationThreshold"}) {
+		t.Errorf("Applied = %v, want [overlays.utilizationThreshold]", change.Applied)
+	}
+	if !reflect.DeepEqual(store.RestartRequired(), []string{"aws.region"}) {
+		t.Errorf("RestartRequired() = %v, want [aws.region]", store.RestartRequired())
+	}
+
+	// Overrides stay applied when the base is reloaded
+	base := testStoreConfig()
+	base.Overlays.Weights.ReservedInstance = 40
+	if _, err := store.Update(base); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := store.Current().Overlays; got.UtilizationThreshold != 80 || got.Weights.ReservedInstance != 40 {
+		t.Errorf("expected the reloaded base with overrides applied, got %+v", got)
+	}
+
+	// Invalid overrides are rejected and the previous ones kept
+	if _, err := store.SetOverrides(func(c *Config) { c.Overlays.UtilizationThreshold = 150 }); err == nil {
+		t.Error("SetOverrides() expected error for invalid overrides, got nil")
+	}
+	if got := store.Current().Overlays.UtilizationThreshold; got != 80 {
+		t.Errorf("UtilizationThreshold = %f, want 80 after a rejected update", got)
+	}
+
+	// Removing the overrides reverts to the base
+	change, err = store.SetOverrides(nil)
+	if err != nil {
+		t.Fatalf("SetOverrides(nil) error = %v", err)
+	}
+	if !reflect.DeepEqual(change.Applied, []string{"overlays.utilizationThreshold"}) {
+		t.Errorf("Applied = %v, want [overlays.utilizationThreshold]", change.Applied)
+	}
+	if len(store.RestartRequired()) != 0 {
+		t.Errorf("RestartRequired() = %v, want none", store.RestartRequired())
+	}
+}
+
+func TestNewStoreWithOverrides(t *testing.T) {
+	store, err := NewStoreWithOverrides(testStoreConfig(), func(c *Config) {
+		c.AWS.Region = "us-east-1"
+	})
+	if err != nil {
+		t.Fatalf("NewStoreWithOverrides() error = %v", err)
+	}
+	if got := store.Current().AWS.Region; got != "us-east-1" {
+		t.Errorf("AWS.Region = %q, want startup settings taken from the overrides", got)
+	}
+
+	if _, err := NewStoreWithOverrides(testStoreConfig(), func(c *Config) {
+		c.AWS.AccountID = "invalid"
+	}); err == nil {
+		t.Error("NewStoreWithOverrides() expected error for invalid overrides, got nil")
+	}
+}
+
+func TestConfig_DeepCopy(t *testing.T) {
+	original := testStoreConfig()
+	original.Overlays.UtilizationCreateThreshold = ptr.To(90.0)
+	original.Overlays.Discounts.ReservedInstance = ptr.To(40.0)
+	original.Preferences.MaxAdjustment = ptr.To(50.0)
+	original.Preferences.SupportedLabels = []string{"karpenter.sh/capacity-type"}
+
+	copied := original.DeepCopy()
+	*copied.Overlays.UtilizationCreateThreshold = 50
+	*copied.Overlays.Discounts.ReservedInstance = 10
+	*copied.Preferences.MaxAdjustment = 10
+	copied.Preferences.SupportedLabels[0] = "example.com/label"
+
+	if got := *original.Overlays.UtilizationCreateThreshold; got != 90 {
+		t.Errorf("original UtilizationCreateThreshold = %v, want 90", got)
+	}
+	if got := *original.Overlays.Discounts.ReservedInstance; got != 40 {
+		t.Errorf("original Discounts.ReservedInstance = %v, want 40", got)
+	}
+	if got := *original.Preferences.MaxAdjustment; got != 50 {
+		t.Errorf("original MaxAdjustment = %v, want 50", got)
+	}
+	if got := original.Preferences.SupportedLabels[0]; got != "karpenter.sh/capacity-type" {
+		t.Errorf("original SupportedLabels[0] = %q, want karpenter.sh/capacity-type", got)
+	}
+	if copied.Overlays.UtilizationDeleteThreshold != nil {
+		t.Errorf("UtilizationDeleteThreshold = %v, want nil", *copied.Overlays.UtilizationDeleteThreshold)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	const configYAML = `
+prometheusUrl: "http://prometheus:9090"
+aws:
+  accountId: "123456789012"
+  region: "us-west-2"
+overlays:
+  utilizationThreshold: %s
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(threshold string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(configYAML, threshold)), 0o644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+	writeConfig("95")
+
+	type reload struct {
+		cfg *Config
+		err error
+	}
+	reloads := make(chan reload, 16)
+	if err := Watch(path, func(cfg *Config, err error) {
+		select {
+		case reloads <- reload{cfg, err}:
+		default:
+		}
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	// waitFor returns the first reload matching want, skipping the partial reads a write
+	// can trigger.
+	waitFor := func(want func(reload) bool) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case r := <-reloads:
+				if want(r) {
+					return
+				}
+			case <-timeout:
+				t.Fatal("timed out waiting for config reload")
+			}
+		}
+	}
+
+	writeConfig("80")
+	waitFor(func(r reload) bool {
+		return r.err == nil && r.cfg.Overlays.UtilizationThreshold == 80
+	})
+
+	// Invalid configs are reported, not applied
+	writeConfig("150")
+	waitFor(func(r reload) bool {
+		return r.err != nil && r.cfg == nil
+	})
+}
+
+func TestWatchNonexistentFile(t *testing.T) {
+	if err := Watch("/nonexistent/config.yaml", func(*Config, error) {}); err == nil {
+		t.Error("Watch() expected error for nonexistent file, got nil")
+	}
+}
@@ -237,6 +237,10 @@ func TestMetricsIntegration_AllDecisionCombinations(t *testing.T) {
 		veneermetrics.ReasonNoCapacity,
 		veneermetrics.ReasonRIAvailable,
 		veneermetrics.ReasonRINotFound,
+		veneermetrics.ReasonRIFullyUtilized,
+		veneermetrics.ReasonSpotCheaper,
+		veneermetrics.ReasonHysteresisSuppressed,
+		veneermetrics.ReasonWithinHysteresisBand,
 		veneermetrics.ReasonUnknown,
 	}
 
@@ -254,12 +258,51 @@ func TestMetricsIntegration_AllDecisionCombinations(t *testing.T) {
 	}
 }
 
+// TestMetricsIntegration_SanitizeReason tests that decision engine reasons map to controlled labels.
+func TestMetricsIntegration_SanitizeReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   veneermetrics.DecisionReason
+	}{
+		{"utilization 96.0% at/above threshold 95.0%", veneermetrics.ReasonUtilizationAboveThreshold},
+		{"utilization 50.0% below threshold 95.0%, capacity available (10.00 $/hour)", veneermetrics.ReasonCapacityAvailable},
+		{"no remaining capacity (0.00 $/hour)", veneermetrics.ReasonNoCapacity},
+		{"2 reserved instances available", veneermetrics.ReasonRIAvailable},
+		{"no reserved instances available", veneermetrics.ReasonRINotFound},
+		{
+			"reserved instances fully utilized (3 of 3 in use: 2 running, 1 launching)",
+			veneermetrics.ReasonRIFullyUtilized,
+		},
+		{
+			"spot cheaper than covered price (spot 60.0% below on-demand, commitment discount 28.0%)",
+			veneermetrics.ReasonSpotCheaper,
+		},
+		{
+			"hysteresis: overlay present for 5m0s of minimum 15m0s, ignoring: utilization 96.0% at/above threshold 95.0%",
+			veneermetrics.ReasonHysteresisSuppressed,
+		},
+		{
+			"hysteresis: utilization 92.0% within hysteresis band [90.0%, 95.0%), keeping overlay present",
+			veneermetrics.ReasonHysteresisSuppressed,
+		},
+		{"utilization 92.0% within hysteresis band [90.0%, 95.0%)", veneermetrics.ReasonWithinHysteresisBand},
+		{"something unexpected", veneermetrics.ReasonUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, veneermetrics.SanitizeReason(tt.reason))
+		})
+	}
+}
+
 // TestMetricsIntegration_AllOperationCombinations tests all valid operation metric combinations.
 func TestMetricsIntegration_AllOperationCombinations(t *testing.T) {
 	operations := []veneermetrics.Operation{
 		veneermetrics.OperationCreate,
 		veneermetrics.OperationUpdate,
 		veneermetrics.OperationDelete,
+		veneermetrics.OperationUnchanged,
 	}
 
 	capacityTypes := []veneermetrics.CapacityType{
@@ -272,6 +315,7 @@ func TestMetricsIntegration_AllOperationCombinations(t *testing.T) {
 		veneermetrics.ErrorTypeValidation,
 		veneermetrics.ErrorTypeAPI,
 		veneermetrics.ErrorTypeNotFound,
+		veneermetrics.ErrorTypeConflict,
 	}
 
 	// Test successful operations
@@ -302,6 +346,8 @@ func TestMetricsIntegration_PrometheusQueryTypes(t *testing.T) {
 		veneermetrics.QueryTypeSPCapacity,
 		veneermetrics.QueryTypeRI,
 		veneermetrics.QueryTypeDataFreshness,
+		veneermetrics.QueryTypeOnDemandPrice,
+		veneermetrics.QueryTypeSpotPrice,
 	}
 
 	for _, qt := range queryTypes {
@@ -401,6 +447,7 @@ func TestMetricsIntegration_NewMetricsCreatesAllFields(t *testing.T) {
 	assert.NotNil(t, m.ReservedInstanceCount, "ReservedInstanceCount should not be nil")
 	assert.NotNil(t, m.SavingsPlanUtilizationPercent, "SavingsPlanUtilizationPercent should not be nil")
 	assert.NotNil(t, m.SavingsPlanRemainingCapacityDollars, "SavingsPlanRemainingCapacityDollars should not be nil")
+	assert.NotNil(t, m.SavingsPlanUnpricedLaunches, "SavingsPlanUnpricedLaunches should not be nil")
 	assert.NotNil(t, m.OverlayOperationsTotal, "OverlayOperationsTotal should not be nil")
 	assert.NotNil(t, m.OverlayOperationErrorsTotal, "OverlayOperationErrorsTotal should not be nil")
 	assert.NotNil(t, m.OverlayCount, "OverlayCount should not be nil")
@@ -409,6 +456,7 @@ func TestMetricsIntegration_NewMetricsCreatesAllFields(t *testing.T) {
 	assert.NotNil(t, m.PrometheusQueryResultCount, "PrometheusQueryResultCount should not be nil")
 	assert.NotNil(t, m.ConfigOverlaysDisabled, "ConfigOverlaysDisabled should not be nil")
 	assert.NotNil(t, m.ConfigUtilizationThreshold, "ConfigUtilizationThreshold should not be nil")
+	assert.NotNil(t, m.ConfigReloadsTotal, "ConfigReloadsTotal should not be nil")
 	assert.NotNil(t, m.Info, "Info should not be nil")
 }
 
@@ -452,4 +500,21 @@ func TestMetricsIntegration_InfoMetric(t *testing.T) {
 
 	infoValueDisabled := testutil.ToFloat64(m2.Info.WithLabelValues(veneermetrics.Version, "true"))
 	assert.Equal(t, float64(1), infoValueDisabled)
+
+	// A reload that toggles disabled mode replaces the info series instead of adding one
+	m2.SetConfigMetrics(false, 90.0)
+	assert.Equal(t, 1, testutil.CollectAndCount(m2.Info), "expected a single info series")
+	assert.Equal(t, float64(1), testutil.ToFloat64(m2.Info.WithLabelValues(veneermetrics.Version, "false")))
+}
+
+// TestMetricsIntegration_ConfigReloads tests config reload counting.
+func TestMetricsIntegration_ConfigReloads(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.RecordConfigReload(veneermetrics.ResultSuccess)
+	m.RecordConfigReload(veneermetrics.ResultSuccess)
+	m.RecordConfigReload(veneermetrics.ResultError)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.ConfigReloadsTotal.WithLabelValues("success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ConfigReloadsTotal.WithLabelValues("error")))
 }
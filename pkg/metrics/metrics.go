@@ -57,8 +57,10 @@ const (
 	MetricPrometheusQueryResultCount  = "prometheus_query_result_count"
 	MetricConfigOverlaysDisabled      = "config_overlays_disabled"
 	MetricConfigUtilizationThreshold  = "config_utilization_threshold_percent"
+	MetricConfigReloadsTotal          = "config_reloads_total"
 	MetricSPUtilizationPercent        = "savings_plan_utilization_percent"
 	MetricSPRemainingCapacityDollars  = "savings_plan_remaining_capacity_dollars"
+	MetricSPUnpricedLaunches          = "savings_plan_unpriced_launches"
 	MetricInfo                        = "info"
 )
 
@@ -99,6 +101,10 @@ const (
 	OperationCreate Operation = "create"
 	OperationUpdate Operation = "update"
 	OperationDelete Operation = "delete"
+
+	// OperationUnchanged records an overlay that already matched its desired state,
+	// so no API call was made.
+	OperationUnchanged Operation = "unchanged"
 )
 
 // String returns the string representation of Operation.
@@ -113,6 +119,10 @@ const (
 	ErrorTypeValidation ErrorType = "validation"
 	ErrorTypeAPI        ErrorType = "api"
 	ErrorTypeNotFound   ErrorType = "not_found"
+
+	// ErrorTypeConflict records a server-side apply rejected because another field
+	// manager owns a field Veneer sets.
+	ErrorTypeConflict ErrorType = "conflict"
 )
 
 // String returns the string representation of ErrorType.
@@ -128,6 +138,8 @@ const (
 	QueryTypeSPCapacity    QueryType = "sp_capacity"
 	QueryTypeRI            QueryType = "ri"
 	QueryTypeDataFreshness QueryType = "data_freshness"
+	QueryTypeOnDemandPrice QueryType = "ondemand_price"
+	QueryTypeSpotPrice     QueryType = "spot_price"
 )
 
 // String returns the string representation of QueryType.
@@ -159,6 +171,10 @@ const (
 	ReasonNoCapacity                DecisionReason = "no_capacity"
 	ReasonRIAvailable               DecisionReason = "ri_available"
 	ReasonRINotFound                DecisionReason = "ri_not_found"
+	ReasonRIFullyUtilized           DecisionReason = "ri_fully_utilized"
+	ReasonSpotCheaper               DecisionReason = "spot_cheaper"
+	ReasonHysteresisSuppressed      DecisionReason = "hysteresis_suppressed"
+	ReasonWithinHysteresisBand      DecisionReason = "within_hysteresis_band"
 	ReasonUnknown                   DecisionReason = "unknown"
 )
 
@@ -197,8 +213,10 @@ const (
 	helpPrometheusQueryResultCount  = "Number of results returned by last Prometheus query"
 	helpConfigOverlaysDisabled      = "1 if overlay creation is disabled (dry-run mode), 0 if enabled"
 	helpConfigUtilizationThreshold  = "Configured utilization threshold for overlay deletion"
+	helpConfigReloadsTotal          = "Total config file reloads by result"
 	helpSPUtilizationPercent        = "Savings Plan utilization percentage by type, family, and region"
 	helpSPRemainingCapacityDollars  = "Savings Plan remaining capacity in dollars per hour"
+	helpSPUnpricedLaunches          = "On-demand launches since the last Lumina refresh with no known on-demand price"
 	helpInfo                        = "Controller information with version and mode labels"
 )
 
@@ -209,6 +227,10 @@ const (
 	reasonPatternNoCapacity     = "no remaining capacity"
 	reasonPatternRIAvailable    = "reserved instances available"
 	reasonPatternNoRI           = "no reserved instances"
+	reasonPatternRIFullyUsed    = "reserved instances fully utilized"
+	reasonPatternSpotCheaper    = "spot cheaper"
+	reasonPatternHysteresis     = "hysteresis:"
+	reasonPatternHysteresisBand = "within hysteresis band"
 )
 
 // Version is set at build time via ldflags.
@@ -263,6 +285,9 @@ type Metrics struct {
 	// SavingsPlanRemainingCapacityDollars tracks remaining SP capacity in $/hour.
 	SavingsPlanRemainingCapacityDollars *prometheus.GaugeVec
 
+	// SavingsPlanUnpricedLaunches tracks pending launches that couldn't be counted against SPs.
+	SavingsPlanUnpricedLaunches prometheus.Gauge
+
 	// ===================
 	// NodeOverlay Lifecycle Metrics
 	// ===================
@@ -299,6 +324,9 @@ type Metrics struct {
 	// ConfigUtilizationThreshold reports the configured utilization threshold.
 	ConfigUtilizationThreshold prometheus.Gauge
 
+	// ConfigReloadsTotal counts config file reloads, by whether the new config was applied.
+	ConfigReloadsTotal *prometheus.CounterVec
+
 	// ===================
 	// Info Metric
 	// ===================
@@ -365,6 +393,12 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Help:      helpSPRemainingCapacityDollars,
 		}, []string{LabelType, LabelInstanceFamily, LabelRegion}),
 
+		SavingsPlanUnpricedLaunches: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      MetricSPUnpricedLaunches,
+			Help:      helpSPUnpricedLaunches,
+		}),
+
 		OverlayOperationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: Namespace,
 			Name:      MetricOverlayOperationsTotal,
@@ -414,6 +448,12 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Help:      helpConfigUtilizationThreshold,
 		}),
 
+		ConfigReloadsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      MetricConfigReloadsTotal,
+			Help:      helpConfigReloadsTotal,
+		}, []string{LabelResult}),
+
 		Info: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
 			Name:      MetricInfo,
@@ -432,6 +472,7 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		m.ReservedInstanceCount,
 		m.SavingsPlanUtilizationPercent,
 		m.SavingsPlanRemainingCapacityDollars,
+		m.SavingsPlanUnpricedLaunches,
 		m.OverlayOperationsTotal,
 		m.OverlayOperationErrorsTotal,
 		m.OverlayCount,
@@ -440,13 +481,15 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		m.PrometheusQueryResultCount,
 		m.ConfigOverlaysDisabled,
 		m.ConfigUtilizationThreshold,
+		m.ConfigReloadsTotal,
 		m.Info,
 	)
 
 	return m
 }
 
-// SetConfigMetrics sets configuration-related metrics. Call this once at startup.
+// SetConfigMetrics sets configuration-related metrics. Call this at startup and whenever
+// the configuration is reloaded.
 func (m *Metrics) SetConfigMetrics(overlaysDisabled bool, utilizationThreshold float64) {
 	if overlaysDisabled {
 		m.ConfigOverlaysDisabled.Set(1)
@@ -460,9 +503,17 @@ func (m *Metrics) SetConfigMetrics(overlaysDisabled bool, utilizationThreshold f
 	if overlaysDisabled {
 		disabledStr = "true"
 	}
+	// Reset first so a reload that toggles disabled mode doesn't leave the old series behind
+	m.Info.Reset()
 	m.Info.WithLabelValues(Version, disabledStr).Set(1)
 }
 
+// RecordConfigReload records a config file reload. ResultError means the reloaded
+// config was rejected and the previous one kept.
+func (m *Metrics) RecordConfigReload(result Result) {
+	m.ConfigReloadsTotal.WithLabelValues(result.String()).Inc()
+}
+
 // RecordReconciliation records a reconciliation cycle result and duration.
 func (m *Metrics) RecordReconciliation(result Result, durationSeconds float64) {
 	m.ReconciliationTotal.WithLabelValues(result.String()).Inc()
@@ -547,6 +598,12 @@ func (m *Metrics) SetSavingsPlanMetrics(
 	m.SavingsPlanRemainingCapacityDollars.WithLabelValues(spType, instanceFamily, region).Set(remainingCapacity)
 }
 
+// SetSavingsPlanUnpricedLaunches sets the number of on-demand launches since the last Lumina
+// refresh that couldn't be counted against Savings Plans because their price is unknown.
+func (m *Metrics) SetSavingsPlanUnpricedLaunches(count int) {
+	m.SavingsPlanUnpricedLaunches.Set(float64(count))
+}
+
 // SetOverlayCount sets the current overlay count by capacity type.
 func (m *Metrics) SetOverlayCount(capacityType CapacityType, count int) {
 	m.OverlayCount.WithLabelValues(capacityType.String()).Set(float64(count))
@@ -563,12 +620,21 @@ func BoolToShouldExist(b bool) ShouldExist {
 // SanitizeReason converts a decision reason string to a controlled DecisionReason.
 func SanitizeReason(reason string) DecisionReason {
 	switch {
+	// Hysteresis reasons embed the engine's original reason, so they must match first
+	case strings.HasPrefix(reason, reasonPatternHysteresis):
+		return ReasonHysteresisSuppressed
+	case strings.Contains(reason, reasonPatternHysteresisBand):
+		return ReasonWithinHysteresisBand
+	case strings.Contains(reason, reasonPatternSpotCheaper):
+		return ReasonSpotCheaper
 	case strings.Contains(reason, reasonPatternAboveThreshold):
 		return ReasonUtilizationAboveThreshold
 	case strings.Contains(reason, reasonPatternBelowThreshold):
 		return ReasonCapacityAvailable
 	case strings.Contains(reason, reasonPatternNoCapacity):
 		return ReasonNoCapacity
+	case strings.Contains(reason, reasonPatternRIFullyUsed):
+		return ReasonRIFullyUtilized
 	case strings.Contains(reason, reasonPatternNoRI):
 		return ReasonRINotFound
 	case strings.Contains(reason, reasonPatternRIAvailable):
@@ -22,6 +22,9 @@ package overlay
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/nextdoor/veneer/pkg/config"
 	"github.com/nextdoor/veneer/pkg/prometheus"
@@ -61,10 +64,22 @@ type Decision struct {
 	// Reserved Instances > EC2 Instance SPs > Compute SPs
 	Weight int
 
-	// Price is the effective hourly cost for on-demand instances with this capacity applied.
-	// For Phase 2, this is always "0.00" (100% discount) to maximize pre-paid usage.
+	// Price is the absolute effective hourly cost for on-demand instances with this capacity applied.
+	// Only set when the overlay targets a single instance type with a known on-demand price
+	// (Reserved Instances). Mutually exclusive with PriceAdjustment.
+	// Example: "0.1210" for an m5.xlarge RI at a 37% discount off $0.192/hour.
 	Price string
 
+	// PriceAdjustment is the percentage change applied to each matching instance type's own
+	// on-demand price. Used when an overlay covers many instance types (Savings Plans) or when
+	// the on-demand price is unknown, so a covered m5.24xlarge keeps its real relative cost.
+	// Mutually exclusive with Price.
+	// Example: "-28%"
+	PriceAdjustment string
+
+	// DiscountPercent is the configured discount percentage the price was derived from (0-100).
+	DiscountPercent float64
+
 	// TargetSelector describes which instances this overlay targets.
 	// Examples:
 	//   - Global Compute SP: "karpenter.k8s.aws/instance-family: Exists"
@@ -83,16 +98,49 @@ type Decision struct {
 	// RemainingCapacity is the remaining capacity in $/hour.
 	// Optional: may be 0 if not applicable or unknown.
 	RemainingCapacity float64
+
+	// WithinHysteresisBand is true when utilization sits between the create and delete
+	// thresholds. The overlay should keep whatever state it is currently in, which only
+	// a HysteresisTracker knows (see HysteresisTracker.Apply).
+	WithinHysteresisBand bool
+
+	// Suppressed is true when a HysteresisTracker overrode the decision to keep the
+	// overlay in its current state.
+	Suppressed bool
+
+	// InstanceTypes restricts the overlay to specific instance types (EC2 Instance SPs only).
+	// Set when some sizes in the family cost more per hour than the remaining capacity can
+	// cover, so Karpenter isn't told that e.g. a 24xlarge is discounted when only a large fits.
+	// Empty means the overlay covers the whole family.
+	InstanceTypes []string
+
+	// AvailabilityZone restricts the overlay to one AZ (zonal Reserved Instances only).
+	// Empty means the overlay covers the whole region.
+	AvailabilityZone string
+
+	// InstanceFamily is the EC2 instance family the overlay targets (EC2 Instance SPs and RIs).
+	// Empty for Compute SP overlays, which cover every family.
+	InstanceFamily string
+
+	// InstanceType is the EC2 instance type of an exact-type Reserved Instance overlay.
+	// Empty for family-level, size-flexible RI overlays (see AnalyzeReservedInstanceFamily),
+	// which list their covered sizes in InstanceTypes instead.
+	InstanceType string
+
+	// Region is the AWS region the overlay is scoped to (EC2 Instance SPs and RIs).
+	// Empty for Compute SP overlays, which are global.
+	Region string
 }
 
 // DecisionEngine analyzes capacity metrics and produces overlay lifecycle decisions.
 type DecisionEngine struct {
-	// Config provides utilization thresholds and overlay weights.
-	Config *config.Config
+	// Config provides utilization thresholds and overlay weights. Each decision reads the
+	// configuration once, so a reload applies from the next decision on.
+	Config config.Provider
 }
 
 // NewDecisionEngine creates a new decision engine with the provided configuration.
-func NewDecisionEngine(cfg *config.Config) *DecisionEngine {
+func NewDecisionEngine(cfg config.Provider) *DecisionEngine {
 	return &DecisionEngine{
 		Config: cfg,
 	}
@@ -123,6 +171,23 @@ type AggregatedSavingsPlan struct {
 
 	// Count is the number of SPs aggregated
 	Count int
+
+	// SpotSavingsPercent is how far spot prices sit below on-demand, averaged across the
+	// instance types this SP covers (0-100). Optional: 0 if spot pricing is unavailable,
+	// in which case no spot comparison is made.
+	SpotSavingsPercent float64
+
+	// InstanceTypePrices maps each instance type in the family to its on-demand price in
+	// $/hour (only for EC2 Instance SPs). Used to restrict the overlay to sizes that fit
+	// within TotalRemainingCapacity. Optional: nil if pricing data is unavailable, in
+	// which case the overlay covers the whole family.
+	InstanceTypePrices map[string]float64
+
+	// PendingLaunchCost is the on-demand price in $/hour of covered instances launched after
+	// Lumina last collected this SP's data. Lumina's remaining capacity doesn't reflect them
+	// yet, so the engine deducts their discounted cost before deciding.
+	// Optional: 0 if no launches are pending.
+	PendingLaunchCost float64
 }
 
 // AggregateComputeSavingsPlans aggregates multiple Compute Savings Plans into a single decision.
@@ -220,30 +285,56 @@ type AggregatedReservedInstance struct {
 	// InstanceType is the EC2 instance type
 	InstanceType string
 
-	// TotalCount is the sum of all RI counts across all AZs
+	// AvailabilityZone is the AZ for zonal RIs, empty for regional RIs
+	AvailabilityZone string
+
+	// TotalCount is the sum of all RI counts in the region (regional) or AZ (zonal)
 	TotalCount int
+
+	// OnDemandPrice is the on-demand price of the instance type in $/hour.
+	// Optional: 0 if pricing data is unavailable, in which case the overlay
+	// falls back to a percentage price adjustment.
+	OnDemandPrice float64
+
+	// SpotPrice is the lowest spot price of the instance type across AZs in $/hour.
+	// Optional: 0 if spot pricing is unavailable, in which case no spot comparison is made.
+	SpotPrice float64
+
+	// RunningCount is the number of on-demand instances of this type currently running in
+	// the region, each of which occupies one RI.
+	// Optional: 0 if no instances are running or the count is unknown.
+	RunningCount int
+
+	// PendingLaunchCount is the number of on-demand instances of this type Karpenter has
+	// launched that aren't running as Nodes yet. They will occupy RIs shortly, so they
+	// count as in use. Optional: 0 if no launches are pending.
+	PendingLaunchCount int
 }
 
-// AggregateReservedInstances aggregates Reserved Instances by instance type and region.
+// AggregateReservedInstances aggregates Reserved Instances by instance type, region, and zone.
 //
-// RIs can exist in multiple AZs within the same region, so we sum the counts per instance type+region
-// to prevent duplicate overlay names (one overlay per instance type+region, not per AZ).
+// Regional RIs apply to matching instances in any AZ, so their counts are summed per instance
+// type+region into one region-wide overlay. Zonal RIs only apply in their own AZ, so they are
+// summed per instance type+AZ and get their own zone-restricted overlay.
 //
-// RIs are scoped to ONE instance type + ONE region + ONE account, so we must group by type and region.
-// Example keys: "m5.xlarge:us-west-2", "c5.2xlarge:us-east-1"
+// Example keys: "m5.xlarge:us-west-2" (regional), "m5.xlarge:us-west-2:us-west-2a" (zonal)
 func AggregateReservedInstances(ris []prometheus.ReservedInstance) map[string]AggregatedReservedInstance {
 	byTypeRegion := make(map[string]AggregatedReservedInstance)
 
 	for _, ri := range ris {
-		// Create composite key: "instanceType:region"
+		// Create composite key: "instanceType:region", plus ":zone" for zonal RIs
 		key := ri.InstanceType + ":" + ri.Region
+		if ri.Zonal() {
+			key += ":" + ri.AvailabilityZone
+		}
 
 		agg, exists := byTypeRegion[key]
 		if !exists {
 			agg = AggregatedReservedInstance{
-				AccountID:    ri.AccountID,
-				Region:       ri.Region,
-				InstanceType: ri.InstanceType,
+				AccountID:        ri.AccountID,
+				Region:           ri.Region,
+				InstanceType:     ri.InstanceType,
+				AvailabilityZone: ri.AvailabilityZone,
 			}
 		}
 
@@ -254,6 +345,98 @@ func AggregateReservedInstances(ris []prometheus.ReservedInstance) map[string]Ag
 	return byTypeRegion
 }
 
+// AggregateOnDemandPrices indexes on-demand prices by instance type and region.
+//
+// Returns a map of "instanceType:region" -> price in $/hour, using the same key format as
+// AggregateReservedInstances so callers can look up the price for each aggregated RI.
+// Lumina may report several prices for the same type and region (e.g., one per operating
+// system), so the lowest price is kept.
+func AggregateOnDemandPrices(prices []prometheus.OnDemandPrice) map[string]float64 {
+	byTypeRegion := make(map[string]float64)
+
+	for _, price := range prices {
+		if price.Price <= 0 {
+			continue
+		}
+
+		key := price.InstanceType + ":" + price.Region
+		if existing, exists := byTypeRegion[key]; !exists || price.Price < existing {
+			byTypeRegion[key] = price.Price
+		}
+	}
+
+	return byTypeRegion
+}
+
+// AggregateSpotPrices indexes spot prices by instance type and region.
+//
+// Returns a map of "instanceType:region" -> price in $/hour. Spot prices are reported
+// per availability zone, so the lowest price across AZs is kept since that is the
+// price Karpenter would pay when launching spot capacity in the region.
+func AggregateSpotPrices(prices []prometheus.SpotPrice) map[string]float64 {
+	byTypeRegion := make(map[string]float64)
+
+	for _, price := range prices {
+		if price.Price <= 0 {
+			continue
+		}
+
+		key := price.InstanceType + ":" + price.Region
+		if existing, exists := byTypeRegion[key]; !exists || price.Price < existing {
+			byTypeRegion[key] = price.Price
+		}
+	}
+
+	return byTypeRegion
+}
+
+// AggregateSpotSavings computes how far spot prices sit below on-demand prices.
+//
+// Both inputs are keyed by "instanceType:region" (see AggregateSpotPrices and
+// AggregateOnDemandPrices). For every instance type with both prices, the spot savings
+// are (1 - spot/on-demand) * 100. Returns the average savings per "family:region"
+// (matching AggregateEC2InstanceSavingsPlans keys) and the average across all instance
+// types (for Compute SPs). Instance types missing either price are ignored.
+func AggregateSpotSavings(
+	spotPrices map[string]float64,
+	onDemandPrices map[string]float64,
+) (byFamilyRegion map[string]float64, overall float64) {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	var totalSum float64
+	var totalCount int
+
+	for key, spot := range spotPrices {
+		onDemand, exists := onDemandPrices[key]
+		if !exists || onDemand <= 0 {
+			continue
+		}
+
+		instanceType, region, found := strings.Cut(key, ":")
+		if !found {
+			continue
+		}
+		family, _, _ := strings.Cut(instanceType, ".")
+
+		savings := (1 - spot/onDemand) * 100
+		familyKey := family + ":" + region
+		sums[familyKey] += savings
+		counts[familyKey]++
+		totalSum += savings
+		totalCount++
+	}
+
+	byFamilyRegion = make(map[string]float64, len(sums))
+	for key, sum := range sums {
+		byFamilyRegion[key] = sum / float64(counts[key])
+	}
+	if totalCount > 0 {
+		overall = totalSum / float64(totalCount)
+	}
+
+	return byFamilyRegion, overall
+}
+
 // AnalyzeComputeSavingsPlan determines if a global Compute SP overlay should exist.
 //
 // Compute SPs apply to ALL instance families and ALL regions, so the overlay targets
@@ -265,18 +448,25 @@ func AggregateReservedInstances(ris []prometheus.ReservedInstance) map[string]Ag
 func (e *DecisionEngine) AnalyzeComputeSavingsPlan(
 	agg AggregatedSavingsPlan,
 ) Decision {
+	overlays := e.Config.Current().Overlays
+
 	// Generate overlay name using configured prefix
-	prefix := e.Config.Overlays.Naming.ComputeSavingsPlanPrefix
+	prefix := overlays.Naming.ComputeSavingsPlanPrefix
 	if prefix == "" {
 		prefix = config.DefaultOverlayNamingComputeSPPrefix
 	}
 	overlayName := fmt.Sprintf("%s-global", prefix)
 
+	// Compute SPs cover every instance type, so discount each type's own on-demand price
+	discount := discountPercent(overlays, CapacityTypeComputeSavingsPlan)
+	agg = deductPendingLaunches(agg, discount)
+
 	decision := Decision{
 		Name:               overlayName,
 		CapacityType:       CapacityTypeComputeSavingsPlan,
-		Weight:             e.Config.Overlays.Weights.ComputeSavingsPlan,
-		Price:              "0.00", // 100% discount for Phase 2
+		Weight:             overlays.Weights.ComputeSavingsPlan,
+		PriceAdjustment:    formatDiscountAdjustment(discount),
+		DiscountPercent:    discount,
 		TargetSelector:     "karpenter.k8s.aws/instance-family: Exists, karpenter.sh/capacity-type: In [on-demand]",
 		UtilizationPercent: agg.UtilizationPercent,
 		RemainingCapacity:  agg.TotalRemainingCapacity,
@@ -285,19 +475,7 @@ func (e *DecisionEngine) AnalyzeComputeSavingsPlan(
 	// Decision logic: overlay exists if BOTH conditions are true:
 	// 1. Utilization below threshold
 	// 2. Remaining capacity available
-	threshold := e.Config.Overlays.UtilizationThreshold
-
-	if agg.UtilizationPercent >= threshold {
-		decision.ShouldExist = false
-		decision.Reason = fmt.Sprintf("utilization %.1f%% at/above threshold %.1f%%", agg.UtilizationPercent, threshold)
-	} else if agg.TotalRemainingCapacity <= 0 {
-		decision.ShouldExist = false
-		decision.Reason = fmt.Sprintf("no remaining capacity (%.2f $/hour)", agg.TotalRemainingCapacity)
-	} else {
-		decision.ShouldExist = true
-		decision.Reason = fmt.Sprintf("utilization %.1f%% below threshold %.1f%%, capacity available (%.2f $/hour)",
-			agg.UtilizationPercent, threshold, agg.TotalRemainingCapacity)
-	}
+	decideSavingsPlan(overlays, &decision, agg, discount)
 
 	return decision
 }
@@ -311,18 +489,27 @@ func (e *DecisionEngine) AnalyzeComputeSavingsPlan(
 func (e *DecisionEngine) AnalyzeEC2InstanceSavingsPlan(
 	agg AggregatedSavingsPlan,
 ) Decision {
+	overlays := e.Config.Current().Overlays
+
 	// Generate unique name per family and region using configured prefix
-	prefix := e.Config.Overlays.Naming.EC2InstanceSavingsPlanPrefix
+	prefix := overlays.Naming.EC2InstanceSavingsPlanPrefix
 	if prefix == "" {
 		prefix = config.DefaultOverlayNamingEC2InstanceSPPrefix
 	}
 	overlayName := fmt.Sprintf("%s-%s-%s", prefix, agg.InstanceFamily, agg.Region)
 
+	// EC2 Instance SPs cover every size in the family, so discount each type's own on-demand price
+	discount := discountPercent(overlays, CapacityTypeEC2InstanceSavingsPlan)
+	agg = deductPendingLaunches(agg, discount)
+
 	decision := Decision{
-		Name:         overlayName,
-		CapacityType: CapacityTypeEC2InstanceSavingsPlan,
-		Weight:       e.Config.Overlays.Weights.EC2InstanceSavingsPlan,
-		Price:        "0.00", // 100% discount for Phase 2
+		Name:            overlayName,
+		CapacityType:    CapacityTypeEC2InstanceSavingsPlan,
+		Weight:          overlays.Weights.EC2InstanceSavingsPlan,
+		PriceAdjustment: formatDiscountAdjustment(discount),
+		DiscountPercent: discount,
+		InstanceFamily:  agg.InstanceFamily,
+		Region:          agg.Region,
 		TargetSelector: fmt.Sprintf(
 			"karpenter.k8s.aws/instance-family: In [%s], karpenter.sh/capacity-type: In [on-demand]",
 			agg.InstanceFamily,
@@ -331,60 +518,239 @@ func (e *DecisionEngine) AnalyzeEC2InstanceSavingsPlan(
 		RemainingCapacity:  agg.TotalRemainingCapacity,
 	}
 
-	threshold := e.Config.Overlays.UtilizationThreshold
-
-	if agg.UtilizationPercent >= threshold {
-		decision.ShouldExist = false
-		decision.Reason = fmt.Sprintf("utilization %.1f%% at/above threshold %.1f%%", agg.UtilizationPercent, threshold)
-	} else if agg.TotalRemainingCapacity <= 0 {
-		decision.ShouldExist = false
-		decision.Reason = fmt.Sprintf("no remaining capacity (%.2f $/hour)", agg.TotalRemainingCapacity)
-	} else {
-		decision.ShouldExist = true
-		decision.Reason = fmt.Sprintf("utilization %.1f%% below threshold %.1f%%, capacity available (%.2f $/hour)",
-			agg.UtilizationPercent, threshold, agg.TotalRemainingCapacity)
+	decideSavingsPlan(overlays, &decision, agg, discount)
+
+	// Only cover the sizes whose discounted hourly cost fits within the remaining commitment.
+	// Otherwise a few cents of remaining capacity would discount every size in the family
+	// and Karpenter would launch far more than the commitment covers.
+	if len(agg.InstanceTypePrices) > 0 {
+		fitting := InstanceTypesWithinBudget(agg.InstanceTypePrices, discount, agg.TotalRemainingCapacity)
+		switch {
+		case len(fitting) == 0:
+			if decision.ShouldExist || decision.WithinHysteresisBand {
+				decision.ShouldExist = false
+				decision.WithinHysteresisBand = false
+				decision.Reason = fmt.Sprintf("no remaining capacity for any %s size (%.2f $/hour)",
+					agg.InstanceFamily, agg.TotalRemainingCapacity)
+			}
+		case len(fitting) < len(agg.InstanceTypePrices):
+			decision.InstanceTypes = fitting
+			decision.TargetSelector = fmt.Sprintf(
+				"node.kubernetes.io/instance-type: In [%s], karpenter.sh/capacity-type: In [on-demand]",
+				strings.Join(fitting, ", "),
+			)
+		}
 	}
 
 	return decision
 }
 
+// InstanceTypesWithinBudget returns the instance types whose discounted on-demand price
+// fits within the remaining $/hour budget, sorted by name.
+//
+// prices maps instance type to on-demand price in $/hour. A type fits when
+// price * (1 - discount/100) <= remaining, since that is how much commitment one
+// running instance consumes per hour.
+func InstanceTypesWithinBudget(prices map[string]float64, discount, remaining float64) []string {
+	var fitting []string
+	for instanceType, price := range prices {
+		if price*(1-discount/100) <= remaining {
+			fitting = append(fitting, instanceType)
+		}
+	}
+	sort.Strings(fitting)
+	return fitting
+}
+
+// InstanceTypePricesForFamily selects the on-demand prices of one instance family in one region.
+//
+// onDemandPrices is keyed by "instanceType:region" (see AggregateOnDemandPrices).
+// Returns a map of instance type -> price in $/hour, or nil if no prices match.
+func InstanceTypePricesForFamily(onDemandPrices map[string]float64, family, region string) map[string]float64 {
+	var prices map[string]float64
+	for key, price := range onDemandPrices {
+		instanceType, keyRegion, found := strings.Cut(key, ":")
+		if !found || keyRegion != region {
+			continue
+		}
+		if keyFamily, _, _ := strings.Cut(instanceType, "."); keyFamily != family {
+			continue
+		}
+		if prices == nil {
+			prices = make(map[string]float64)
+		}
+		prices[instanceType] = price
+	}
+	return prices
+}
+
 // AnalyzeReservedInstance determines if an instance-type-specific RI overlay should exist.
 //
-// RIs are binary: either available (count > 0) or not. No utilization percentage.
+// Each running or launching on-demand instance of the type occupies one RI. The overlay
+// exists while some RIs are unoccupied; utilization is the occupied share of TotalCount.
+//
+// Zonal RIs produce a separate overlay named after their AZ (e.g. "cost-aware-ri-m5.xlarge-us-west-2a")
+// so it never collides with the region-wide overlay for regional RIs of the same type.
 //
 // NOTE: This method now expects aggregated metrics. Call AggregateReservedInstances()
 // first to combine multiple RIs for the same instance type+region across AZs before calling this method.
 func (e *DecisionEngine) AnalyzeReservedInstance(agg AggregatedReservedInstance) Decision {
-	// Generate unique name per instance type and region using configured prefix
-	prefix := e.Config.Overlays.Naming.ReservedInstancePrefix
+	overlays := e.Config.Current().Overlays
+
+	// Generate unique name per instance type and region (or zone) using configured prefix
+	prefix := overlays.Naming.ReservedInstancePrefix
 	if prefix == "" {
 		prefix = config.DefaultOverlayNamingReservedInstancePrefix
 	}
-	overlayName := fmt.Sprintf("%s-%s-%s", prefix, agg.InstanceType, agg.Region)
+	scope := agg.Region
+	if agg.AvailabilityZone != "" {
+		scope = agg.AvailabilityZone
+	}
+	overlayName := fmt.Sprintf("%s-%s-%s", prefix, agg.InstanceType, scope)
+
+	discount := discountPercent(overlays, CapacityTypeReservedInstance)
+	family, _, _ := strings.Cut(agg.InstanceType, ".")
 
 	decision := Decision{
-		Name:         overlayName,
-		CapacityType: CapacityTypeReservedInstance,
-		Weight:       e.Config.Overlays.Weights.ReservedInstance,
-		Price:        "0.00", // 100% discount for Phase 2
+		Name:            overlayName,
+		CapacityType:    CapacityTypeReservedInstance,
+		Weight:          overlays.Weights.ReservedInstance,
+		DiscountPercent: discount,
+		InstanceFamily:  family,
+		InstanceType:    agg.InstanceType,
+		Region:          agg.Region,
 		TargetSelector: fmt.Sprintf("node.kubernetes.io/instance-type: In [%s], karpenter.sh/capacity-type: In [on-demand]",
 			agg.InstanceType),
-		UtilizationPercent: 0, // RIs don't have utilization metrics
-		RemainingCapacity:  0, // RIs tracked by count, not $/hour
+		RemainingCapacity: 0, // RIs tracked by count, not $/hour
+		AvailabilityZone:  agg.AvailabilityZone,
+	}
+	if agg.AvailabilityZone != "" {
+		decision.TargetSelector = fmt.Sprintf(
+			"node.kubernetes.io/instance-type: In [%s], topology.kubernetes.io/zone: In [%s], karpenter.sh/capacity-type: In [on-demand]",
+			agg.InstanceType, agg.AvailabilityZone)
 	}
 
-	// Decision logic: overlay exists if RI count > 0
+	inUse := agg.RunningCount + agg.PendingLaunchCount
 	if agg.TotalCount > 0 {
-		decision.ShouldExist = true
-		decision.Reason = fmt.Sprintf("%d reserved instances available", agg.TotalCount)
+		decision.UtilizationPercent = float64(inUse) / float64(agg.TotalCount) * 100
+	}
+
+	// RIs cover exactly one instance type, so advertise the actual post-discount price when known
+	if agg.OnDemandPrice > 0 {
+		decision.Price = formatDiscountedPrice(agg.OnDemandPrice, discount)
 	} else {
+		decision.PriceAdjustment = formatDiscountAdjustment(discount)
+	}
+
+	// Decision logic: overlay exists while some RIs are unoccupied, unless spot undercuts the
+	// covered price. The comparison needs the on-demand price to know the covered price.
+	coveredPrice := agg.OnDemandPrice * (1 - discount/100)
+	available := agg.TotalCount - inUse
+	if agg.TotalCount <= 0 {
 		decision.ShouldExist = false
 		decision.Reason = "no reserved instances available"
+	} else if available <= 0 {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("reserved instances fully utilized (%d of %d in use: %d running, %d launching)",
+			inUse, agg.TotalCount, agg.RunningCount, agg.PendingLaunchCount)
+	} else if agg.SpotPrice > 0 && agg.OnDemandPrice > 0 && agg.SpotPrice < coveredPrice {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("spot cheaper than covered price (spot %.4f $/hour, covered %.4f $/hour)",
+			agg.SpotPrice, coveredPrice)
+	} else {
+		decision.ShouldExist = true
+		decision.Reason = fmt.Sprintf("%d reserved instances available", available)
 	}
 
 	return decision
 }
 
+// decideSavingsPlan applies the utilization, capacity, and spot rules shared by both SP types.
+//
+// Utilization at/above the delete threshold removes the overlay and utilization below the
+// create threshold creates it. In between (the hysteresis band) the decision is marked
+// WithinHysteresisBand so a HysteresisTracker can keep the overlay in its current state;
+// on its own the decision defaults to not creating the overlay.
+func decideSavingsPlan(
+	overlays config.OverlayManagementConfig, decision *Decision, agg AggregatedSavingsPlan, discount float64,
+) {
+	createThreshold, deleteThreshold := overlays.UtilizationThresholds()
+
+	if agg.UtilizationPercent >= deleteThreshold {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("utilization %.1f%% at/above threshold %.1f%%", agg.UtilizationPercent, deleteThreshold)
+	} else if agg.TotalRemainingCapacity <= 0 {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("no remaining capacity (%.2f $/hour)", agg.TotalRemainingCapacity)
+	} else if agg.SpotSavingsPercent > discount {
+		// Spot undercuts the commitment-covered rate, so steering workloads to covered
+		// on-demand capacity would cost more than letting Karpenter buy spot
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("spot cheaper than covered price (spot %.1f%% below on-demand, commitment discount %.1f%%)",
+			agg.SpotSavingsPercent, discount)
+	} else if agg.UtilizationPercent >= createThreshold {
+		decision.ShouldExist = false
+		decision.WithinHysteresisBand = true
+		decision.Reason = fmt.Sprintf("utilization %.1f%% within hysteresis band [%.1f%%, %.1f%%)",
+			agg.UtilizationPercent, createThreshold, deleteThreshold)
+	} else {
+		decision.ShouldExist = true
+		decision.Reason = fmt.Sprintf("utilization %.1f%% below threshold %.1f%%, capacity available (%.2f $/hour)",
+			agg.UtilizationPercent, createThreshold, agg.TotalRemainingCapacity)
+	}
+
+	if agg.PendingLaunchCost > 0 {
+		decision.Reason += fmt.Sprintf(" after %.2f $/hour of launches since last Lumina refresh", agg.PendingLaunchCost)
+	}
+}
+
+// deductPendingLaunches removes the commitment consumed by launches Lumina hasn't seen yet
+// from an aggregated SP and recomputes its utilization.
+//
+// A covered instance consumes its discounted on-demand price from the commitment each hour.
+// Remaining capacity is clamped at zero, so a burst of launches reads as 100% utilization.
+func deductPendingLaunches(agg AggregatedSavingsPlan, discount float64) AggregatedSavingsPlan {
+	if agg.PendingLaunchCost <= 0 {
+		return agg
+	}
+
+	agg.TotalRemainingCapacity -= agg.PendingLaunchCost * (1 - discount/100)
+	if agg.TotalRemainingCapacity < 0 {
+		agg.TotalRemainingCapacity = 0
+	}
+	if agg.TotalHourlyCommitment > 0 {
+		agg.UtilizationPercent = (1 - (agg.TotalRemainingCapacity / agg.TotalHourlyCommitment)) * 100
+	}
+	return agg
+}
+
+// discountPercent returns the configured discount for a capacity type,
+// falling back to the default when the config leaves it unset.
+func discountPercent(overlays config.OverlayManagementConfig, ct CapacityType) float64 {
+	reservedInstance, ec2InstanceSavingsPlan, computeSavingsPlan := overlays.Discounts.Percentages()
+	switch ct {
+	case CapacityTypeComputeSavingsPlan:
+		return computeSavingsPlan
+	case CapacityTypeEC2InstanceSavingsPlan:
+		return ec2InstanceSavingsPlan
+	case CapacityTypeReservedInstance:
+		return reservedInstance
+	}
+	return 0
+}
+
+// formatDiscountAdjustment converts a discount percentage to a Karpenter priceAdjustment string.
+// Examples: 28 -> "-28%", 37.5 -> "-37.5%", 100 -> "-100%"
+func formatDiscountAdjustment(discount float64) string {
+	return "-" + strconv.FormatFloat(discount, 'f', -1, 64) + "%"
+}
+
+// formatDiscountedPrice applies a discount percentage to an on-demand price and formats it
+// as a Karpenter price string with 4 decimal places (e.g., 0.192 at 37% -> "0.1210").
+func formatDiscountedPrice(onDemandPrice, discount float64) string {
+	return fmt.Sprintf("%.4f", onDemandPrice*(1-discount/100))
+}
+
 // AnalyzeComputeSavingsPlanSingle is a convenience wrapper for analyzing a single Compute SP.
 // For production code with multiple SPs, use AggregateComputeSavingsPlans() + AnalyzeComputeSavingsPlan().
 func (e *DecisionEngine) AnalyzeComputeSavingsPlanSingle(
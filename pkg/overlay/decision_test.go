@@ -17,14 +17,17 @@ limitations under the License.
 package overlay
 
 import (
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/nextdoor/veneer/pkg/config"
 	"github.com/nextdoor/veneer/pkg/prometheus"
+	"k8s.io/utils/ptr"
 )
 
 const (
-	// Expected price value for all test cases (overlay price is not yet implemented)
+	// Expected price value for generator test decisions that carry an absolute price
 	expectedTestPrice = "0.00"
 )
 
@@ -141,8 +144,12 @@ func TestAnalyzeComputeSavingsPlan(t *testing.T) {
 				t.Errorf("Weight = %d, want 10", decision.Weight)
 			}
 
-			if decision.Price != expectedTestPrice {
-				t.Errorf("Price = %q, want %q", decision.Price, expectedTestPrice)
+			if decision.Price != "" {
+				t.Errorf("Price = %q, want empty", decision.Price)
+			}
+
+			if decision.PriceAdjustment != "-28%" {
+				t.Errorf("PriceAdjustment = %q, want %q", decision.PriceAdjustment, "-28%")
 			}
 
 			if decision.ShouldExist != tt.wantShouldExist {
@@ -268,8 +275,12 @@ func TestAnalyzeEC2InstanceSavingsPlan(t *testing.T) {
 				t.Errorf("Weight = %d, want 20", decision.Weight)
 			}
 
-			if decision.Price != expectedTestPrice {
-				t.Errorf("Price = %q, want %q", decision.Price, expectedTestPrice)
+			if decision.Price != "" {
+				t.Errorf("Price = %q, want empty", decision.Price)
+			}
+
+			if decision.PriceAdjustment != "-37%" {
+				t.Errorf("PriceAdjustment = %q, want %q", decision.PriceAdjustment, "-37%")
 			}
 
 			if decision.ShouldExist != tt.wantShouldExist {
@@ -344,8 +355,12 @@ func TestAnalyzeReservedInstance(t *testing.T) {
 				t.Errorf("Weight = %d, want 30", decision.Weight)
 			}
 
-			if decision.Price != expectedTestPrice {
-				t.Errorf("Price = %q, want %q", decision.Price, expectedTestPrice)
+			if decision.Price != "" {
+				t.Errorf("Price = %q, want empty", decision.Price)
+			}
+
+			if decision.PriceAdjustment != "-37%" {
+				t.Errorf("PriceAdjustment = %q, want %q", decision.PriceAdjustment, "-37%")
 			}
 
 			if decision.ShouldExist != tt.wantShouldExist {
@@ -449,3 +464,578 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDecisionEngineWithCustomDiscounts(t *testing.T) {
+	cfg := testConfig()
+	cfg.Overlays.Discounts.ReservedInstance = ptr.To(40.0)
+	cfg.Overlays.Discounts.EC2InstanceSavingsPlan = ptr.To(35.0)
+	cfg.Overlays.Discounts.ComputeSavingsPlan = ptr.To(22.5)
+	engine := NewDecisionEngine(cfg)
+
+	tests := []struct {
+		name                string
+		decision            Decision
+		wantPrice           string
+		wantAdjustment      string
+		wantDiscountPercent float64
+	}{
+		{
+			name: "RI with known on-demand price uses absolute price",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType:  "m5.xlarge",
+				Region:        "us-west-2",
+				TotalCount:    2,
+				OnDemandPrice: 0.192,
+			}),
+			wantPrice:           "0.1152",
+			wantDiscountPercent: 40,
+		},
+		{
+			name: "RI without on-demand price falls back to adjustment",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType: "m5.xlarge",
+				Region:       "us-west-2",
+				TotalCount:   2,
+			}),
+			wantAdjustment:      "-40%",
+			wantDiscountPercent: 40,
+		},
+		{
+			name: "EC2 Instance SP uses adjustment",
+			decision: engine.AnalyzeEC2InstanceSavingsPlan(AggregatedSavingsPlan{
+				Type:                   prometheus.SavingsPlanTypeEC2Instance,
+				InstanceFamily:         "m5",
+				Region:                 "us-west-2",
+				UtilizationPercent:     50.0,
+				TotalRemainingCapacity: 10.0,
+			}),
+			wantAdjustment:      "-35%",
+			wantDiscountPercent: 35,
+		},
+		{
+			name: "Compute SP uses fractional adjustment",
+			decision: engine.AnalyzeComputeSavingsPlan(AggregatedSavingsPlan{
+				Type:                   prometheus.SavingsPlanTypeCompute,
+				UtilizationPercent:     50.0,
+				TotalRemainingCapacity: 10.0,
+			}),
+			wantAdjustment:      "-22.5%",
+			wantDiscountPercent: 22.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.decision.Price != tt.wantPrice {
+				t.Errorf("Price = %q, want %q", tt.decision.Price, tt.wantPrice)
+			}
+			if tt.decision.PriceAdjustment != tt.wantAdjustment {
+				t.Errorf("PriceAdjustment = %q, want %q", tt.decision.PriceAdjustment, tt.wantAdjustment)
+			}
+			if tt.decision.DiscountPercent != tt.wantDiscountPercent {
+				t.Errorf("DiscountPercent = %v, want %v", tt.decision.DiscountPercent, tt.wantDiscountPercent)
+			}
+		})
+	}
+}
+
+func TestDecisionEngineWithZeroDiscount(t *testing.T) {
+	// A configured 0% discount is honored rather than replaced by the default
+	cfg := testConfig()
+	cfg.Overlays.Discounts.ReservedInstance = ptr.To(0.0)
+	cfg.Overlays.Discounts.ComputeSavingsPlan = ptr.To(0.0)
+	engine := NewDecisionEngine(cfg)
+
+	ri := engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+		InstanceType:  "m5.xlarge",
+		Region:        "us-west-2",
+		TotalCount:    2,
+		OnDemandPrice: 0.192,
+	})
+	if ri.Price != "0.1920" || ri.DiscountPercent != 0 {
+		t.Errorf("RI Price = %q, DiscountPercent = %v, want the on-demand price with no discount",
+			ri.Price, ri.DiscountPercent)
+	}
+
+	sp := engine.AnalyzeComputeSavingsPlan(AggregatedSavingsPlan{
+		Type:                   prometheus.SavingsPlanTypeCompute,
+		UtilizationPercent:     50.0,
+		TotalRemainingCapacity: 10.0,
+	})
+	if sp.PriceAdjustment != "-0%" || sp.DiscountPercent != 0 {
+		t.Errorf("Compute SP PriceAdjustment = %q, DiscountPercent = %v, want -0%% and 0",
+			sp.PriceAdjustment, sp.DiscountPercent)
+	}
+}
+
+func TestAggregateOnDemandPrices(t *testing.T) {
+	prices := []prometheus.OnDemandPrice{
+		{InstanceType: "m5.xlarge", Region: "us-west-2", Price: 0.192},
+		{InstanceType: "m5.xlarge", Region: "us-west-2", Price: 0.384}, // e.g., Windows; lowest wins
+		{InstanceType: "m5.xlarge", Region: "us-east-1", Price: 0.180},
+		{InstanceType: "c5.large", Region: "us-west-2", Price: 0}, // Missing price is ignored
+	}
+
+	result := AggregateOnDemandPrices(prices)
+
+	if len(result) != 2 {
+		t.Fatalf("got %d entries, want 2: %v", len(result), result)
+	}
+	if got := result["m5.xlarge:us-west-2"]; got != 0.192 {
+		t.Errorf("m5.xlarge:us-west-2 = %v, want 0.192", got)
+	}
+	if got := result["m5.xlarge:us-east-1"]; got != 0.180 {
+		t.Errorf("m5.xlarge:us-east-1 = %v, want 0.180", got)
+	}
+	if _, exists := result["c5.large:us-west-2"]; exists {
+		t.Errorf("c5.large:us-west-2 should be skipped when price is 0")
+	}
+}
+
+func TestDecisionEngineSpotComparison(t *testing.T) {
+	engine := NewDecisionEngine(testConfig())
+
+	availableSP := func(spotSavings float64) AggregatedSavingsPlan {
+		return AggregatedSavingsPlan{
+			InstanceFamily:         "m5",
+			Region:                 "us-west-2",
+			UtilizationPercent:     50.0,
+			TotalRemainingCapacity: 10.0,
+			SpotSavingsPercent:     spotSavings,
+		}
+	}
+
+	tests := []struct {
+		name               string
+		decision           Decision
+		wantShouldExist    bool
+		wantReasonContains string
+	}{
+		{
+			name:               "Compute SP - spot cheaper than covered rate",
+			decision:           engine.AnalyzeComputeSavingsPlan(availableSP(60.0)),
+			wantShouldExist:    false,
+			wantReasonContains: "spot cheaper than covered price",
+		},
+		{
+			name:               "Compute SP - covered rate cheaper than spot",
+			decision:           engine.AnalyzeComputeSavingsPlan(availableSP(20.0)),
+			wantShouldExist:    true,
+			wantReasonContains: "below threshold",
+		},
+		{
+			name:               "Compute SP - no spot data",
+			decision:           engine.AnalyzeComputeSavingsPlan(availableSP(0)),
+			wantShouldExist:    true,
+			wantReasonContains: "below threshold",
+		},
+		{
+			name:               "EC2 Instance SP - spot cheaper than covered rate",
+			decision:           engine.AnalyzeEC2InstanceSavingsPlan(availableSP(45.0)),
+			wantShouldExist:    false,
+			wantReasonContains: "spot cheaper than covered price",
+		},
+		{
+			name:               "EC2 Instance SP - covered rate cheaper than spot",
+			decision:           engine.AnalyzeEC2InstanceSavingsPlan(availableSP(30.0)),
+			wantShouldExist:    true,
+			wantReasonContains: "below threshold",
+		},
+		{
+			name: "RI - spot cheaper than covered price",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType:  "m5.xlarge",
+				Region:        "us-west-2",
+				TotalCount:    2,
+				OnDemandPrice: 0.192,
+				SpotPrice:     0.08, // Covered price is 0.121
+			}),
+			wantShouldExist:    false,
+			wantReasonContains: "spot cheaper than covered price",
+		},
+		{
+			name: "RI - covered price cheaper than spot",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType:  "m5.xlarge",
+				Region:        "us-west-2",
+				TotalCount:    2,
+				OnDemandPrice: 0.192,
+				SpotPrice:     0.15,
+			}),
+			wantShouldExist:    true,
+			wantReasonContains: "reserved instances available",
+		},
+		{
+			name: "RI - spot price without on-demand price cannot be compared",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType: "m5.xlarge",
+				Region:       "us-west-2",
+				TotalCount:   2,
+				SpotPrice:    0.01,
+			}),
+			wantShouldExist:    true,
+			wantReasonContains: "reserved instances available",
+		},
+		{
+			name: "RI - no RIs takes precedence over spot comparison",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType:  "m5.xlarge",
+				Region:        "us-west-2",
+				OnDemandPrice: 0.192,
+				SpotPrice:     0.08,
+			}),
+			wantShouldExist:    false,
+			wantReasonContains: "no reserved instances available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.decision.ShouldExist != tt.wantShouldExist {
+				t.Errorf("ShouldExist = %v, want %v (reason: %s)", tt.decision.ShouldExist, tt.wantShouldExist, tt.decision.Reason)
+			}
+			if !contains(tt.decision.Reason, tt.wantReasonContains) {
+				t.Errorf("Reason = %q, want it to contain %q", tt.decision.Reason, tt.wantReasonContains)
+			}
+		})
+	}
+}
+
+func TestAggregateSpotPrices(t *testing.T) {
+	prices := []prometheus.SpotPrice{
+		{InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2a", Price: 0.08},
+		{InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2b", Price: 0.06}, // Cheapest AZ wins
+		{InstanceType: "c5.large", Region: "us-west-2", AvailabilityZone: "us-west-2a", Price: 0},     // Ignored
+	}
+
+	result := AggregateSpotPrices(prices)
+
+	if len(result) != 1 {
+		t.Fatalf("got %d entries, want 1: %v", len(result), result)
+	}
+	if got := result["m5.xlarge:us-west-2"]; got != 0.06 {
+		t.Errorf("m5.xlarge:us-west-2 = %v, want 0.06", got)
+	}
+}
+
+func TestAggregateSpotSavings(t *testing.T) {
+	spot := map[string]float64{
+		"m5.xlarge:us-west-2":  0.10, // 50% savings
+		"m5.2xlarge:us-west-2": 0.30, // 25% savings
+		"c5.large:us-west-2":   0.05, // 60% savings
+		"r5.large:us-west-2":   0.05, // No on-demand price, ignored
+	}
+	onDemand := map[string]float64{
+		"m5.xlarge:us-west-2":  0.20,
+		"m5.2xlarge:us-west-2": 0.40,
+		"c5.large:us-west-2":   0.125,
+	}
+
+	byFamily, overall := AggregateSpotSavings(spot, onDemand)
+
+	if len(byFamily) != 2 {
+		t.Fatalf("got %d families, want 2: %v", len(byFamily), byFamily)
+	}
+	if got := byFamily["m5:us-west-2"]; math.Abs(got-37.5) > 0.001 {
+		t.Errorf("m5:us-west-2 = %v, want 37.5", got)
+	}
+	if got := byFamily["c5:us-west-2"]; math.Abs(got-60.0) > 0.001 {
+		t.Errorf("c5:us-west-2 = %v, want 60.0", got)
+	}
+	if want := (50.0 + 25.0 + 60.0) / 3; math.Abs(overall-want) > 0.001 {
+		t.Errorf("overall = %v, want %v", overall, want)
+	}
+}
+
+func TestDecisionEngineHysteresisBand(t *testing.T) {
+	cfg := testConfig()
+	cfg.Overlays.UtilizationCreateThreshold = ptr.To(90.0)
+	cfg.Overlays.UtilizationDeleteThreshold = ptr.To(95.0)
+	engine := NewDecisionEngine(cfg)
+
+	tests := []struct {
+		name            string
+		utilization     float64
+		wantShouldExist bool
+		wantBand        bool
+		wantReason      string
+	}{
+		{"below create threshold", 85.0, true, false, "below threshold 90.0%"},
+		{"at create threshold", 90.0, false, true, "within hysteresis band [90.0%, 95.0%)"},
+		{"inside band", 93.0, false, true, "within hysteresis band"},
+		{"at delete threshold", 95.0, false, false, "at/above threshold 95.0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := AggregatedSavingsPlan{
+				InstanceFamily:         "m5",
+				Region:                 "us-west-2",
+				UtilizationPercent:     tt.utilization,
+				TotalRemainingCapacity: 10.0,
+			}
+
+			for _, decision := range []Decision{
+				engine.AnalyzeComputeSavingsPlan(agg),
+				engine.AnalyzeEC2InstanceSavingsPlan(agg),
+			} {
+				if decision.ShouldExist != tt.wantShouldExist {
+					t.Errorf("%s: ShouldExist = %v, want %v", decision.Name, decision.ShouldExist, tt.wantShouldExist)
+				}
+				if decision.WithinHysteresisBand != tt.wantBand {
+					t.Errorf("%s: WithinHysteresisBand = %v, want %v", decision.Name, decision.WithinHysteresisBand, tt.wantBand)
+				}
+				if !contains(decision.Reason, tt.wantReason) {
+					t.Errorf("%s: Reason = %q, want it to contain %q", decision.Name, decision.Reason, tt.wantReason)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeEC2InstanceSavingsPlan_CapacityProportionalSizing(t *testing.T) {
+	engine := NewDecisionEngine(testConfig())
+
+	// At the default 37% EC2 Instance SP discount each size consumes 63% of its on-demand price
+	prices := map[string]float64{
+		"m5.large":    0.096, // 0.0605 $/hour of commitment
+		"m5.xlarge":   0.192, // 0.1210
+		"m5.2xlarge":  0.384, // 0.2419
+		"m5.24xlarge": 4.608, // 2.9030
+	}
+
+	tests := []struct {
+		name              string
+		remaining         float64
+		prices            map[string]float64
+		wantShouldExist   bool
+		wantInstanceTypes []string
+		wantReason        string
+	}{
+		{
+			name:              "only small sizes fit",
+			remaining:         0.15,
+			prices:            prices,
+			wantShouldExist:   true,
+			wantInstanceTypes: []string{"m5.large", "m5.xlarge"},
+			wantReason:        "capacity available",
+		},
+		{
+			name:              "every size fits - whole family",
+			remaining:         10.0,
+			prices:            prices,
+			wantShouldExist:   true,
+			wantInstanceTypes: nil,
+			wantReason:        "capacity available",
+		},
+		{
+			name:              "no size fits",
+			remaining:         0.05,
+			prices:            prices,
+			wantShouldExist:   false,
+			wantInstanceTypes: nil,
+			wantReason:        "no remaining capacity for any m5 size",
+		},
+		{
+			name:              "no pricing data - whole family",
+			remaining:         0.05,
+			prices:            nil,
+			wantShouldExist:   true,
+			wantInstanceTypes: nil,
+			wantReason:        "capacity available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.AnalyzeEC2InstanceSavingsPlan(AggregatedSavingsPlan{
+				InstanceFamily:         "m5",
+				Region:                 "us-west-2",
+				UtilizationPercent:     50.0,
+				TotalRemainingCapacity: tt.remaining,
+				InstanceTypePrices:     tt.prices,
+			})
+
+			if decision.ShouldExist != tt.wantShouldExist {
+				t.Errorf("ShouldExist = %v, want %v (reason: %s)", decision.ShouldExist, tt.wantShouldExist, decision.Reason)
+			}
+			if !reflect.DeepEqual(decision.InstanceTypes, tt.wantInstanceTypes) {
+				t.Errorf("InstanceTypes = %v, want %v", decision.InstanceTypes, tt.wantInstanceTypes)
+			}
+			if !contains(decision.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want it to contain %q", decision.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestInstanceTypePricesForFamily(t *testing.T) {
+	onDemand := map[string]float64{
+		"m5.large:us-west-2":  0.096,
+		"m5.xlarge:us-west-2": 0.192,
+		"m5.xlarge:us-east-1": 0.180, // Other region
+		"m5d.large:us-west-2": 0.113, // Other family sharing the prefix
+		"c5.large:us-west-2":  0.085,
+	}
+
+	got := InstanceTypePricesForFamily(onDemand, "m5", "us-west-2")
+	want := map[string]float64{"m5.large": 0.096, "m5.xlarge": 0.192}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InstanceTypePricesForFamily() = %v, want %v", got, want)
+	}
+
+	if got := InstanceTypePricesForFamily(onDemand, "r5", "us-west-2"); got != nil {
+		t.Errorf("InstanceTypePricesForFamily() for unknown family = %v, want nil", got)
+	}
+}
+
+func TestDecisionEngineCapacityInUse(t *testing.T) {
+	engine := NewDecisionEngine(testConfig())
+
+	t.Run("savings plan", func(t *testing.T) {
+		// At the default 28% Compute SP discount, each $1.00/hour launched consumes $0.72/hour
+		tests := []struct {
+			name              string
+			pendingCost       float64
+			wantShouldExist   bool
+			wantRemaining     float64
+			wantUtilization   float64
+			wantReasonContent string
+		}{
+			{"no pending launches", 0, true, 2.0, 80.0, "capacity available (2.00 $/hour)"},
+			{"launches fit in remaining capacity", 1.0, true, 1.28, 87.2, "after 1.00 $/hour of launches"},
+			{"launches exhaust remaining capacity", 10.0, false, 0, 100.0, "at/above threshold"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				decision := engine.AnalyzeComputeSavingsPlan(AggregatedSavingsPlan{
+					TotalRemainingCapacity: 2.0,
+					TotalHourlyCommitment:  10.0,
+					UtilizationPercent:     80.0,
+					PendingLaunchCost:      tt.pendingCost,
+				})
+
+				if decision.ShouldExist != tt.wantShouldExist {
+					t.Errorf("ShouldExist = %v, want %v (reason: %s)", decision.ShouldExist, tt.wantShouldExist, decision.Reason)
+				}
+				if math.Abs(decision.RemainingCapacity-tt.wantRemaining) > 1e-9 {
+					t.Errorf("RemainingCapacity = %v, want %v", decision.RemainingCapacity, tt.wantRemaining)
+				}
+				if math.Abs(decision.UtilizationPercent-tt.wantUtilization) > 1e-9 {
+					t.Errorf("UtilizationPercent = %v, want %v", decision.UtilizationPercent, tt.wantUtilization)
+				}
+				if !contains(decision.Reason, tt.wantReasonContent) {
+					t.Errorf("Reason = %q, want it to contain %q", decision.Reason, tt.wantReasonContent)
+				}
+			})
+		}
+	})
+
+	t.Run("reserved instance", func(t *testing.T) {
+		tests := []struct {
+			name              string
+			running           int
+			pendingCount      int
+			wantShouldExist   bool
+			wantUtilization   float64
+			wantReasonContent string
+		}{
+			{"no instances in use", 0, 0, true, 0, "3 reserved instances available"},
+			{"running and launching leave one RI free", 1, 1, true, 200.0 / 3, "1 reserved instances available"},
+			{"running instances occupy every RI", 3, 0, false, 100, "fully utilized (3 of 3 in use: 3 running, 0 launching)"},
+			{"launches occupy the last RI", 2, 1, false, 100, "fully utilized (3 of 3 in use: 2 running, 1 launching)"},
+			{"more instances than RIs", 4, 0, false, 400.0 / 3, "fully utilized (4 of 3 in use"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				decision := engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+					InstanceType:       "m5.xlarge",
+					Region:             "us-west-2",
+					TotalCount:         3,
+					RunningCount:       tt.running,
+					PendingLaunchCount: tt.pendingCount,
+				})
+
+				if decision.ShouldExist != tt.wantShouldExist {
+					t.Errorf("ShouldExist = %v, want %v (reason: %s)", decision.ShouldExist, tt.wantShouldExist, decision.Reason)
+				}
+				if math.Abs(decision.UtilizationPercent-tt.wantUtilization) > 1e-9 {
+					t.Errorf("UtilizationPercent = %v, want %v", decision.UtilizationPercent, tt.wantUtilization)
+				}
+				if !contains(decision.Reason, tt.wantReasonContent) {
+					t.Errorf("Reason = %q, want it to contain %q", decision.Reason, tt.wantReasonContent)
+				}
+			})
+		}
+	})
+}
+
+func TestAggregateReservedInstances_Zonal(t *testing.T) {
+	engine := NewDecisionEngine(testConfig())
+
+	ris := []prometheus.ReservedInstance{
+		{InstanceType: "m5.xlarge", Region: "us-west-2", Count: 2},                                 // Regional
+		{InstanceType: "m5.xlarge", Region: "us-west-2", Count: 1},                                 // Regional
+		{InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2a", Count: 3}, // Zonal
+		{InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2c", Count: 1}, // Zonal
+	}
+
+	aggs := AggregateReservedInstances(ris)
+
+	tests := []struct {
+		key          string
+		wantCount    int
+		wantName     string
+		wantSelector string
+	}{
+		{
+			key:          "m5.xlarge:us-west-2",
+			wantCount:    3,
+			wantName:     "cost-aware-ri-m5.xlarge-us-west-2",
+			wantSelector: "node.kubernetes.io/instance-type: In [m5.xlarge], karpenter.sh/capacity-type: In [on-demand]",
+		},
+		{
+			key:       "m5.xlarge:us-west-2:us-west-2a",
+			wantCount: 3,
+			wantName:  "cost-aware-ri-m5.xlarge-us-west-2a",
+			wantSelector: "node.kubernetes.io/instance-type: In [m5.xlarge], topology.kubernetes.io/zone: In [us-west-2a], " +
+				"karpenter.sh/capacity-type: In [on-demand]",
+		},
+		{
+			key:       "m5.xlarge:us-west-2:us-west-2c",
+			wantCount: 1,
+			wantName:  "cost-aware-ri-m5.xlarge-us-west-2c",
+			wantSelector: "node.kubernetes.io/instance-type: In [m5.xlarge], topology.kubernetes.io/zone: In [us-west-2c], " +
+				"karpenter.sh/capacity-type: In [on-demand]",
+		},
+	}
+
+	if len(aggs) != len(tests) {
+		t.Fatalf("AggregateReservedInstances() returned %d aggregates, want %d: %v", len(aggs), len(tests), aggs)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			agg, ok := aggs[tt.key]
+			if !ok {
+				t.Fatalf("missing aggregate %q", tt.key)
+			}
+			if agg.TotalCount != tt.wantCount {
+				t.Errorf("TotalCount = %d, want %d", agg.TotalCount, tt.wantCount)
+			}
+
+			decision := engine.AnalyzeReservedInstance(agg)
+			if decision.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", decision.Name, tt.wantName)
+			}
+			if decision.TargetSelector != tt.wantSelector {
+				t.Errorf("TargetSelector = %q, want %q", decision.TargetSelector, tt.wantSelector)
+			}
+			if decision.AvailabilityZone != agg.AvailabilityZone {
+				t.Errorf("AvailabilityZone = %q, want %q", decision.AvailabilityZone, agg.AvailabilityZone)
+			}
+		})
+	}
+}
@@ -19,7 +19,10 @@ package overlay
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,12 +54,27 @@ const (
 	LabelCapacityType = "veneer.io/capacity-type"
 
 	// LabelRegion identifies the AWS region this overlay is scoped to.
-	// Set for EC2 Instance SP and RI overlays.
+	// Set for EC2 Instance SP and regional RI overlays.
 	LabelRegion = "veneer.io/region"
 
-	// LabelOptimizationReason explains why this overlay exists.
+	// LabelZone identifies the availability zone this overlay is scoped to.
+	// Only set for zonal Reserved Instance overlays, instead of LabelRegion.
+	// Example values: "us-west-2a", "eu-central-1b"
+	LabelZone = "veneer.io/zone"
+)
+
+// Annotation keys used on Veneer-managed NodeOverlays.
+// Annotations carry volatile decision context that changes every reconcile cycle. They are
+// written whenever the overlay is created or updated, but a change in them alone never
+// triggers an update, so they describe the decision at the time of the last write.
+const (
+	// AnnotationOptimizationReason explains why this overlay exists.
 	// Provides human-readable context for debugging and auditing.
-	LabelOptimizationReason = "veneer.io/optimization-reason"
+	AnnotationOptimizationReason = "veneer.io/optimization-reason"
+
+	// AnnotationUtilizationPercent is the utilization of the backing capacity (0-100)
+	// when the overlay was written.
+	AnnotationUtilizationPercent = "veneer.io/utilization-percent"
 )
 
 // Well-known Kubernetes and Karpenter label keys used in NodeOverlay requirements.
@@ -70,6 +88,9 @@ const (
 	// LabelCapacityTypeKarpenter is the Karpenter label for capacity type (spot vs on-demand).
 	LabelCapacityTypeKarpenter = "karpenter.sh/capacity-type"
 
+	// LabelZoneK8s is the standard Kubernetes label for availability zone.
+	LabelZoneK8s = "topology.kubernetes.io/zone"
+
 	// LabelDisabledKey is the label key used to create an impossible requirement.
 	// When Disabled mode is enabled, overlays include a requirement that this label
 	// must equal "true", but no nodes will ever have this label, so the overlay
@@ -80,6 +101,10 @@ const (
 	LabelDisabledValue = "true"
 )
 
+// priceAdjustmentPattern mirrors the NodeOverlay CRD validation for spec.priceAdjustment:
+// a signed absolute amount, a positive percentage, or a negative percentage down to -100%.
+var priceAdjustmentPattern = regexp.MustCompile(`^(([+-]\d*\.?\d+)|(\+\d*\.?\d+%)|(-\d{1,2}(\.\d+)?%)|(-100%))$`)
+
 // Action constants for GeneratedOverlay.
 const (
 	// ActionCreate indicates a new overlay should be created.
@@ -94,21 +119,35 @@ const (
 // The generator converts Decision structs (which represent whether an overlay should exist)
 // into fully-formed NodeOverlay Kubernetes resources.
 type Generator struct {
-	// Disabled controls whether generated overlays are active or inactive.
+	// disabled controls whether generated overlays are active or inactive.
 	// When true, an impossible requirement is added to each overlay that prevents
 	// it from matching any nodes. This allows testing overlay creation without
 	// affecting Karpenter's provisioning decisions.
-	Disabled bool
+	//
+	// It is atomic so the setting can be changed on config reload while reconcilers generate overlays.
+	disabled atomic.Bool
 }
 
 // NewGenerator creates a new NodeOverlay generator with default settings (enabled).
 func NewGenerator() *Generator {
-	return &Generator{Disabled: false}
+	return &Generator{}
 }
 
 // NewGeneratorWithOptions creates a new NodeOverlay generator with the specified options.
 func NewGeneratorWithOptions(disabled bool) *Generator {
-	return &Generator{Disabled: disabled}
+	g := &Generator{}
+	g.disabled.Store(disabled)
+	return g
+}
+
+// Disabled reports whether generated overlays are disabled.
+func (g *Generator) Disabled() bool {
+	return g.disabled.Load()
+}
+
+// SetDisabled changes whether overlays generated from now on are disabled.
+func (g *Generator) SetDisabled(disabled bool) {
+	g.disabled.Store(disabled)
 }
 
 // GeneratedOverlay wraps a NodeOverlay with additional metadata for dry-run logging.
@@ -132,8 +171,9 @@ type GeneratedOverlay struct {
 // The generated overlay includes:
 //   - Proper naming convention based on capacity type
 //   - Labels for identification and debugging
+//   - Annotations with the decision's reason and utilization
 //   - Requirements to target appropriate instances
-//   - Price set to "0.00" (pre-paid capacity is effectively free)
+//   - Either an absolute Price or a percentage PriceAdjustment, whichever the decision carries
 //   - Weight based on capacity type priority
 func (g *Generator) Generate(decision Decision) *karpenterv1alpha1.NodeOverlay {
 	if !decision.ShouldExist {
@@ -146,16 +186,25 @@ func (g *Generator) Generate(decision Decision) *karpenterv1alpha1.NodeOverlay {
 			Kind:       "NodeOverlay",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   decision.Name,
-			Labels: g.generateLabels(decision),
+			Name:        decision.Name,
+			Labels:      g.generateLabels(decision),
+			Annotations: generateAnnotations(decision),
 		},
 		Spec: karpenterv1alpha1.NodeOverlaySpec{
 			Requirements: g.generateRequirements(decision),
-			Price:        &decision.Price,
 			Weight:       int32Ptr(int32(decision.Weight)),
 		},
 	}
 
+	// Karpenter rejects overlays that set both price and priceAdjustment
+	if decision.Price != "" {
+		price := decision.Price
+		overlay.Spec.Price = &price
+	} else if decision.PriceAdjustment != "" {
+		adjustment := decision.PriceAdjustment
+		overlay.Spec.PriceAdjustment = &adjustment
+	}
+
 	return overlay
 }
 
@@ -195,7 +244,6 @@ func (g *Generator) GenerateAll(decisions []Decision) []GeneratedOverlay {
 // All overlays get:
 //   - managed-by: veneer
 //   - capacity-type: the type of pre-paid capacity
-//   - optimization-reason: human-readable explanation
 //
 // Family-specific overlays (EC2 Instance SP, RI) also get:
 //   - instance-family: the EC2 instance family
@@ -203,55 +251,66 @@ func (g *Generator) GenerateAll(decisions []Decision) []GeneratedOverlay {
 //
 // RI overlays additionally get:
 //   - instance-type: the specific instance type
+//   - zone: the availability zone, instead of region, for zonal RIs
 //
 // When disabled mode is enabled, overlays also get:
 //   - veneer.io/disabled: "true"
 func (g *Generator) generateLabels(decision Decision) map[string]string {
 	labels := map[string]string{
-		LabelManagedBy:          LabelManagedByValue,
-		LabelCapacityType:       capacityTypeToLabelValue(decision.CapacityType),
-		LabelOptimizationReason: sanitizeLabelValue(decision.Reason),
+		LabelManagedBy:    LabelManagedByValue,
+		LabelCapacityType: capacityTypeToLabelValue(decision.CapacityType),
 	}
 
 	// Add disabled label when in disabled mode for easy identification
-	if g.Disabled {
+	if g.Disabled() {
 		labels[LabelDisabledKey] = LabelDisabledValue
 	}
 
-	// Extract instance family and type from the decision name for family-specific overlays
+	// Family-specific overlays carry their scope as structured fields, so any naming prefix works
 	switch decision.CapacityType {
 	case CapacityTypeEC2InstanceSavingsPlan:
-		family, region := parseEC2InstanceSPName(decision.Name)
-		if family != "" {
-			labels[LabelInstanceFamily] = family
+		if decision.InstanceFamily != "" {
+			labels[LabelInstanceFamily] = decision.InstanceFamily
 		}
-		if region != "" {
-			labels[LabelRegion] = region
+		if decision.Region != "" {
+			labels[LabelRegion] = decision.Region
 		}
 
 	case CapacityTypeReservedInstance:
-		instanceType, region := parseRIName(decision.Name)
-		if instanceType != "" {
-			labels[LabelInstanceType] = instanceType
-			// Extract family from instance type (e.g., "m5" from "m5.xlarge")
-			if idx := strings.Index(instanceType, "."); idx > 0 {
-				labels[LabelInstanceFamily] = instanceType[:idx]
-			}
+		if decision.InstanceFamily != "" {
+			labels[LabelInstanceFamily] = decision.InstanceFamily
+		}
+		// Size-flexible RI overlays cover a whole family rather than one type
+		if decision.InstanceType != "" {
+			labels[LabelInstanceType] = decision.InstanceType
 		}
-		if region != "" {
-			labels[LabelRegion] = region
+		// Zonal RIs are scoped to their zone rather than the region
+		if decision.AvailabilityZone != "" {
+			labels[LabelZone] = decision.AvailabilityZone
+		} else if decision.Region != "" {
+			labels[LabelRegion] = decision.Region
 		}
 	}
 
 	return labels
 }
 
+// generateAnnotations creates the annotation map for a NodeOverlay based on the decision.
+// These values change between reconcile cycles, so they are kept out of labels and spec.
+func generateAnnotations(decision Decision) map[string]string {
+	return map[string]string{
+		AnnotationOptimizationReason: decision.Reason,
+		AnnotationUtilizationPercent: strconv.FormatFloat(decision.UtilizationPercent, 'f', 1, 64),
+	}
+}
+
 // generateRequirements creates the NodeSelectorRequirements for targeting instances.
 //
 // The requirements determine which instances this overlay applies to:
 //   - Compute SP (global): All on-demand instances (instance-family Exists)
 //   - EC2 Instance SP: On-demand instances of a specific family
-//   - Reserved Instance: On-demand instances of a specific type
+//   - Reserved Instance: On-demand instances of a specific type (in a specific AZ for zonal RIs),
+//     or of the covered sizes of a family for size-flexible RIs
 //
 // All overlays target on-demand capacity type since SPs and RIs only apply to on-demand.
 //
@@ -265,7 +324,7 @@ func (g *Generator) generateRequirements(decision Decision) []karpenterv1alpha1.
 	// If disabled mode is enabled, add an impossible requirement first.
 	// This requirement demands that nodes have a label that no node will ever have,
 	// ensuring the overlay never matches any instances while still being valid YAML.
-	if g.Disabled {
+	if g.Disabled() {
 		requirements = append(requirements, karpenterv1alpha1.NodeSelectorRequirement{
 			Key:      LabelDisabledKey,
 			Operator: corev1.NodeSelectorOpIn,
@@ -295,27 +354,63 @@ func (g *Generator) generateRequirements(decision Decision) []karpenterv1alpha1.
 
 	case CapacityTypeEC2InstanceSavingsPlan:
 		// EC2 Instance SPs are scoped to a specific instance family
-		family, _ := parseEC2InstanceSPName(decision.Name)
 		requirements = append(requirements,
 			karpenterv1alpha1.NodeSelectorRequirement{
 				Key:      LabelInstanceFamilyKarpenter,
 				Operator: corev1.NodeSelectorOpIn,
-				Values:   []string{family},
+				Values:   []string{decision.InstanceFamily},
 			},
-			capacityTypeReq,
 		)
+		// Narrow to the sizes that fit within the remaining commitment, if restricted
+		if len(decision.InstanceTypes) > 0 {
+			requirements = append(requirements,
+				karpenterv1alpha1.NodeSelectorRequirement{
+					Key:      LabelInstanceTypeK8s,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   append([]string(nil), decision.InstanceTypes...),
+				},
+			)
+		}
+		requirements = append(requirements, capacityTypeReq)
 
 	case CapacityTypeReservedInstance:
+		// Size-flexible RIs cover the sizes of a family that fit within the remaining units
+		if decision.InstanceType == "" {
+			requirements = append(requirements,
+				karpenterv1alpha1.NodeSelectorRequirement{
+					Key:      LabelInstanceFamilyKarpenter,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{decision.InstanceFamily},
+				},
+				karpenterv1alpha1.NodeSelectorRequirement{
+					Key:      LabelInstanceTypeK8s,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   append([]string(nil), decision.InstanceTypes...),
+				},
+				capacityTypeReq,
+			)
+			break
+		}
+
 		// RIs are scoped to a specific instance type
-		instanceType, _ := parseRIName(decision.Name)
 		requirements = append(requirements,
 			karpenterv1alpha1.NodeSelectorRequirement{
 				Key:      LabelInstanceTypeK8s,
 				Operator: corev1.NodeSelectorOpIn,
-				Values:   []string{instanceType},
+				Values:   []string{decision.InstanceType},
 			},
-			capacityTypeReq,
 		)
+		// Zonal RIs only discount instances launched in their own AZ
+		if decision.AvailabilityZone != "" {
+			requirements = append(requirements,
+				karpenterv1alpha1.NodeSelectorRequirement{
+					Key:      LabelZoneK8s,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{decision.AvailabilityZone},
+				},
+			)
+		}
+		requirements = append(requirements, capacityTypeReq)
 
 	default:
 		// Should never happen, but return capacity type requirement as a safeguard
@@ -339,105 +434,19 @@ func capacityTypeToLabelValue(ct CapacityType) string {
 	}
 }
 
-// sanitizeLabelValue ensures a string is valid as a Kubernetes label value.
-// Label values must be 63 characters or less and match the regex:
-// [a-z0-9A-Z]([a-z0-9A-Z-_.]*[a-z0-9A-Z])?
-func sanitizeLabelValue(s string) string {
-	// Replace spaces and special characters with hyphens
-	s = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
-			return r
-		}
-		return '-'
-	}, s)
-
-	// Remove consecutive hyphens
-	for strings.Contains(s, "--") {
-		s = strings.ReplaceAll(s, "--", "-")
-	}
-
-	// Trim leading/trailing hyphens
-	s = strings.Trim(s, "-_.")
-
-	// Truncate to 63 characters
-	if len(s) > 63 {
-		s = s[:63]
-		// Ensure we don't end with a hyphen after truncation
-		s = strings.TrimRight(s, "-_.")
-	}
-
-	// If empty after sanitization, return a default
-	if s == "" {
-		return "capacity-available"
-	}
-
-	return s
-}
-
-// parseEC2InstanceSPName extracts the instance family and region from an EC2 Instance SP overlay name.
-// Expected format: "cost-aware-ec2-sp-{family}-{region}" (e.g., "cost-aware-ec2-sp-m5-us-west-2")
-func parseEC2InstanceSPName(name string) (family, region string) {
-	// Remove the prefix to get "{family}-{region}"
-	const prefix = "cost-aware-ec2-sp-"
-	if !strings.HasPrefix(name, prefix) {
-		return "", ""
-	}
-
-	remainder := strings.TrimPrefix(name, prefix)
-
-	// The family is everything before the last hyphen-delimited region
-	// Regions are like "us-west-2", "eu-central-1", etc.
-	// So we need to find where the family ends and the region begins
-	// Family examples: "m5", "c5", "r6i", "m5a"
-	// This is tricky because both can contain hyphens
-
-	// Strategy: assume the region is the last 3 hyphen-separated segments
-	// (e.g., "us-west-2" = "us" + "west" + "2")
-	parts := strings.Split(remainder, "-")
-	if len(parts) >= 4 {
-		// Last 3 parts are the region
-		family = strings.Join(parts[:len(parts)-3], "-")
-		region = strings.Join(parts[len(parts)-3:], "-")
-	} else if len(parts) >= 2 {
-		// Fallback: first part is family, rest is region
-		family = parts[0]
-		region = strings.Join(parts[1:], "-")
-	}
-
-	return family, region
-}
-
-// parseRIName extracts the instance type and region from a Reserved Instance overlay name.
-// Expected format: "cost-aware-ri-{instance-type}-{region}" (e.g., "cost-aware-ri-m5.xlarge-us-west-2")
-func parseRIName(name string) (instanceType, region string) {
-	// Remove the prefix to get "{instance-type}-{region}"
-	const prefix = "cost-aware-ri-"
-	if !strings.HasPrefix(name, prefix) {
-		return "", ""
-	}
-
-	remainder := strings.TrimPrefix(name, prefix)
-
-	// Instance types contain a dot (e.g., "m5.xlarge", "c5.2xlarge")
-	// Find the dot to identify where the instance type ends
-	dotIdx := strings.Index(remainder, ".")
-	if dotIdx < 0 {
-		return "", ""
-	}
-
-	// Find the next hyphen after the dot to separate instance type from region
-	// The instance type is "m5.xlarge", and everything after the next hyphen is region
-	afterDot := remainder[dotIdx+1:]
-	hyphenIdx := strings.Index(afterDot, "-")
-	if hyphenIdx < 0 {
-		// No region specified
-		return remainder, ""
+// CapacityTypeFromLabelValue converts a veneer.io/capacity-type label value back to a CapacityType.
+// Returns an empty CapacityType if the value is not recognized.
+func CapacityTypeFromLabelValue(value string) CapacityType {
+	switch value {
+	case "compute-savings-plan":
+		return CapacityTypeComputeSavingsPlan
+	case "ec2-instance-savings-plan":
+		return CapacityTypeEC2InstanceSavingsPlan
+	case "reserved-instance":
+		return CapacityTypeReservedInstance
+	default:
+		return ""
 	}
-
-	instanceType = remainder[:dotIdx+1+hyphenIdx]
-	region = afterDot[hyphenIdx+1:]
-
-	return instanceType, region
 }
 
 // int32Ptr returns a pointer to an int32 value.
@@ -464,7 +473,7 @@ func (e ValidationError) Error() string {
 //   - Name is valid (DNS subdomain name)
 //   - Labels are valid (keys and values)
 //   - Requirements are properly formed
-//   - Price is in valid format
+//   - Price and PriceAdjustment are in valid format and not both set
 //   - Weight is within bounds (1-10000)
 func ValidateOverlay(overlay *karpenterv1alpha1.NodeOverlay) []ValidationError {
 	var errors []ValidationError
@@ -567,6 +576,23 @@ func ValidateOverlay(overlay *karpenterv1alpha1.NodeOverlay) []ValidationError {
 		}
 	}
 
+	// Validate price adjustment format
+	if overlay.Spec.PriceAdjustment != nil {
+		adjustment := *overlay.Spec.PriceAdjustment
+		if overlay.Spec.Price != nil {
+			errors = append(errors, ValidationError{
+				Field:   "spec.priceAdjustment",
+				Message: "price and priceAdjustment are mutually exclusive",
+			})
+		}
+		if !priceAdjustmentPattern.MatchString(adjustment) {
+			errors = append(errors, ValidationError{
+				Field:   "spec.priceAdjustment",
+				Message: fmt.Sprintf("priceAdjustment %q must be a signed decimal or percentage (e.g., \"-0.05\", \"-28%%\")", adjustment),
+			})
+		}
+	}
+
 	// Validate weight (1-10000)
 	if overlay.Spec.Weight != nil {
 		w := *overlay.Spec.Weight
@@ -612,6 +638,10 @@ func FormatOverlayYAML(overlay *karpenterv1alpha1.NodeOverlay) string {
 		sb.WriteString(fmt.Sprintf("  price: %q\n", *overlay.Spec.Price))
 	}
 
+	if overlay.Spec.PriceAdjustment != nil {
+		sb.WriteString(fmt.Sprintf("  priceAdjustment: %q\n", *overlay.Spec.PriceAdjustment))
+	}
+
 	if len(overlay.Spec.Requirements) > 0 {
 		sb.WriteString("  requirements:\n")
 		for _, req := range overlay.Spec.Requirements {
@@ -17,9 +17,11 @@ limitations under the License.
 package overlay
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/nextdoor/veneer/pkg/config"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
@@ -99,6 +101,8 @@ func TestGenerator_Generate_EC2InstanceSavingsPlan(t *testing.T) {
 
 	decision := Decision{
 		Name:               "cost-aware-ec2-sp-m5-us-west-2",
+		InstanceFamily:     "m5",
+		Region:             "us-west-2",
 		CapacityType:       CapacityTypeEC2InstanceSavingsPlan,
 		ShouldExist:        true,
 		Weight:             20,
@@ -148,15 +152,59 @@ func TestGenerator_Generate_EC2InstanceSavingsPlan(t *testing.T) {
 	}
 }
 
+func TestGenerator_Generate_EC2InstanceSavingsPlan_RestrictedSizes(t *testing.T) {
+	g := NewGenerator()
+
+	decision := Decision{
+		Name:            "cost-aware-ec2-sp-m5-us-west-2",
+		InstanceFamily:  "m5",
+		Region:          "us-west-2",
+		CapacityType:    CapacityTypeEC2InstanceSavingsPlan,
+		ShouldExist:     true,
+		Weight:          20,
+		PriceAdjustment: "-37%",
+		InstanceTypes:   []string{"m5.large", "m5.xlarge"},
+	}
+
+	overlay := g.Generate(decision)
+
+	if overlay == nil {
+		t.Fatal("expected overlay to be generated, got nil")
+	}
+
+	// Family, instance type, and capacity type requirements
+	if len(overlay.Spec.Requirements) != 3 {
+		t.Fatalf("expected 3 requirements, got %d", len(overlay.Spec.Requirements))
+	}
+
+	typeReq := overlay.Spec.Requirements[1]
+	if typeReq.Key != LabelInstanceTypeK8s {
+		t.Errorf("expected second requirement key %q, got %q", LabelInstanceTypeK8s, typeReq.Key)
+	}
+	if typeReq.Operator != corev1.NodeSelectorOpIn {
+		t.Errorf("expected second requirement operator %q, got %q", corev1.NodeSelectorOpIn, typeReq.Operator)
+	}
+	if len(typeReq.Values) != 2 || typeReq.Values[0] != "m5.large" || typeReq.Values[1] != "m5.xlarge" {
+		t.Errorf("expected second requirement values [m5.large m5.xlarge], got %v", typeReq.Values)
+	}
+
+	if errs := ValidateOverlay(overlay); len(errs) > 0 {
+		t.Errorf("expected valid overlay, got errors: %v", errs)
+	}
+}
+
 func TestGenerator_Generate_ReservedInstance(t *testing.T) {
 	g := NewGenerator()
 
 	decision := Decision{
-		Name:         "cost-aware-ri-m5.xlarge-us-west-2",
-		CapacityType: CapacityTypeReservedInstance,
-		ShouldExist:  true,
-		Weight:       30,
-		Price:        "0.00",
+		Name:           "cost-aware-ri-m5.xlarge-us-west-2",
+		InstanceFamily: "m5",
+		InstanceType:   "m5.xlarge",
+		Region:         "us-west-2",
+		CapacityType:   CapacityTypeReservedInstance,
+		ShouldExist:    true,
+		Weight:         30,
+		Price:          "0.00",
 		TargetSelector: "node.kubernetes.io/instance-type: In [m5.xlarge], " +
 			"karpenter.sh/capacity-type: In [on-demand]",
 		Reason: "3 reserved instances available",
@@ -204,6 +252,100 @@ func TestGenerator_Generate_ReservedInstance(t *testing.T) {
 	}
 }
 
+func TestGenerator_Generate_ZonalReservedInstance(t *testing.T) {
+	g := NewGenerator()
+
+	decision := Decision{
+		Name:             "cost-aware-ri-m5.xlarge-us-west-2a",
+		InstanceFamily:   "m5",
+		InstanceType:     "m5.xlarge",
+		Region:           "us-west-2",
+		CapacityType:     CapacityTypeReservedInstance,
+		ShouldExist:      true,
+		Weight:           30,
+		Price:            "0.1210",
+		AvailabilityZone: "us-west-2a",
+		Reason:           "2 reserved instances available",
+	}
+
+	overlay := g.Generate(decision)
+
+	if overlay.Labels[LabelZone] != "us-west-2a" {
+		t.Errorf("expected zone label %q, got %q", "us-west-2a", overlay.Labels[LabelZone])
+	}
+	if region, exists := overlay.Labels[LabelRegion]; exists {
+		t.Errorf("expected no region label on zonal overlay, got %q", region)
+	}
+	if overlay.Labels[LabelInstanceType] != "m5.xlarge" {
+		t.Errorf("expected instance-type label %q, got %q", "m5.xlarge", overlay.Labels[LabelInstanceType])
+	}
+
+	// Requirements: instance type, zone, capacity type
+	if len(overlay.Spec.Requirements) != 3 {
+		t.Fatalf("expected 3 requirements, got %d", len(overlay.Spec.Requirements))
+	}
+	zoneReq := overlay.Spec.Requirements[1]
+	if zoneReq.Key != LabelZoneK8s || zoneReq.Operator != corev1.NodeSelectorOpIn ||
+		len(zoneReq.Values) != 1 || zoneReq.Values[0] != "us-west-2a" {
+		t.Errorf("expected zone requirement %s In [us-west-2a], got %s %s %v",
+			LabelZoneK8s, zoneReq.Key, zoneReq.Operator, zoneReq.Values)
+	}
+
+	if errs := ValidateOverlay(overlay); len(errs) > 0 {
+		t.Errorf("expected valid overlay, got errors: %v", errs)
+	}
+}
+
+func TestGenerator_Generate_SizeFlexibleReservedInstance(t *testing.T) {
+	g := NewGenerator()
+
+	decision := Decision{
+		Name:            "cost-aware-ri-m5-us-west-2",
+		CapacityType:    CapacityTypeReservedInstance,
+		ShouldExist:     true,
+		Weight:          30,
+		PriceAdjustment: "-37%",
+		InstanceFamily:  "m5",
+		Region:          "us-west-2",
+		InstanceTypes:   []string{"m5.large", "m5.xlarge"},
+		Reason:          "12.00 normalized units of reserved instances available",
+	}
+
+	overlay := g.Generate(decision)
+
+	if overlay.Labels[LabelInstanceFamily] != "m5" {
+		t.Errorf("expected instance-family label %q, got %q", "m5", overlay.Labels[LabelInstanceFamily])
+	}
+	if overlay.Labels[LabelRegion] != "us-west-2" {
+		t.Errorf("expected region label %q, got %q", "us-west-2", overlay.Labels[LabelRegion])
+	}
+	if instanceType, exists := overlay.Labels[LabelInstanceType]; exists {
+		t.Errorf("expected no instance-type label on family overlay, got %q", instanceType)
+	}
+
+	// Requirements: instance family, covered instance types, capacity type
+	if len(overlay.Spec.Requirements) != 3 {
+		t.Fatalf("expected 3 requirements, got %d", len(overlay.Spec.Requirements))
+	}
+	familyReq := overlay.Spec.Requirements[0]
+	if familyReq.Key != LabelInstanceFamilyKarpenter || len(familyReq.Values) != 1 || familyReq.Values[0] != "m5" {
+		t.Errorf("expected family requirement %s In [m5], got %s %v",
+			LabelInstanceFamilyKarpenter, familyReq.Key, familyReq.Values)
+	}
+	typeReq := overlay.Spec.Requirements[1]
+	if typeReq.Key != LabelInstanceTypeK8s || !reflect.DeepEqual(typeReq.Values, decision.InstanceTypes) {
+		t.Errorf("expected instance-type requirement %s In %v, got %s %v",
+			LabelInstanceTypeK8s, decision.InstanceTypes, typeReq.Key, typeReq.Values)
+	}
+	if overlay.Spec.PriceAdjustment == nil || *overlay.Spec.PriceAdjustment != "-37%" {
+		t.Errorf("expected price adjustment -37%%, got %v", overlay.Spec.PriceAdjustment)
+	}
+
+	if errs := ValidateOverlay(overlay); len(errs) > 0 {
+		t.Errorf("expected valid overlay, got errors: %v", errs)
+	}
+}
+
 func TestGenerator_Generate_ShouldNotExist(t *testing.T) {
 	g := NewGenerator()
 
@@ -236,20 +378,25 @@ func TestGenerator_GenerateAll(t *testing.T) {
 			Reason:       "capacity available",
 		},
 		{
-			Name:         "cost-aware-ec2-sp-m5-us-west-2",
-			CapacityType: CapacityTypeEC2InstanceSavingsPlan,
-			ShouldExist:  false, // Should be marked for deletion
-			Weight:       20,
-			Price:        "0.00",
-			Reason:       "no remaining capacity",
+			Name:           "cost-aware-ec2-sp-m5-us-west-2",
+			InstanceFamily: "m5",
+			Region:         "us-west-2",
+			CapacityType:   CapacityTypeEC2InstanceSavingsPlan,
+			ShouldExist:    false, // Should be marked for deletion
+			Weight:         20,
+			Price:          "0.00",
+			Reason:         "no remaining capacity",
 		},
 		{
-			Name:         "cost-aware-ri-c5.large-us-east-1",
-			CapacityType: CapacityTypeReservedInstance,
-			ShouldExist:  true,
-			Weight:       30,
-			Price:        "0.00",
-			Reason:       "2 reserved instances available",
+			Name:           "cost-aware-ri-c5.large-us-east-1",
+			InstanceFamily: "c5",
+			InstanceType:   "c5.large",
+			Region:         "us-east-1",
+			CapacityType:   CapacityTypeReservedInstance,
+			ShouldExist:    true,
+			Weight:         30,
+			Price:          "0.00",
+			Reason:         "2 reserved instances available",
 		},
 	}
 
@@ -284,136 +431,152 @@ func TestGenerator_GenerateAll(t *testing.T) {
 	}
 }
 
-func TestParseEC2InstanceSPName(t *testing.T) {
+func TestGenerator_CustomNamingPrefixes(t *testing.T) {
+	cfg := testConfig()
+	cfg.Overlays.Naming = config.OverlayNamingConfig{
+		ReservedInstancePrefix:       "acme-reserved",
+		EC2InstanceSavingsPlanPrefix: "acme-ec2sp",
+		ComputeSavingsPlanPrefix:     "acme-compute",
+	}
+	engine := NewDecisionEngine(cfg)
+	g := NewGenerator()
+
 	tests := []struct {
 		name           string
-		expectedFamily string
-		expectedRegion string
+		decision       Decision
+		wantName       string
+		wantLabels     map[string]string
+		wantTypeValues []string // Values of the instance-type requirement, nil if absent
+		wantFamily     string   // Value of the instance-family In requirement, empty if absent
 	}{
 		{
-			name:           "cost-aware-ec2-sp-m5-us-west-2",
-			expectedFamily: "m5",
-			expectedRegion: "us-west-2",
-		},
-		{
-			name:           "cost-aware-ec2-sp-c5-eu-central-1",
-			expectedFamily: "c5",
-			expectedRegion: "eu-central-1",
+			name: "EC2 Instance SP",
+			decision: engine.AnalyzeEC2InstanceSavingsPlan(AggregatedSavingsPlan{
+				InstanceFamily: "r6i", Region: "ap-southeast-1", TotalRemainingCapacity: 10, UtilizationPercent: 50,
+			}),
+			wantName:   "acme-ec2sp-r6i-ap-southeast-1",
+			wantLabels: map[string]string{LabelInstanceFamily: "r6i", LabelRegion: "ap-southeast-1"},
+			wantFamily: "r6i",
 		},
 		{
-			name:           "cost-aware-ec2-sp-r6i-ap-southeast-1",
-			expectedFamily: "r6i",
-			expectedRegion: "ap-southeast-1",
+			name: "regional RI",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType: "c5.2xlarge", Region: "eu-central-1", TotalCount: 2,
+			}),
+			wantName: "acme-reserved-c5.2xlarge-eu-central-1",
+			wantLabels: map[string]string{
+				LabelInstanceType: "c5.2xlarge", LabelInstanceFamily: "c5", LabelRegion: "eu-central-1",
+			},
+			wantTypeValues: []string{"c5.2xlarge"},
 		},
 		{
-			name:           "cost-aware-ec2-sp-m5a-us-east-1",
-			expectedFamily: "m5a",
-			expectedRegion: "us-east-1",
+			name: "zonal RI",
+			decision: engine.AnalyzeReservedInstance(AggregatedReservedInstance{
+				InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2b", TotalCount: 1,
+			}),
+			wantName: "acme-reserved-m5.xlarge-us-west-2b",
+			wantLabels: map[string]string{
+				LabelInstanceType: "m5.xlarge", LabelInstanceFamily: "m5", LabelZone: "us-west-2b",
+			},
+			wantTypeValues: []string{"m5.xlarge"},
 		},
 		{
-			name:           "invalid-prefix-m5-us-west-2",
-			expectedFamily: "",
-			expectedRegion: "",
+			name: "size-flexible RI",
+			decision: engine.AnalyzeReservedInstanceFamily(AggregatedReservedInstanceFamily{
+				InstanceFamily: "m5", Region: "us-west-2", TotalUnits: 8, InstanceTypes: []string{"m5.large", "m5.xlarge"},
+			}),
+			wantName:       "acme-reserved-m5-us-west-2",
+			wantLabels:     map[string]string{LabelInstanceFamily: "m5", LabelRegion: "us-west-2"},
+			wantTypeValues: []string{"m5.large", "m5.xlarge"},
+			wantFamily:     "m5",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			family, region := parseEC2InstanceSPName(tc.name)
-			if family != tc.expectedFamily {
-				t.Errorf("expected family %q, got %q", tc.expectedFamily, family)
+			if !tc.decision.ShouldExist {
+				t.Fatalf("expected decision to create an overlay, got reason %q", tc.decision.Reason)
+			}
+
+			overlay := g.Generate(tc.decision)
+			if overlay.Name != tc.wantName {
+				t.Errorf("expected name %q, got %q", tc.wantName, overlay.Name)
+			}
+			for key, want := range tc.wantLabels {
+				if got := overlay.Labels[key]; got != want {
+					t.Errorf("expected label %s=%q, got %q", key, want, got)
+				}
+			}
+
+			var gotTypeValues []string
+			var gotFamily string
+			for _, req := range overlay.Spec.Requirements {
+				switch {
+				case req.Key == LabelInstanceTypeK8s:
+					gotTypeValues = req.Values
+				case req.Key == LabelInstanceFamilyKarpenter && req.Operator == corev1.NodeSelectorOpIn:
+					gotFamily = strings.Join(req.Values, ",")
+				}
 			}
-			if region != tc.expectedRegion {
-				t.Errorf("expected region %q, got %q", tc.expectedRegion, region)
+			if !reflect.DeepEqual(gotTypeValues, tc.wantTypeValues) {
+				t.Errorf("expected instance-type values %v, got %v", tc.wantTypeValues, gotTypeValues)
+			}
+			if gotFamily != tc.wantFamily {
+				t.Errorf("expected instance-family value %q, got %q", tc.wantFamily, gotFamily)
+			}
+
+			if errs := ValidateOverlay(overlay); len(errs) > 0 {
+				t.Errorf("expected valid overlay, got errors: %v", errs)
 			}
 		})
 	}
 }
 
-func TestParseRIName(t *testing.T) {
-	tests := []struct {
-		name           string
-		expectedType   string
-		expectedRegion string
-	}{
-		{
-			name:           "cost-aware-ri-m5.xlarge-us-west-2",
-			expectedType:   "m5.xlarge",
-			expectedRegion: "us-west-2",
-		},
-		{
-			name:           "cost-aware-ri-c5.2xlarge-eu-central-1",
-			expectedType:   "c5.2xlarge",
-			expectedRegion: "eu-central-1",
-		},
-		{
-			name:           "cost-aware-ri-r6i.large-ap-southeast-1",
-			expectedType:   "r6i.large",
-			expectedRegion: "ap-southeast-1",
-		},
-		{
-			name:           "invalid-prefix-m5.xlarge-us-west-2",
-			expectedType:   "",
-			expectedRegion: "",
-		},
-		{
-			name:           "cost-aware-ri-invalid-no-dot",
-			expectedType:   "",
-			expectedRegion: "",
-		},
+func TestGenerator_Generate_VolatileDataInAnnotations(t *testing.T) {
+	g := NewGenerator()
+
+	decision := Decision{
+		Name:               "cost-aware-compute-sp-global",
+		CapacityType:       CapacityTypeComputeSavingsPlan,
+		ShouldExist:        true,
+		Weight:             10,
+		PriceAdjustment:    "-28%",
+		UtilizationPercent: 42.37,
+		Reason:             "utilization 42.4% below threshold 95.0%",
 	}
 
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			instanceType, region := parseRIName(tc.name)
-			if instanceType != tc.expectedType {
-				t.Errorf("expected instance type %q, got %q", tc.expectedType, instanceType)
-			}
-			if region != tc.expectedRegion {
-				t.Errorf("expected region %q, got %q", tc.expectedRegion, region)
-			}
-		})
+	overlay := g.Generate(decision)
+
+	if got := overlay.Annotations[AnnotationOptimizationReason]; got != decision.Reason {
+		t.Errorf("expected reason annotation %q, got %q", decision.Reason, got)
+	}
+	if got := overlay.Annotations[AnnotationUtilizationPercent]; got != "42.4" {
+		t.Errorf("expected utilization annotation %q, got %q", "42.4", got)
+	}
+
+	// Labels must not change as utilization moves, or every cycle would rewrite the overlay
+	next := decision
+	next.UtilizationPercent = 57.1
+	next.Reason = "utilization 57.1% below threshold 95.0%"
+	if nextOverlay := g.Generate(next); !reflect.DeepEqual(nextOverlay.Labels, overlay.Labels) {
+		t.Errorf("expected labels to be independent of utilization, got %v and %v", overlay.Labels, nextOverlay.Labels)
 	}
 }
 
-func TestSanitizeLabelValue(t *testing.T) {
+func TestCapacityTypeToLabelValue(t *testing.T) {
 	tests := []struct {
-		input    string
+		input    CapacityType
 		expected string
 	}{
-		{
-			input:    "simple",
-			expected: "simple",
-		},
-		{
-			input:    "with spaces",
-			expected: "with-spaces",
-		},
-		{
-			input:    "special!@#$%chars",
-			expected: "special-chars",
-		},
-		{
-			input:    "---leading-trailing---",
-			expected: "leading-trailing",
-		},
-		{
-			input:    strings.Repeat("a", 100),
-			expected: strings.Repeat("a", 63),
-		},
-		{
-			input:    "",
-			expected: "capacity-available",
-		},
-		{
-			input:    "utilization 50.0% below threshold 95.0%",
-			expected: "utilization-50.0-below-threshold-95.0",
-		},
+		{CapacityTypeComputeSavingsPlan, "compute-savings-plan"},
+		{CapacityTypeEC2InstanceSavingsPlan, "ec2-instance-savings-plan"},
+		{CapacityTypeReservedInstance, "reserved-instance"},
+		{CapacityType("unknown"), "unknown"},
 	}
 
 	for _, tc := range tests {
-		t.Run(tc.input, func(t *testing.T) {
-			result := sanitizeLabelValue(tc.input)
+		t.Run(string(tc.input), func(t *testing.T) {
+			result := capacityTypeToLabelValue(tc.input)
 			if result != tc.expected {
 				t.Errorf("expected %q, got %q", tc.expected, result)
 			}
@@ -421,20 +584,21 @@ func TestSanitizeLabelValue(t *testing.T) {
 	}
 }
 
-func TestCapacityTypeToLabelValue(t *testing.T) {
+func TestCapacityTypeFromLabelValue(t *testing.T) {
 	tests := []struct {
-		input    CapacityType
-		expected string
+		input    string
+		expected CapacityType
 	}{
-		{CapacityTypeComputeSavingsPlan, "compute-savings-plan"},
-		{CapacityTypeEC2InstanceSavingsPlan, "ec2-instance-savings-plan"},
-		{CapacityTypeReservedInstance, "reserved-instance"},
-		{CapacityType("unknown"), "unknown"},
+		{"compute-savings-plan", CapacityTypeComputeSavingsPlan},
+		{"ec2-instance-savings-plan", CapacityTypeEC2InstanceSavingsPlan},
+		{"reserved-instance", CapacityTypeReservedInstance},
+		{"unknown", ""},
+		{"", ""},
 	}
 
 	for _, tc := range tests {
-		t.Run(string(tc.input), func(t *testing.T) {
-			result := capacityTypeToLabelValue(tc.input)
+		t.Run(tc.input, func(t *testing.T) {
+			result := CapacityTypeFromLabelValue(tc.input)
 			if result != tc.expected {
 				t.Errorf("expected %q, got %q", tc.expected, result)
 			}
@@ -544,6 +708,64 @@ func TestValidateOverlay(t *testing.T) {
 			expectedErrors: 1,
 			errorContains:  []string{"must be a non-negative decimal"},
 		},
+		{
+			name: "valid price adjustment",
+			overlay: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overlay",
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Requirements: []karpenterv1alpha1.NodeSelectorRequirement{
+						{
+							Key:      LabelInstanceFamilyKarpenter,
+							Operator: corev1.NodeSelectorOpExists,
+						},
+					},
+					PriceAdjustment: stringPtr("-28%"),
+					Weight:          &validWeight,
+				},
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "invalid price adjustment format",
+			overlay: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overlay",
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Requirements: []karpenterv1alpha1.NodeSelectorRequirement{
+						{
+							Key:      LabelInstanceFamilyKarpenter,
+							Operator: corev1.NodeSelectorOpExists,
+						},
+					},
+					PriceAdjustment: stringPtr("-150%"), // Below -100%
+				},
+			},
+			expectedErrors: 1,
+			errorContains:  []string{"must be a signed decimal or percentage"},
+		},
+		{
+			name: "price and price adjustment both set",
+			overlay: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overlay",
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Requirements: []karpenterv1alpha1.NodeSelectorRequirement{
+						{
+							Key:      LabelInstanceFamilyKarpenter,
+							Operator: corev1.NodeSelectorOpExists,
+						},
+					},
+					Price:           &validPrice,
+					PriceAdjustment: stringPtr("-28%"),
+				},
+			},
+			expectedErrors: 1,
+			errorContains:  []string{"mutually exclusive"},
+		},
 		{
 			name: "weight out of range - too low",
 			overlay: &karpenterv1alpha1.NodeOverlay{
@@ -674,17 +896,25 @@ func stringPtr(s string) *string {
 func TestNewGeneratorWithOptions(t *testing.T) {
 	t.Run("disabled=false", func(t *testing.T) {
 		g := NewGeneratorWithOptions(false)
-		if g.Disabled {
+		if g.Disabled() {
 			t.Error("expected Disabled to be false")
 		}
 	})
 
 	t.Run("disabled=true", func(t *testing.T) {
 		g := NewGeneratorWithOptions(true)
-		if !g.Disabled {
+		if !g.Disabled() {
 			t.Error("expected Disabled to be true")
 		}
 	})
+
+	t.Run("SetDisabled", func(t *testing.T) {
+		g := NewGeneratorWithOptions(false)
+		g.SetDisabled(true)
+		if !g.Disabled() {
+			t.Error("expected Disabled to be true after SetDisabled(true)")
+		}
+	})
 }
 
 func TestGenerator_DisabledMode_AddsImpossibleRequirement(t *testing.T) {
@@ -735,12 +965,14 @@ func TestGenerator_DisabledMode_EC2InstanceSP(t *testing.T) {
 	g := NewGeneratorWithOptions(true) // Disabled mode enabled
 
 	decision := Decision{
-		Name:         "cost-aware-ec2-sp-m5-us-west-2",
-		CapacityType: CapacityTypeEC2InstanceSavingsPlan,
-		ShouldExist:  true,
-		Weight:       20,
-		Price:        "0.00",
-		Reason:       "capacity available",
+		Name:           "cost-aware-ec2-sp-m5-us-west-2",
+		InstanceFamily: "m5",
+		Region:         "us-west-2",
+		CapacityType:   CapacityTypeEC2InstanceSavingsPlan,
+		ShouldExist:    true,
+		Weight:         20,
+		Price:          "0.00",
+		Reason:         "capacity available",
 	}
 
 	overlay := g.Generate(decision)
@@ -773,12 +1005,15 @@ func TestGenerator_DisabledMode_ReservedInstance(t *testing.T) {
 	g := NewGeneratorWithOptions(true) // Disabled mode enabled
 
 	decision := Decision{
-		Name:         "cost-aware-ri-m5.xlarge-us-west-2",
-		CapacityType: CapacityTypeReservedInstance,
-		ShouldExist:  true,
-		Weight:       30,
-		Price:        "0.00",
-		Reason:       "3 reserved instances available",
+		Name:           "cost-aware-ri-m5.xlarge-us-west-2",
+		InstanceFamily: "m5",
+		InstanceType:   "m5.xlarge",
+		Region:         "us-west-2",
+		CapacityType:   CapacityTypeReservedInstance,
+		ShouldExist:    true,
+		Weight:         30,
+		Price:          "0.00",
+		Reason:         "3 reserved instances available",
 	}
 
 	overlay := g.Generate(decision)
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// overlayState records whether an overlay exists and since when.
+type overlayState struct {
+	exists bool
+	since  time.Time
+}
+
+// HysteresisTracker remembers the state of each cost-aware overlay across reconcile cycles
+// so decisions can be damped when utilization hovers around a threshold.
+//
+// The DecisionEngine is stateless; the tracker layers two rules on top of its decisions:
+//   - Decisions within the hysteresis band keep the overlay in its current state
+//   - An overlay cannot flip state until it has stayed in its current state for a minimum duration
+//
+// Decisions changed by either rule are marked Suppressed and get a reason starting with
+// "hysteresis:" so they are visible in logs and metrics. The tracker only learns about
+// state changes through Seed and Record.
+//
+// HysteresisTracker is safe for concurrent use.
+type HysteresisTracker struct {
+	mu     sync.Mutex
+	states map[string]overlayState
+}
+
+// NewHysteresisTracker creates an empty HysteresisTracker.
+func NewHysteresisTracker() *HysteresisTracker {
+	return &HysteresisTracker{
+		states: make(map[string]overlayState),
+	}
+}
+
+// Known reports whether the tracker has a recorded state for the named overlay.
+func (t *HysteresisTracker) Known(name string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, exists := t.states[name]
+	return exists
+}
+
+// Seed records the current state of an overlay the tracker has not seen yet, typically
+// read from the cluster after a restart. A zero since means the state has no known start,
+// so the minimum duration does not hold it. Seeding an already-known overlay is a no-op.
+func (t *HysteresisTracker) Seed(name string, exists bool, since time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, known := t.states[name]; known {
+		return
+	}
+	t.states[name] = overlayState{exists: exists, since: since}
+}
+
+// Forget removes the recorded state of an overlay, e.g. after it was garbage-collected.
+func (t *HysteresisTracker) Forget(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.states, name)
+}
+
+// Apply damps a decision against the overlay's recorded state.
+//
+// Unknown overlays are treated as absent with no dwell restriction. The returned decision has ShouldExist
+// set to the state the overlay should be in after this cycle; if that differs from what
+// the engine decided, Suppressed is set and Reason explains why.
+//
+// Apply doesn't change the recorded state: call Record once the overlay was actually
+// created or deleted, so a failed write doesn't start the dwell clock.
+func (t *HysteresisTracker) Apply(decision Decision, minStateDuration time.Duration, now time.Time) Decision {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := t.states[decision.Name]
+
+	if decision.WithinHysteresisBand {
+		if decision.ShouldExist != current.exists {
+			decision.ShouldExist = current.exists
+			decision.Suppressed = true
+			decision.Reason = fmt.Sprintf("hysteresis: %s, keeping overlay %s", decision.Reason, existenceWord(current.exists))
+		}
+	} else if decision.ShouldExist != current.exists && !current.since.IsZero() {
+		if held := now.Sub(current.since); held < minStateDuration {
+			decision.Reason = fmt.Sprintf("hysteresis: overlay %s for %s of minimum %s, ignoring: %s",
+				existenceWord(current.exists), held.Truncate(time.Second), minStateDuration, decision.Reason)
+			decision.ShouldExist = current.exists
+			decision.Suppressed = true
+		}
+	}
+
+	return decision
+}
+
+// Record records that the named overlay now exists or not. Only real transitions start the
+// dwell clock: recording the state the overlay is already in is a no-op, and an overlay seen
+// for the first time that stays absent keeps a zero since, so it can be created as soon as
+// it's needed.
+func (t *HysteresisTracker) Record(name string, exists bool, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current, known := t.states[name]
+	switch {
+	case known && current.exists == exists:
+	case !known && !exists:
+		t.states[name] = overlayState{}
+	default:
+		t.states[name] = overlayState{exists: exists, since: now}
+	}
+}
+
+// existenceWord describes an overlay state for decision reasons.
+func existenceWord(exists bool) string {
+	if exists {
+		return "present"
+	}
+	return "absent"
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHysteresisTracker_Apply(t *testing.T) {
+	const name = "cost-aware-compute-sp-global"
+	const minStateDuration = 15 * time.Minute
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	create := Decision{Name: name, ShouldExist: true, Reason: "utilization 80.0% below threshold 90.0%"}
+	remove := Decision{Name: name, ShouldExist: false, Reason: "utilization 96.0% at/above threshold 95.0%"}
+	band := Decision{Name: name, WithinHysteresisBand: true, Reason: "utilization 92.0% within hysteresis band [90.0%, 95.0%)"}
+
+	// Steps are applied in order to a single tracker; offset is relative to start.
+	// Each outcome is recorded as written to the cluster unless writeFails is set.
+	type step struct {
+		offset          time.Duration
+		decision        Decision
+		wantShouldExist bool
+		wantSuppressed  bool
+		writeFails      bool
+	}
+
+	scenarios := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "unknown overlay is created immediately",
+			steps: []step{
+				{0, create, true, false, false},
+			},
+		},
+		{
+			name: "delete suppressed until min state duration elapses",
+			steps: []step{
+				{0, create, true, false, false},
+				{5 * time.Minute, remove, true, true, false},
+				{15 * time.Minute, remove, false, false, false},
+			},
+		},
+		{
+			name: "re-create suppressed after delete",
+			steps: []step{
+				{0, create, true, false, false},
+				{20 * time.Minute, remove, false, false, false},
+				{25 * time.Minute, create, false, true, false},
+				{35 * time.Minute, create, true, false, false},
+			},
+		},
+		{
+			name: "band keeps overlay present",
+			steps: []step{
+				{0, create, true, false, false},
+				{20 * time.Minute, band, true, true, false},
+				{40 * time.Minute, band, true, true, false},
+			},
+		},
+		{
+			name: "band keeps absent overlay absent without suppressing",
+			steps: []step{
+				{0, band, false, false, false},
+				{5 * time.Minute, create, true, false, false},
+			},
+		},
+		{
+			name: "failed delete doesn't start the dwell clock",
+			steps: []step{
+				{0, create, true, false, false},
+				{20 * time.Minute, remove, false, false, true},
+				{21 * time.Minute, create, true, false, false},
+			},
+		},
+		{
+			name: "failed create can be retried straight away",
+			steps: []step{
+				{0, create, true, false, true},
+				{1 * time.Minute, create, true, false, false},
+				{2 * time.Minute, remove, true, true, false},
+			},
+		},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			tracker := NewHysteresisTracker()
+			for i, st := range sc.steps {
+				got := tracker.Apply(st.decision, minStateDuration, start.Add(st.offset))
+				if got.ShouldExist != st.wantShouldExist {
+					t.Errorf("step %d: ShouldExist = %v, want %v (reason: %s)", i, got.ShouldExist, st.wantShouldExist, got.Reason)
+				}
+				if got.Suppressed != st.wantSuppressed {
+					t.Errorf("step %d: Suppressed = %v, want %v", i, got.Suppressed, st.wantSuppressed)
+				}
+				if got.Suppressed && !strings.HasPrefix(got.Reason, "hysteresis:") {
+					t.Errorf("step %d: suppressed Reason = %q, want hysteresis prefix", i, got.Reason)
+				}
+				if !st.writeFails {
+					tracker.Record(got.Name, got.ShouldExist, start.Add(st.offset))
+				}
+			}
+		})
+	}
+}
+
+func TestHysteresisTracker_Seed(t *testing.T) {
+	const name = "cost-aware-ri-m5.xlarge-us-west-2"
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker := NewHysteresisTracker()
+	if tracker.Known(name) {
+		t.Fatalf("Known() = true before seeding")
+	}
+
+	// Overlay created 5 minutes ago, e.g. before a controller restart
+	tracker.Seed(name, true, now.Add(-5*time.Minute))
+	if !tracker.Known(name) {
+		t.Fatalf("Known() = false after seeding")
+	}
+
+	// Seeding again must not reset the recorded state
+	tracker.Seed(name, false, time.Time{})
+
+	got := tracker.Apply(Decision{Name: name, ShouldExist: false}, 15*time.Minute, now)
+	if !got.ShouldExist || !got.Suppressed {
+		t.Errorf("Apply() = (ShouldExist %v, Suppressed %v), want delete suppressed by seeded state",
+			got.ShouldExist, got.Suppressed)
+	}
+
+	tracker.Forget(name)
+	if tracker.Known(name) {
+		t.Errorf("Known() = true after Forget()")
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/prometheus"
+)
+
+// normalizationFactors maps instance sizes to AWS normalization factors for size-flexible
+// Reserved Instances. A regional RI covers any size in its family whose normalization
+// factor fits within the RI's units: one 2xlarge RI (16 units) covers two xlarge (8 units each).
+//
+// Sizes of the form "<N>xlarge" not listed here have a factor of 8*N.
+// See https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/apply_ri.html
+var normalizationFactors = map[string]float64{
+	"nano":   0.25,
+	"micro":  0.5,
+	"small":  1,
+	"medium": 2,
+	"large":  4,
+	"xlarge": 8,
+}
+
+// NormalizationFactor returns the AWS normalization factor of an instance type
+// (e.g., 8 for "m5.xlarge", 16 for "m5.2xlarge").
+//
+// Returns false for sizes without a fixed factor, such as "metal", whose factor
+// depends on the family. RIs of those types are matched by exact instance type.
+func NormalizationFactor(instanceType string) (float64, bool) {
+	_, size, found := strings.Cut(instanceType, ".")
+	if !found {
+		return 0, false
+	}
+
+	if factor, exists := normalizationFactors[size]; exists {
+		return factor, true
+	}
+
+	multiplier, found := strings.CutSuffix(size, "xlarge")
+	if !found {
+		return 0, false
+	}
+	n, err := strconv.Atoi(multiplier)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return float64(n) * normalizationFactors["xlarge"], true
+}
+
+// AggregatedReservedInstanceFamily represents size-flexible regional RIs of one instance
+// family in one region, measured in normalized units.
+type AggregatedReservedInstanceFamily struct {
+	// AccountID is the AWS account ID
+	AccountID string
+
+	// Region is the AWS region
+	Region string
+
+	// InstanceFamily is the EC2 instance family (e.g., "m5")
+	InstanceFamily string
+
+	// TotalUnits is the sum of count * normalization factor across all RIs in the family
+	TotalUnits float64
+
+	// RunningUnits is the normalized units of on-demand instances of the family currently running.
+	// Optional: 0 if no instances are running or usage is unknown.
+	RunningUnits float64
+
+	// PendingLaunchUnits is the normalized units of on-demand instances of the family Karpenter
+	// has launched that aren't running as Nodes yet. Optional: 0 if no launches are pending.
+	PendingLaunchUnits float64
+
+	// InstanceTypes lists the sizes in the family the overlay may cover, typically every size
+	// with a known on-demand price plus the RIs' own types. Sizes without a normalization
+	// factor are ignored, and sizes not listed are never covered.
+	InstanceTypes []string
+
+	// SpotSavingsPercent is how far spot prices sit below on-demand, averaged across the
+	// family (0-100). Optional: 0 if spot pricing is unavailable.
+	SpotSavingsPercent float64
+}
+
+// AggregateReservedInstancesByFamily aggregates size-flexible Reserved Instances by instance
+// family and region, in normalized units.
+//
+// Only regional RIs are size-flexible; zonal RIs and RIs of types without a normalization
+// factor are returned in exact so the caller can aggregate them by exact instance type with
+// AggregateReservedInstances. Lumina doesn't report the RI platform, so all regional RIs are
+// assumed to be Linux/Unix with default tenancy, the only kind AWS makes size-flexible.
+//
+// InstanceTypes starts out as the RIs' own types; callers add the other sizes of the family.
+//
+// Returns a map of "family:region" -> aggregated units (matching AggregateEC2InstanceSavingsPlans keys).
+func AggregateReservedInstancesByFamily(
+	ris []prometheus.ReservedInstance,
+) (families map[string]AggregatedReservedInstanceFamily, exact []prometheus.ReservedInstance) {
+	families = make(map[string]AggregatedReservedInstanceFamily)
+
+	for _, ri := range ris {
+		factor, known := NormalizationFactor(ri.InstanceType)
+		if ri.Zonal() || !known {
+			exact = append(exact, ri)
+			continue
+		}
+
+		family, _, _ := strings.Cut(ri.InstanceType, ".")
+		key := family + ":" + ri.Region
+
+		agg, exists := families[key]
+		if !exists {
+			agg = AggregatedReservedInstanceFamily{
+				AccountID:      ri.AccountID,
+				Region:         ri.Region,
+				InstanceFamily: family,
+			}
+		}
+
+		agg.TotalUnits += float64(ri.Count) * factor
+		if !slices.Contains(agg.InstanceTypes, ri.InstanceType) {
+			agg.InstanceTypes = append(agg.InstanceTypes, ri.InstanceType)
+		}
+		families[key] = agg
+	}
+
+	return families, exact
+}
+
+// AnalyzeReservedInstanceFamily determines if a family-level, size-flexible RI overlay should exist.
+//
+// The overlay covers every size of the family whose normalization factor fits within the
+// remaining (unoccupied) normalized units, so Karpenter prefers exactly the sizes the RIs
+// would discount. Since it covers many instance types, the price is a percentage adjustment.
+func (e *DecisionEngine) AnalyzeReservedInstanceFamily(agg AggregatedReservedInstanceFamily) Decision {
+	overlays := e.Config.Current().Overlays
+	prefix := overlays.Naming.ReservedInstancePrefix
+	if prefix == "" {
+		prefix = config.DefaultOverlayNamingReservedInstancePrefix
+	}
+	overlayName := fmt.Sprintf("%s-%s-%s", prefix, agg.InstanceFamily, agg.Region)
+
+	discount := discountPercent(overlays, CapacityTypeReservedInstance)
+
+	decision := Decision{
+		Name:            overlayName,
+		CapacityType:    CapacityTypeReservedInstance,
+		Weight:          overlays.Weights.ReservedInstance,
+		PriceAdjustment: formatDiscountAdjustment(discount),
+		DiscountPercent: discount,
+		InstanceFamily:  agg.InstanceFamily,
+		Region:          agg.Region,
+		TargetSelector: fmt.Sprintf(
+			"karpenter.k8s.aws/instance-family: In [%s], karpenter.sh/capacity-type: In [on-demand]",
+			agg.InstanceFamily,
+		),
+	}
+
+	inUse := agg.RunningUnits + agg.PendingLaunchUnits
+	remaining := agg.TotalUnits - inUse
+	if agg.TotalUnits > 0 {
+		decision.UtilizationPercent = inUse / agg.TotalUnits * 100
+	}
+
+	if agg.TotalUnits <= 0 {
+		decision.ShouldExist = false
+		decision.Reason = "no reserved instances available"
+		return decision
+	}
+	if remaining <= 0 {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("reserved instances fully utilized (%.2f of %.2f normalized units in use)",
+			inUse, agg.TotalUnits)
+		return decision
+	}
+	if agg.SpotSavingsPercent > discount {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("spot cheaper than covered price (spot %.1f%% below on-demand, reserved instance discount %.1f%%)",
+			agg.SpotSavingsPercent, discount)
+		return decision
+	}
+
+	// Always list the covered sizes: sizes Veneer doesn't know about (e.g., missing from
+	// pricing data) might not fit, so they must not be discounted
+	covered := InstanceTypesWithinUnits(agg.InstanceTypes, remaining)
+	if len(covered) == 0 {
+		decision.ShouldExist = false
+		decision.Reason = fmt.Sprintf("no remaining capacity for any %s size (%.2f normalized units)",
+			agg.InstanceFamily, remaining)
+		return decision
+	}
+	decision.InstanceTypes = covered
+	decision.TargetSelector = fmt.Sprintf(
+		"node.kubernetes.io/instance-type: In [%s], karpenter.sh/capacity-type: In [on-demand]",
+		strings.Join(covered, ", "),
+	)
+
+	decision.ShouldExist = true
+	decision.Reason = fmt.Sprintf("%.2f normalized units of reserved instances available", remaining)
+	return decision
+}
+
+// InstanceTypesWithinUnits returns the instance types whose normalization factor fits within
+// the remaining normalized units, sorted by name. Types without a factor never fit.
+func InstanceTypesWithinUnits(instanceTypes []string, remaining float64) []string {
+	var fitting []string
+	for _, instanceType := range instanceTypes {
+		if factor, known := NormalizationFactor(instanceType); known && factor <= remaining {
+			fitting = append(fitting, instanceType)
+		}
+	}
+	sort.Strings(fitting)
+	return fitting
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nextdoor/veneer/pkg/prometheus"
+)
+
+func TestNormalizationFactor(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		wantFactor   float64
+		wantKnown    bool
+	}{
+		{"t3.nano", 0.25, true},
+		{"t3.micro", 0.5, true},
+		{"t3.small", 1, true},
+		{"t3.medium", 2, true},
+		{"m5.large", 4, true},
+		{"m5.xlarge", 8, true},
+		{"m5.2xlarge", 16, true},
+		{"m5.24xlarge", 192, true},
+		{"m5.metal", 0, false},
+		{"m5.0xlarge", 0, false},
+		{"m5", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			factor, known := NormalizationFactor(tt.instanceType)
+			if factor != tt.wantFactor || known != tt.wantKnown {
+				t.Errorf("NormalizationFactor(%q) = (%f, %v), want (%f, %v)",
+					tt.instanceType, factor, known, tt.wantFactor, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestAggregateReservedInstancesByFamily(t *testing.T) {
+	ris := []prometheus.ReservedInstance{
+		{InstanceType: "m5.2xlarge", Region: "us-west-2", Count: 1},                                // 16 units
+		{InstanceType: "m5.large", Region: "us-west-2", Count: 3},                                  // 12 units
+		{InstanceType: "m5.xlarge", Region: "us-east-1", Count: 1},                                 // Other region
+		{InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2a", Count: 2}, // Zonal
+		{InstanceType: "m5.metal", Region: "us-west-2", Count: 1},                                  // No factor
+	}
+
+	families, exact := AggregateReservedInstancesByFamily(ris)
+
+	if len(families) != 2 {
+		t.Fatalf("expected 2 family aggregates, got %d: %v", len(families), families)
+	}
+	west := families["m5:us-west-2"]
+	if west.TotalUnits != 28 {
+		t.Errorf("m5:us-west-2 TotalUnits = %f, want 28", west.TotalUnits)
+	}
+	if !reflect.DeepEqual(west.InstanceTypes, []string{"m5.2xlarge", "m5.large"}) {
+		t.Errorf("m5:us-west-2 InstanceTypes = %v, want the RIs' own types", west.InstanceTypes)
+	}
+	if east := families["m5:us-east-1"]; east.TotalUnits != 8 {
+		t.Errorf("m5:us-east-1 TotalUnits = %f, want 8", east.TotalUnits)
+	}
+
+	if len(exact) != 2 || exact[0].InstanceType != "m5.xlarge" || exact[1].InstanceType != "m5.metal" {
+		t.Errorf("expected zonal and metal RIs to be matched exactly, got %v", exact)
+	}
+}
+
+func TestAnalyzeReservedInstanceFamily(t *testing.T) {
+	engine := NewDecisionEngine(testConfig())
+	sizes := []string{"m5.large", "m5.xlarge", "m5.2xlarge", "m5.4xlarge", "m5.metal"}
+
+	tests := []struct {
+		name       string
+		agg        AggregatedReservedInstanceFamily
+		wantExist  bool
+		wantTypes  []string
+		wantReason string
+	}{
+		{
+			name:       "all sizes within remaining units",
+			agg:        AggregatedReservedInstanceFamily{TotalUnits: 32},
+			wantExist:  true,
+			wantTypes:  []string{"m5.2xlarge", "m5.4xlarge", "m5.large", "m5.xlarge"},
+			wantReason: "32.00 normalized units of reserved instances available",
+		},
+		{
+			name:       "running instances shrink the covered sizes",
+			agg:        AggregatedReservedInstanceFamily{TotalUnits: 32, RunningUnits: 16, PendingLaunchUnits: 4},
+			wantExist:  true,
+			wantTypes:  []string{"m5.large", "m5.xlarge"},
+			wantReason: "12.00 normalized units of reserved instances available",
+		},
+		{
+			name:       "remaining units below the smallest size",
+			agg:        AggregatedReservedInstanceFamily{TotalUnits: 16, RunningUnits: 14},
+			wantExist:  false,
+			wantReason: "no remaining capacity for any m5 size (2.00 normalized units)",
+		},
+		{
+			name:       "fully utilized",
+			agg:        AggregatedReservedInstanceFamily{TotalUnits: 16, RunningUnits: 16, PendingLaunchUnits: 8},
+			wantExist:  false,
+			wantReason: "reserved instances fully utilized (24.00 of 16.00 normalized units in use)",
+		},
+		{
+			name:       "no reserved instances",
+			agg:        AggregatedReservedInstanceFamily{},
+			wantExist:  false,
+			wantReason: "no reserved instances available",
+		},
+		{
+			name:      "spot cheaper",
+			agg:       AggregatedReservedInstanceFamily{TotalUnits: 32, SpotSavingsPercent: 60},
+			wantExist: false,
+			wantReason: "spot cheaper than covered price (spot 60.0% below on-demand, " +
+				"reserved instance discount 37.0%)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.agg.InstanceFamily = "m5"
+			tt.agg.Region = "us-west-2"
+			tt.agg.InstanceTypes = sizes
+
+			decision := engine.AnalyzeReservedInstanceFamily(tt.agg)
+
+			if decision.Name != "cost-aware-ri-m5-us-west-2" {
+				t.Errorf("Name = %q, want %q", decision.Name, "cost-aware-ri-m5-us-west-2")
+			}
+			if decision.InstanceFamily != "m5" || decision.Region != "us-west-2" {
+				t.Errorf("InstanceFamily/Region = %q/%q, want m5/us-west-2", decision.InstanceFamily, decision.Region)
+			}
+			if decision.ShouldExist != tt.wantExist {
+				t.Errorf("ShouldExist = %v, want %v", decision.ShouldExist, tt.wantExist)
+			}
+			if !reflect.DeepEqual(decision.InstanceTypes, tt.wantTypes) {
+				t.Errorf("InstanceTypes = %v, want %v", decision.InstanceTypes, tt.wantTypes)
+			}
+			if decision.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", decision.Reason, tt.wantReason)
+			}
+			if decision.PriceAdjustment != "-37%" {
+				t.Errorf("PriceAdjustment = %q, want %q", decision.PriceAdjustment, "-37%")
+			}
+		})
+	}
+}
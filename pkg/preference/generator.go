@@ -17,11 +17,17 @@ limitations under the License.
 package preference
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 )
 
@@ -30,21 +36,35 @@ import (
 // The generator converts Preference structs into fully-formed NodeOverlay Kubernetes
 // resources with priceAdjustment to influence Karpenter's instance selection.
 type Generator struct {
-	// Disabled controls whether generated overlays are active or inactive.
+	// disabled controls whether generated overlays are active or inactive.
 	// When true, an impossible requirement is added to each overlay that prevents
 	// it from matching any nodes. This allows testing overlay creation without
 	// affecting Karpenter's provisioning decisions.
-	Disabled bool
+	//
+	// It is atomic so the setting can be changed on config reload while reconcilers generate overlays.
+	disabled atomic.Bool
 }
 
 // NewGenerator creates a new preference overlay generator with default settings (enabled).
 func NewGenerator() *Generator {
-	return &Generator{Disabled: false}
+	return &Generator{}
 }
 
 // NewGeneratorWithOptions creates a new preference overlay generator with the specified options.
 func NewGeneratorWithOptions(disabled bool) *Generator {
-	return &Generator{Disabled: disabled}
+	g := &Generator{}
+	g.disabled.Store(disabled)
+	return g
+}
+
+// Disabled reports whether generated overlays are disabled.
+func (g *Generator) Disabled() bool {
+	return g.disabled.Load()
+}
+
+// SetDisabled changes whether overlays generated from now on are disabled.
+func (g *Generator) SetDisabled(disabled bool) {
+	g.disabled.Store(disabled)
 }
 
 // Generate creates a NodeOverlay from a Preference.
@@ -53,7 +73,9 @@ func NewGeneratorWithOptions(disabled bool) *Generator {
 //   - Name following the pattern: pref-{nodepool}-{number}
 //   - Labels for identification and debugging
 //   - Requirements scoped to the source NodePool plus user-specified matchers
-//   - PriceAdjustment from the preference (e.g., "-20%")
+//   - PriceAdjustment from the preference (e.g., "-20%" or "-0.05"), or Price for
+//     fixed price preferences (e.g., "0.10")
+//   - Capacity for preferences that add extended resources (e.g., "example.com/fpga": 1)
 //   - Weight equal to the preference number
 func (g *Generator) Generate(pref Preference) *karpenterv1alpha1.NodeOverlay {
 	overlay := &karpenterv1alpha1.NodeOverlay{
@@ -66,11 +88,11 @@ func (g *Generator) Generate(pref Preference) *karpenterv1alpha1.NodeOverlay {
 			Labels: g.generateLabels(pref),
 		},
 		Spec: karpenterv1alpha1.NodeOverlaySpec{
-			Requirements:    g.generateRequirements(pref),
-			PriceAdjustment: g.formatPriceAdjustment(pref.Adjustment),
-			Weight:          int32Ptr(int32(pref.Number)),
+			Requirements: g.generateRequirements(pref, []string{pref.NodePoolName}),
+			Weight:       int32Ptr(int32(pref.Number)),
 		},
 	}
+	g.setOverrides(&overlay.Spec, pref)
 
 	return overlay
 }
@@ -86,6 +108,105 @@ func (g *Generator) GenerateAll(prefs []Preference) []*karpenterv1alpha1.NodeOve
 	return overlays
 }
 
+// GenerateForResource creates a NodeOverlay for a preference declared by the VeneerPreference
+// named resourceName, scoped to pref.NodePoolName.
+//
+// The overlay is generated like an annotation preference overlay, except that:
+//   - Name follows the pattern: vpref-{resource}-{nodepool}-{hash} (see OverlayNameForResource)
+//   - Weight is the explicit weight from the VeneerPreference rather than pref.Number
+//   - Labels identify the source VeneerPreference and target NodePool instead of the
+//     source NodePool and preference number
+func (g *Generator) GenerateForResource(resourceName string, pref Preference, weight int32) *karpenterv1alpha1.NodeOverlay {
+	overlay := g.Generate(pref)
+	overlay.Name = OverlayNameForResource(resourceName, pref.NodePoolName)
+	overlay.Spec.Weight = int32Ptr(weight)
+
+	delete(overlay.Labels, LabelSourceNodePool)
+	delete(overlay.Labels, LabelPreferenceNumber)
+	overlay.Labels[LabelSourcePreference] = resourceName
+	overlay.Labels[LabelTargetNodePool] = pref.NodePoolName
+
+	return overlay
+}
+
+// OverlayNameForResource returns the overlay name for a VeneerPreference and NodePool:
+// vpref-{resource}-{nodepool}-{hash}. Both names may contain dashes, so the short hash of the
+// pair keeps e.g. VeneerPreference a-b with NodePool c and VeneerPreference a with NodePool b-c
+// from sharing an overlay.
+func OverlayNameForResource(resourceName, nodePoolName string) string {
+	// Neither name can contain a slash, so the hashed string identifies the pair
+	sum := sha256.Sum256([]byte(resourceName + "/" + nodePoolName))
+	return fmt.Sprintf("vpref-%s-%s-%s", resourceName, nodePoolName, hex.EncodeToString(sum[:4]))
+}
+
+// ValidateOverlay checks that a generated overlay can be written: its name must be a valid
+// object name and its label values valid label values. Names and labels derived from long
+// resource and NodePool names can exceed the 253-character name or 63-character label limits.
+func ValidateOverlay(overlay *karpenterv1alpha1.NodeOverlay) error {
+	if errs := validation.IsDNS1123Subdomain(overlay.Name); len(errs) > 0 {
+		return fmt.Errorf("overlay name %q is invalid: %s", overlay.Name, strings.Join(errs, "; "))
+	}
+
+	keys := make([]string, 0, len(overlay.Labels))
+	for key := range overlay.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if errs := validation.IsValidLabelValue(overlay.Labels[key]); len(errs) > 0 {
+			return fmt.Errorf("overlay %s label %s value %q is invalid: %s",
+				overlay.Name, key, overlay.Labels[key], strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// GenerateForNodePools creates a single NodeOverlay for the cluster-wide preference named
+// clusterPreferenceName, scoped to every NodePool in nodePoolNames.
+//
+// The overlay is generated like an annotation preference overlay, except that:
+//   - Name follows the pattern: cluster-pref-{name}
+//   - The karpenter.sh/nodepool requirement lists every target NodePool
+//   - Weight is the explicit weight of the cluster preference rather than pref.Number
+//   - Labels identify the source cluster preference instead of a source NodePool
+//
+// nodePoolNames must not be empty, since an In requirement needs at least one value.
+func (g *Generator) GenerateForNodePools(
+	clusterPreferenceName string, pref Preference, nodePoolNames []string, weight int32,
+) *karpenterv1alpha1.NodeOverlay {
+	labels := map[string]string{
+		LabelManagedBy:               LabelManagedByValue,
+		LabelPreferenceType:          LabelPreferenceTypeValue,
+		LabelSourceClusterPreference: clusterPreferenceName,
+	}
+	if g.Disabled() {
+		labels[LabelDisabledKey] = LabelDisabledValue
+	}
+
+	overlay := &karpenterv1alpha1.NodeOverlay{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "karpenter.sh/v1alpha1",
+			Kind:       "NodeOverlay",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   OverlayNameForClusterPreference(clusterPreferenceName),
+			Labels: labels,
+		},
+		Spec: karpenterv1alpha1.NodeOverlaySpec{
+			Requirements: g.generateRequirements(pref, nodePoolNames),
+			Weight:       int32Ptr(weight),
+		},
+	}
+	g.setOverrides(&overlay.Spec, pref)
+
+	return overlay
+}
+
+// OverlayNameForClusterPreference returns the overlay name for a cluster-wide preference.
+func OverlayNameForClusterPreference(clusterPreferenceName string) string {
+	return "cluster-pref-" + clusterPreferenceName
+}
+
 // generateName creates the overlay name from a preference.
 // Format: pref-{nodepool}-{number}
 func (g *Generator) generateName(pref Preference) string {
@@ -114,7 +235,7 @@ func (g *Generator) generateLabels(pref Preference) map[string]string {
 		LabelPreferenceNumber: strconv.Itoa(pref.Number),
 	}
 
-	if g.Disabled {
+	if g.Disabled() {
 		labels[LabelDisabledKey] = LabelDisabledValue
 	}
 
@@ -124,20 +245,22 @@ func (g *Generator) generateLabels(pref Preference) map[string]string {
 // generateRequirements creates the NodeSelectorRequirements for a preference overlay.
 //
 // Requirements always include:
-//   - karpenter.sh/nodepool In [nodepool name] - scope to source NodePool
+//   - karpenter.sh/nodepool In [nodepool names] - scope to the target NodePools
 //   - user-specified matchers converted to requirements
 //   - veneer.io/disabled: "true" (if disabled mode is enabled)
-func (g *Generator) generateRequirements(pref Preference) []karpenterv1alpha1.NodeSelectorRequirement {
+func (g *Generator) generateRequirements(
+	pref Preference, nodePoolNames []string,
+) []karpenterv1alpha1.NodeSelectorRequirement {
 	// Pre-allocate: 1 for nodepool + matchers + potentially 1 for disabled
 	capacity := 1 + len(pref.Matchers)
-	if g.Disabled {
+	if g.Disabled() {
 		capacity++
 	}
 	requirements := make([]karpenterv1alpha1.NodeSelectorRequirement, 0, capacity)
 
 	// If disabled mode is enabled, add an impossible requirement first.
 	// This prevents the overlay from ever matching any instances.
-	if g.Disabled {
+	if g.Disabled() {
 		requirements = append(requirements, karpenterv1alpha1.NodeSelectorRequirement{
 			Key:      LabelDisabledKey,
 			Operator: corev1.NodeSelectorOpIn,
@@ -145,11 +268,11 @@ func (g *Generator) generateRequirements(pref Preference) []karpenterv1alpha1.No
 		})
 	}
 
-	// Always scope to the source NodePool
+	// Always scope to the target NodePools
 	requirements = append(requirements, karpenterv1alpha1.NodeSelectorRequirement{
 		Key:      LabelNodePool,
 		Operator: corev1.NodeSelectorOpIn,
-		Values:   []string{pref.NodePoolName},
+		Values:   nodePoolNames,
 	})
 
 	// Convert user matchers to requirements
@@ -169,6 +292,10 @@ func (g *Generator) generateRequirements(pref Preference) []karpenterv1alpha1.No
 			req.Operator = corev1.NodeSelectorOpGt
 		case OperatorLt:
 			req.Operator = corev1.NodeSelectorOpLt
+		case OperatorExists:
+			req.Operator = corev1.NodeSelectorOpExists
+		case OperatorDoesNotExist:
+			req.Operator = corev1.NodeSelectorOpDoesNotExist
 		default:
 			req.Operator = corev1.NodeSelectorOpIn
 		}
@@ -179,6 +306,40 @@ func (g *Generator) generateRequirements(pref Preference) []karpenterv1alpha1.No
 	return requirements
 }
 
+// setOverrides sets either the price or the priceAdjustment of an overlay spec from a preference,
+// along with any extended resource capacity. Karpenter rejects overlays that set both prices.
+func (g *Generator) setOverrides(spec *karpenterv1alpha1.NodeOverlaySpec, pref Preference) {
+	if len(pref.Capacity) > 0 {
+		spec.Capacity = pref.Capacity.DeepCopy()
+	}
+
+	switch pref.AdjustmentType {
+	case AdjustmentPrice:
+		price := strconv.FormatFloat(pref.Price, 'f', -1, 64)
+		spec.Price = &price
+	case AdjustmentAbsolute:
+		spec.PriceAdjustment = g.formatAbsolutePriceAdjustment(pref.Adjustment)
+	case AdjustmentNone:
+		// Capacity-only preference; the price is left unchanged
+	default:
+		spec.PriceAdjustment = g.formatPriceAdjustment(pref.Adjustment)
+	}
+}
+
+// formatAbsolutePriceAdjustment converts a dollar adjustment to the signed string format
+// expected by Karpenter's priceAdjustment field.
+//
+// Examples:
+//   - -0.05 -> "-0.05"
+//   - 0.5 -> "+0.5"
+func (g *Generator) formatAbsolutePriceAdjustment(adj float64) *string {
+	s := strconv.FormatFloat(adj, 'f', -1, 64)
+	if adj >= 0 {
+		s = "+" + s
+	}
+	return &s
+}
+
 // formatPriceAdjustment converts a numeric adjustment to the string format expected
 // by Karpenter's priceAdjustment field.
 //
@@ -221,6 +382,15 @@ func GetSourceNodePool(overlay *karpenterv1alpha1.NodeOverlay) string {
 	return overlay.Labels[LabelSourceNodePool]
 }
 
+// GetSourcePreference returns the VeneerPreference name that a preference overlay was generated from.
+// Returns empty string if the overlay was not generated from a VeneerPreference.
+func GetSourcePreference(overlay *karpenterv1alpha1.NodeOverlay) string {
+	if overlay == nil || overlay.Labels == nil {
+		return ""
+	}
+	return overlay.Labels[LabelSourcePreference]
+}
+
 // GetPreferenceNumber returns the preference number from a preference overlay.
 // Returns 0 if the overlay is not a preference overlay or the number can't be parsed.
 func GetPreferenceNumber(overlay *karpenterv1alpha1.NodeOverlay) int {
@@ -17,9 +17,11 @@ limitations under the License.
 package preference
 
 import (
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 )
@@ -312,6 +314,113 @@ func TestGenerator_Generate(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:     "absolute adjustment",
+			disabled: false,
+			pref: Preference{
+				Number:         1,
+				NodePoolName:   "absolute-adj",
+				Adjustment:     -0.05,
+				AdjustmentType: AdjustmentAbsolute,
+				Matchers: []LabelMatcher{
+					{Key: LabelArch, Operator: OperatorIn, Values: []string{"arm64"}},
+				},
+			},
+			check: func(t *testing.T, o *karpenterv1alpha1.NodeOverlay) {
+				if o.Spec.PriceAdjustment == nil || *o.Spec.PriceAdjustment != "-0.05" {
+					t.Errorf("expected priceAdjustment -0.05, got %v", o.Spec.PriceAdjustment)
+				}
+				if o.Spec.Price != nil {
+					t.Errorf("expected no price, got %s", *o.Spec.Price)
+				}
+			},
+		},
+		{
+			name:     "positive absolute adjustment",
+			disabled: false,
+			pref: Preference{
+				Number:         1,
+				NodePoolName:   "absolute-adj",
+				Adjustment:     0.5,
+				AdjustmentType: AdjustmentAbsolute,
+				Matchers: []LabelMatcher{
+					{Key: LabelArch, Operator: OperatorIn, Values: []string{"arm64"}},
+				},
+			},
+			check: func(t *testing.T, o *karpenterv1alpha1.NodeOverlay) {
+				if o.Spec.PriceAdjustment == nil || *o.Spec.PriceAdjustment != "+0.5" {
+					t.Errorf("expected priceAdjustment +0.5, got %v", o.Spec.PriceAdjustment)
+				}
+			},
+		},
+		{
+			name:     "fixed price",
+			disabled: false,
+			pref: Preference{
+				Number:         1,
+				NodePoolName:   "fixed-price",
+				Price:          0.10,
+				AdjustmentType: AdjustmentPrice,
+				Matchers: []LabelMatcher{
+					{Key: LabelInstanceType, Operator: OperatorIn, Values: []string{"m7i.xlarge"}},
+				},
+			},
+			check: func(t *testing.T, o *karpenterv1alpha1.NodeOverlay) {
+				if o.Spec.Price == nil || *o.Spec.Price != "0.1" {
+					t.Errorf("expected price 0.1, got %v", o.Spec.Price)
+				}
+				// Karpenter rejects overlays with both price and priceAdjustment
+				if o.Spec.PriceAdjustment != nil {
+					t.Errorf("expected no priceAdjustment, got %s", *o.Spec.PriceAdjustment)
+				}
+			},
+		},
+		{
+			name:     "exists and does not exist matchers",
+			disabled: false,
+			pref: Preference{
+				Number:       1,
+				NodePoolName: "gpu",
+				Adjustment:   -10,
+				Matchers: []LabelMatcher{
+					{Key: LabelInstanceGPUName, Operator: OperatorExists},
+					{Key: LabelInstanceAcceleratorName, Operator: OperatorDoesNotExist},
+				},
+			},
+			check: func(t *testing.T, o *karpenterv1alpha1.NodeOverlay) {
+				if len(o.Spec.Requirements) != 3 {
+					t.Fatalf("expected 3 requirements, got %d", len(o.Spec.Requirements))
+				}
+				if r := o.Spec.Requirements[1]; r.Operator != corev1.NodeSelectorOpExists || len(r.Values) != 0 {
+					t.Errorf("expected Exists requirement with no values, got %+v", r)
+				}
+				if r := o.Spec.Requirements[2]; r.Operator != corev1.NodeSelectorOpDoesNotExist || len(r.Values) != 0 {
+					t.Errorf("expected DoesNotExist requirement with no values, got %+v", r)
+				}
+			},
+		},
+		{
+			name:     "capacity only",
+			disabled: false,
+			pref: Preference{
+				Number:         1,
+				NodePoolName:   "fpga",
+				AdjustmentType: AdjustmentNone,
+				Capacity:       corev1.ResourceList{"example.com/fpga": resource.MustParse("1")},
+				Matchers: []LabelMatcher{
+					{Key: LabelInstanceType, Operator: OperatorIn, Values: []string{"f1.2xlarge"}},
+				},
+			},
+			check: func(t *testing.T, o *karpenterv1alpha1.NodeOverlay) {
+				if q, ok := o.Spec.Capacity["example.com/fpga"]; !ok || q.Value() != 1 {
+					t.Errorf("expected example.com/fpga capacity 1, got %v", o.Spec.Capacity)
+				}
+				if o.Spec.Price != nil || o.Spec.PriceAdjustment != nil {
+					t.Errorf("expected price unchanged, got price=%v priceAdjustment=%v",
+						o.Spec.Price, o.Spec.PriceAdjustment)
+				}
+			},
+		},
 		{
 			name:     "high preference number",
 			disabled: false,
@@ -606,3 +715,150 @@ func TestGetPreferenceNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_GenerateForResource(t *testing.T) {
+	g := NewGenerator()
+
+	pref := Preference{
+		NodePoolName: testNodePoolMyWorkload,
+		Adjustment:   -20,
+		Matchers: []LabelMatcher{
+			{Key: LabelInstanceFamily, Operator: OperatorIn, Values: []string{testInstanceFamilyC7g}},
+		},
+	}
+
+	overlay := g.GenerateForResource("prefer-graviton", pref, 7)
+
+	if overlay.Name != "vpref-prefer-graviton-my-workload-8ae8cc2f" {
+		t.Errorf("expected name vpref-prefer-graviton-my-workload-8ae8cc2f, got %s", overlay.Name)
+	}
+	if overlay.Spec.Weight == nil || *overlay.Spec.Weight != 7 {
+		t.Errorf("expected weight 7, got %v", overlay.Spec.Weight)
+	}
+	if overlay.Spec.PriceAdjustment == nil || *overlay.Spec.PriceAdjustment != "-20%" {
+		t.Errorf("expected price adjustment -20%%, got %v", overlay.Spec.PriceAdjustment)
+	}
+
+	// Scoped to the target NodePool like annotation preference overlays
+	if len(overlay.Spec.Requirements) != 2 || overlay.Spec.Requirements[0].Key != LabelNodePool ||
+		overlay.Spec.Requirements[0].Values[0] != testNodePoolMyWorkload {
+		t.Errorf("expected requirements scoped to NodePool %s, got %v", testNodePoolMyWorkload, overlay.Spec.Requirements)
+	}
+
+	if !IsPreferenceOverlay(overlay) {
+		t.Error("expected overlay to be a preference overlay")
+	}
+	if got := GetSourcePreference(overlay); got != "prefer-graviton" {
+		t.Errorf("expected source preference prefer-graviton, got %q", got)
+	}
+	if got := overlay.Labels[LabelTargetNodePool]; got != testNodePoolMyWorkload {
+		t.Errorf("expected target NodePool %s, got %q", testNodePoolMyWorkload, got)
+	}
+
+	// Not picked up by the NodePool reconciler, which lists by source NodePool
+	if _, ok := overlay.Labels[LabelSourceNodePool]; ok {
+		t.Error("expected no source-nodepool label on a VeneerPreference overlay")
+	}
+	if _, ok := overlay.Labels[LabelPreferenceNumber]; ok {
+		t.Error("expected no preference-number label on a VeneerPreference overlay")
+	}
+}
+
+func TestValidateOverlay(t *testing.T) {
+	g := NewGenerator()
+	pref := Preference{
+		NodePoolName: testNodePoolMyWorkload,
+		Adjustment:   -20,
+		Matchers: []LabelMatcher{
+			{Key: LabelInstanceFamily, Operator: OperatorIn, Values: []string{testInstanceFamilyC7g}},
+		},
+	}
+	longNodePool := pref
+	longNodePool.NodePoolName = strings.Repeat("a", 250)
+
+	tests := []struct {
+		name    string
+		overlay *karpenterv1alpha1.NodeOverlay
+		wantErr string
+	}{
+		{
+			name:    "valid overlay",
+			overlay: g.GenerateForResource("prefer-graviton", pref, 7),
+		},
+		{
+			name:    "name longer than 253 characters",
+			overlay: g.GenerateForResource("prefer-graviton", longNodePool, 7),
+			wantErr: "overlay name",
+		},
+		{
+			name:    "label value longer than 63 characters",
+			overlay: g.GenerateForResource(strings.Repeat("p", 64), pref, 7),
+			wantErr: "label " + LabelSourcePreference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOverlay(tt.overlay)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateOverlay() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateOverlay() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateForNodePools(t *testing.T) {
+	g := NewGenerator()
+
+	pref := Preference{
+		Adjustment: -20,
+		Matchers: []LabelMatcher{
+			{Key: LabelInstanceFamily, Operator: OperatorIn, Values: []string{testInstanceFamilyC7g}},
+		},
+	}
+
+	overlay := g.GenerateForNodePools("prefer-graviton", pref, []string{"platform-a", "platform-b"}, 1)
+
+	if overlay.Name != "cluster-pref-prefer-graviton" {
+		t.Errorf("expected name cluster-pref-prefer-graviton, got %s", overlay.Name)
+	}
+	if overlay.Spec.Weight == nil || *overlay.Spec.Weight != 1 {
+		t.Errorf("expected weight 1, got %v", overlay.Spec.Weight)
+	}
+
+	// A single requirement lists every matching NodePool
+	if len(overlay.Spec.Requirements) != 2 || overlay.Spec.Requirements[0].Key != LabelNodePool {
+		t.Fatalf("expected NodePool requirement first, got %v", overlay.Spec.Requirements)
+	}
+	if got := overlay.Spec.Requirements[0].Values; len(got) != 2 || got[0] != "platform-a" || got[1] != "platform-b" {
+		t.Errorf("expected NodePool values [platform-a platform-b], got %v", got)
+	}
+
+	if !IsPreferenceOverlay(overlay) {
+		t.Error("expected overlay to be a preference overlay")
+	}
+	if got := overlay.Labels[LabelSourceClusterPreference]; got != "prefer-graviton" {
+		t.Errorf("expected source cluster preference prefer-graviton, got %q", got)
+	}
+	if _, ok := overlay.Labels[LabelSourceNodePool]; ok {
+		t.Error("expected no source-nodepool label on a cluster preference overlay")
+	}
+}
+
+func TestOverlayNameForResource_Unambiguous(t *testing.T) {
+	// Both pairs would be named vpref-a-b-c without the hash
+	first := OverlayNameForResource("a-b", "c")
+	second := OverlayNameForResource("a", "b-c")
+	if first == second {
+		t.Errorf("expected different overlay names, both are %s", first)
+	}
+	if OverlayNameForResource("a-b", "c") != first {
+		t.Error("expected the overlay name to be stable")
+	}
+}
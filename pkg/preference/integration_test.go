@@ -258,7 +258,7 @@ func TestIntegration_ParseGenerateValidate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Step 1: Parse preferences from annotations
-			prefs, parseErrors := ParseNodePoolPreferences(tt.annotations, tt.nodePoolName)
+			prefs, parseErrors := ParseNodePoolPreferences(tt.annotations, tt.nodePoolName, nil)
 
 			// Log parse errors for debugging
 			for _, err := range parseErrors {
@@ -308,7 +308,7 @@ func TestIntegration_HelperFunctions(t *testing.T) {
 	annotations := map[string]string{
 		"veneer.io/preference.5": "karpenter.k8s.aws/instance-family=c7a adjust=-20%",
 	}
-	prefs, _ := ParseNodePoolPreferences(annotations, "helper-test")
+	prefs, _ := ParseNodePoolPreferences(annotations, "helper-test", nil)
 
 	// Generate overlay
 	generator := NewGenerator()
@@ -22,6 +22,10 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // ParseError represents an error encountered while parsing a preference annotation.
@@ -40,11 +44,24 @@ func (e ParseError) Error() string {
 // adjustmentRegex matches adjustment expressions like "adjust=+20%", "adjust=-15%", "adjust=5%"
 var adjustmentRegex = regexp.MustCompile(`^adjust=([+-]?\d+(?:\.\d+)?)%$`)
 
+// absoluteAdjustmentRegex matches absolute dollar adjustments like "adjust=-0.05", "adjust=+1".
+// The sign is required, matching Karpenter's priceAdjustment format.
+var absoluteAdjustmentRegex = regexp.MustCompile(`^adjust=([+-]\d+(?:\.\d+)?)$`)
+
+// capacityRegex matches capacity expressions like "capacity=example.com/fpga:1,hugepages-2Mi:1Gi"
+var capacityRegex = regexp.MustCompile(`^capacity=(.+)$`)
+
+// priceRegex matches fixed price expressions like "price=0.10", "price=2"
+var priceRegex = regexp.MustCompile(`^price=(\d+(?:\.\d+)?)$`)
+
 // ParseNodePoolPreferences extracts and parses all preference annotations from a NodePool.
 //
 // Annotation format:
 //
 //	veneer.io/preference.N: "key=val1,val2 [key2=val3] adjust=[+-]N%"
+//	veneer.io/preference.N: "key=val1,val2 [key2=val3] adjust=[+-]N"
+//	veneer.io/preference.N: "key=val1,val2 [key2=val3] price=N"
+//	veneer.io/preference.N: "key=val1,val2 [key2=val3] [adjust=... | price=N] capacity=name:qty[,name:qty]"
 //
 // Where:
 //   - N is a positive integer (1-9999) determining the overlay weight/priority
@@ -53,11 +70,18 @@ var adjustmentRegex = regexp.MustCompile(`^adjust=([+-]?\d+(?:\.\d+)?)%$`)
 //   - key!=val uses NotIn operator
 //   - key>N uses Gt operator (for numeric labels)
 //   - key<N uses Lt operator (for numeric labels)
-//   - adjust specifies the price adjustment percentage
+//   - key alone uses Exists operator, and !key uses DoesNotExist
+//   - adjust specifies the price adjustment, as a percentage (N%) or in dollars per hour ([+-]N)
+//   - price replaces the price with a fixed amount in dollars per hour
+//   - capacity adds extended resources to matching instance types
+//
+// Matcher keys must be in supportedLabels; nil uses SupportedLabels.
 //
 // Returns the parsed preferences sorted by number (ascending), and any parse errors.
 // Parse errors are non-fatal; valid preferences are still returned.
-func ParseNodePoolPreferences(annotations map[string]string, nodePoolName string) ([]Preference, []error) {
+func ParseNodePoolPreferences(
+	annotations map[string]string, nodePoolName string, supportedLabels LabelSet,
+) ([]Preference, []error) {
 	preferences := make([]Preference, 0, len(annotations))
 	var errors []error
 
@@ -85,7 +109,7 @@ func ParseNodePoolPreferences(annotations map[string]string, nodePoolName string
 		}
 
 		// Parse the preference value
-		pref, err := parsePreferenceValue(value)
+		pref, err := parsePreferenceValue(value, supportedLabels)
 		if err != nil {
 			errors = append(errors, ParseError{
 				AnnotationKey: key,
@@ -107,11 +131,45 @@ func ParseNodePoolPreferences(annotations map[string]string, nodePoolName string
 	return preferences, errors
 }
 
+// CheckAdjustmentRange returns the preferences whose price change is within the allowed
+// limits (see Preference.CheckAdjustment), and a ParseError for each preference outside them.
+//
+// The limits are deployment policy rather than syntax, so they are checked separately from
+// ParseNodePoolPreferences; out-of-range preferences are treated like unparseable ones.
+func CheckAdjustmentRange(
+	prefs []Preference, minAdjustment, maxAdjustment, maxAbsoluteAdjustment float64,
+) ([]Preference, []error) {
+	valid := make([]Preference, 0, len(prefs))
+	var errors []error
+
+	for _, pref := range prefs {
+		if err := pref.CheckAdjustment(minAdjustment, maxAdjustment, maxAbsoluteAdjustment); err != nil {
+			errors = append(errors, ParseError{
+				AnnotationKey: AnnotationPrefix + strconv.Itoa(pref.Number),
+				Message:       err.Error(),
+			})
+			continue
+		}
+		valid = append(valid, pref)
+	}
+
+	return valid, errors
+}
+
+// ParsePreference parses a preference in annotation value syntax, for preference sources
+// other than NodePool annotations (e.g., cluster-wide preferences in the config file).
+// Matcher keys must be in supportedLabels; nil uses SupportedLabels.
+// The returned preference has no Number or NodePoolName.
+func ParsePreference(value string, supportedLabels LabelSet) (*Preference, error) {
+	return parsePreferenceValue(value, supportedLabels)
+}
+
 // parsePreferenceValue parses a single preference annotation value.
 //
-// Format: "key=val1,val2 [key2=val3] adjust=[+-]N%"
-// The "adjust" part is required. All other parts define matchers.
-func parsePreferenceValue(value string) (*Preference, error) {
+// Format: "key=val1,val2 [key2=val3] adjust=[+-]N%" (or "adjust=[+-]N", or "price=N")
+// plus optional "capacity=name:qty[,name:qty]" parts. At most one of "adjust" or "price"
+// is allowed, and a preference without either must set capacity. All other parts define matchers.
+func parsePreferenceValue(value string, supportedLabels LabelSet) (*Preference, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return nil, fmt.Errorf("empty preference value")
@@ -126,29 +184,60 @@ func parsePreferenceValue(value string) (*Preference, error) {
 		return nil, fmt.Errorf("empty preference value")
 	}
 
-	var adjustFound bool
+	var adjustFound, priceFound bool
 	for _, part := range parts {
 		// Check if this is the adjustment expression
 		if strings.HasPrefix(part, "adjust=") {
-			adj, err := parseAdjustment(part)
+			adj, adjType, err := parseAdjustment(part)
 			if err != nil {
 				return nil, err
 			}
 			pref.Adjustment = adj
+			pref.AdjustmentType = adjType
 			adjustFound = true
 			continue
 		}
 
+		// Check if this is the fixed price expression
+		if strings.HasPrefix(part, "price=") {
+			price, err := parsePrice(part)
+			if err != nil {
+				return nil, err
+			}
+			pref.Price = price
+			priceFound = true
+			continue
+		}
+
+		// Check if this is a capacity expression
+		if strings.HasPrefix(part, "capacity=") {
+			if err := parseCapacity(part, &pref.Capacity); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		// Otherwise, it's a label matcher
-		matcher, err := parseMatcher(part)
+		matcher, err := parseMatcher(part, supportedLabels)
 		if err != nil {
 			return nil, err
 		}
 		pref.Matchers = append(pref.Matchers, *matcher)
 	}
 
-	if !adjustFound {
-		return nil, fmt.Errorf("missing required 'adjust=[+-]N%%' expression")
+	// Karpenter rejects overlays that set both price and priceAdjustment
+	if adjustFound && priceFound {
+		return nil, fmt.Errorf("'adjust=' and 'price=' cannot be combined in one preference")
+	}
+	if priceFound {
+		pref.AdjustmentType = AdjustmentPrice
+		pref.Adjustment = 0
+	}
+	if !adjustFound && !priceFound {
+		if len(pref.Capacity) == 0 {
+			return nil, fmt.Errorf("missing required 'adjust=[+-]N%%', 'price=N', or 'capacity=name:qty' expression")
+		}
+		pref.AdjustmentType = AdjustmentNone
 	}
 
 	if len(pref.Matchers) == 0 {
@@ -165,7 +254,9 @@ func parsePreferenceValue(value string) (*Preference, error) {
 //   - key!=val1,val2 -> NotIn operator
 //   - key>N          -> Gt operator (numeric)
 //   - key<N          -> Lt operator (numeric)
-func parseMatcher(expr string) (*LabelMatcher, error) {
+//   - key            -> Exists operator
+//   - !key           -> DoesNotExist operator
+func parseMatcher(expr string, supportedLabels LabelSet) (*LabelMatcher, error) {
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		return nil, fmt.Errorf("empty matcher expression")
@@ -225,19 +316,27 @@ func parseMatcher(expr string) (*LabelMatcher, error) {
 		matcher.Operator = OperatorIn
 		matcher.Values = parseValues(parts[1])
 
+	case strings.HasPrefix(expr, "!"):
+		matcher.Key = strings.TrimSpace(expr[1:])
+		matcher.Operator = OperatorDoesNotExist
+
 	default:
-		return nil, fmt.Errorf("invalid matcher %q: missing operator (=, !=, >, <)", expr)
+		matcher.Key = expr
+		matcher.Operator = OperatorExists
 	}
 
 	// Validate the key is a supported label
 	if matcher.Key == "" {
 		return nil, fmt.Errorf("invalid matcher %q: empty label key", expr)
 	}
-	if !SupportedLabels[matcher.Key] {
+	if !labelSetOrDefault(supportedLabels)[matcher.Key] {
 		return nil, fmt.Errorf("unsupported label key %q: must be one of the supported Karpenter labels", matcher.Key)
 	}
 
-	// Validate we have at least one value
+	// Validate we have at least one value; Exists and DoesNotExist take none
+	if matcher.Operator == OperatorExists || matcher.Operator == OperatorDoesNotExist {
+		return matcher, nil
+	}
 	if len(matcher.Values) == 0 {
 		return nil, fmt.Errorf("invalid matcher %q: at least one value is required", expr)
 	}
@@ -245,6 +344,50 @@ func parseMatcher(expr string) (*LabelMatcher, error) {
 	return matcher, nil
 }
 
+// ValidateMatcher checks a structured matcher (e.g., from a VeneerPreference) against the
+// same rules as matchers parsed from annotations: the key must be in supportedLabels (nil
+// uses SupportedLabels), the operator must be known, Gt/Lt take exactly one integer value,
+// and Exists/DoesNotExist take no values.
+func ValidateMatcher(matcher LabelMatcher, supportedLabels LabelSet) error {
+	if !labelSetOrDefault(supportedLabels)[matcher.Key] {
+		return fmt.Errorf("unsupported label key %q: must be one of the supported Karpenter labels", matcher.Key)
+	}
+	if matcher.Operator == OperatorExists || matcher.Operator == OperatorDoesNotExist {
+		if len(matcher.Values) != 0 {
+			return fmt.Errorf("matcher for %q: %s operator takes no values", matcher.Key, matcher.Operator)
+		}
+		return nil
+	}
+	if len(matcher.Values) == 0 {
+		return fmt.Errorf("matcher for %q: at least one value is required", matcher.Key)
+	}
+
+	switch matcher.Operator {
+	case OperatorIn, OperatorNotIn:
+	case OperatorGt, OperatorLt:
+		if len(matcher.Values) != 1 {
+			return fmt.Errorf("matcher for %q: %s operator requires exactly one value", matcher.Key, matcher.Operator)
+		}
+		if _, err := strconv.Atoi(matcher.Values[0]); err != nil {
+			return fmt.Errorf("matcher for %q: %s operator requires a numeric value, got %q",
+				matcher.Key, matcher.Operator, matcher.Values[0])
+		}
+	default:
+		return fmt.Errorf("matcher for %q: unsupported operator %q (must be In, NotIn, Gt, Lt, Exists, or DoesNotExist)",
+			matcher.Key, matcher.Operator)
+	}
+
+	return nil
+}
+
+// labelSetOrDefault returns supportedLabels, or SupportedLabels if it is nil.
+func labelSetOrDefault(supportedLabels LabelSet) LabelSet {
+	if supportedLabels == nil {
+		return SupportedLabels
+	}
+	return supportedLabels
+}
+
 // parseValues splits a comma-separated value string and trims whitespace.
 func parseValues(valuesStr string) []string {
 	parts := strings.Split(valuesStr, ",")
@@ -258,24 +401,98 @@ func parseValues(valuesStr string) []string {
 	return values
 }
 
-// parseAdjustment parses an adjustment expression.
+// parseAdjustment parses an adjustment expression, returning the adjustment and whether
+// it is a percentage or an absolute dollar amount.
 //
-// Format: "adjust=[+-]N%" where N is a decimal number.
-// Examples: "adjust=-20%", "adjust=+40%", "adjust=15%"
-func parseAdjustment(expr string) (float64, error) {
+// Format: "adjust=[+-]N%" or "adjust=[+-]N" where N is a decimal number.
+// Examples: "adjust=-20%", "adjust=+40%", "adjust=15%", "adjust=-0.05"
+func parseAdjustment(expr string) (float64, AdjustmentType, error) {
 	expr = strings.TrimSpace(expr)
 
+	adjType := AdjustmentPercent
 	matches := adjustmentRegex.FindStringSubmatch(expr)
 	if matches == nil {
-		return 0, fmt.Errorf(
-			"invalid adjustment %q: expected format 'adjust=[+-]N%%' (e.g., 'adjust=-20%%')", expr)
+		adjType = AdjustmentAbsolute
+		matches = absoluteAdjustmentRegex.FindStringSubmatch(expr)
+	}
+	if matches == nil {
+		return 0, 0, fmt.Errorf(
+			"invalid adjustment %q: expected format 'adjust=[+-]N%%' or 'adjust=[+-]N' (e.g., 'adjust=-20%%' or 'adjust=-0.05')",
+			expr)
 	}
 
 	// matches[1] is the captured number (including optional sign)
 	adj, err := strconv.ParseFloat(matches[1], 64)
 	if err != nil {
-		return 0, fmt.Errorf("invalid adjustment %q: %w", expr, err)
+		return 0, 0, fmt.Errorf("invalid adjustment %q: %w", expr, err)
+	}
+
+	return adj, adjType, nil
+}
+
+// parsePrice parses a fixed price expression.
+//
+// Format: "price=N" where N is a non-negative decimal number of dollars per hour.
+// Examples: "price=0.10", "price=2"
+func parsePrice(expr string) (float64, error) {
+	expr = strings.TrimSpace(expr)
+
+	matches := priceRegex.FindStringSubmatch(expr)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid price %q: expected format 'price=N' (e.g., 'price=0.10')", expr)
+	}
+
+	price, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q: %w", expr, err)
+	}
+
+	return price, nil
+}
+
+// parseCapacity parses a capacity expression into capacity, which is allocated if nil.
+//
+// Format: "capacity=name:qty[,name:qty]" where name is an extended resource name and qty
+// is a non-negative Kubernetes quantity.
+// Examples: "capacity=example.com/fpga:1", "capacity=hugepages-2Mi:1Gi,example.com/nic:2"
+func parseCapacity(expr string, capacity *corev1.ResourceList) error {
+	expr = strings.TrimSpace(expr)
+
+	matches := capacityRegex.FindStringSubmatch(expr)
+	if matches == nil {
+		return fmt.Errorf("invalid capacity %q: expected format 'capacity=name:qty' (e.g., 'capacity=example.com/fpga:1')", expr)
+	}
+
+	for _, entry := range strings.Split(matches[1], ",") {
+		// Split on the last colon; resource names never contain one
+		idx := strings.LastIndex(entry, ":")
+		if idx <= 0 || idx == len(entry)-1 {
+			return fmt.Errorf("invalid capacity %q: expected 'name:qty', got %q", expr, entry)
+		}
+		name := corev1.ResourceName(entry[:idx])
+		if errs := validation.IsQualifiedName(string(name)); len(errs) > 0 {
+			return fmt.Errorf("invalid capacity %q: invalid resource name %q: %s", expr, name, strings.Join(errs, "; "))
+		}
+		if RestrictedCapacityResources[name] {
+			return fmt.Errorf("invalid capacity %q: resource %q cannot be overridden, only extended resources can be added",
+				expr, name)
+		}
+		qty, err := resource.ParseQuantity(entry[idx+1:])
+		if err != nil {
+			return fmt.Errorf("invalid capacity %q: invalid quantity for %q: %w", expr, name, err)
+		}
+		if qty.Sign() < 0 {
+			return fmt.Errorf("invalid capacity %q: quantity for %q must not be negative", expr, name)
+		}
+
+		if *capacity == nil {
+			*capacity = corev1.ResourceList{}
+		}
+		if _, exists := (*capacity)[name]; exists {
+			return fmt.Errorf("invalid capacity %q: resource %q is set more than once", expr, name)
+		}
+		(*capacity)[name] = qty
 	}
 
-	return adj, nil
+	return nil
 }
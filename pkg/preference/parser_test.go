@@ -17,7 +17,13 @@ limitations under the License.
 package preference
 
 import (
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 )
 
 //nolint:gocyclo // Table-driven tests with inline assertions have high cyclomatic complexity
@@ -338,7 +344,7 @@ func TestParseNodePoolPreferences(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			prefs, errs := ParseNodePoolPreferences(tt.annotations, tt.nodePoolName)
+			prefs, errs := ParseNodePoolPreferences(tt.annotations, tt.nodePoolName, nil)
 
 			if len(prefs) != tt.wantPrefs {
 				t.Errorf("expected %d preferences, got %d", tt.wantPrefs, len(prefs))
@@ -408,8 +414,74 @@ func TestParsePreferenceValue(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "invalid adjustment - no percent",
-			value:   "karpenter.k8s.aws/instance-family=c7a adjust=-20",
+			name:    "absolute adjustment",
+			value:   "karpenter.k8s.aws/instance-family=c7a adjust=-0.05",
+			wantErr: false,
+			checkPref: func(t *testing.T, p *Preference) {
+				if p.AdjustmentType != AdjustmentAbsolute || p.Adjustment != -0.05 {
+					t.Errorf("expected absolute Adjustment=-0.05, got %v %f", p.AdjustmentType, p.Adjustment)
+				}
+			},
+		},
+		{
+			name:    "fixed price",
+			value:   "node.kubernetes.io/instance-type=m7i.xlarge price=0.10",
+			wantErr: false,
+			checkPref: func(t *testing.T, p *Preference) {
+				if p.AdjustmentType != AdjustmentPrice || p.Price != 0.10 {
+					t.Errorf("expected Price=0.10, got %v %f", p.AdjustmentType, p.Price)
+				}
+			},
+		},
+		{
+			name:    "price and adjustment combined",
+			value:   "karpenter.k8s.aws/instance-family=c7a adjust=-20% price=0.10",
+			wantErr: true,
+		},
+		{
+			name:    "invalid price - negative",
+			value:   "karpenter.k8s.aws/instance-family=c7a price=-0.10",
+			wantErr: true,
+		},
+		{
+			name:    "price without matcher",
+			value:   "price=0.10",
+			wantErr: true,
+		},
+		{
+			name:    "capacity only",
+			value:   "node.kubernetes.io/instance-type=f1.2xlarge capacity=example.com/fpga:1",
+			wantErr: false,
+			checkPref: func(t *testing.T, p *Preference) {
+				if p.AdjustmentType != AdjustmentNone {
+					t.Errorf("expected AdjustmentNone, got %v", p.AdjustmentType)
+				}
+				if q := p.Capacity["example.com/fpga"]; q.Value() != 1 {
+					t.Errorf("expected example.com/fpga capacity 1, got %v", p.Capacity)
+				}
+			},
+		},
+		{
+			name:    "capacity with adjustment",
+			value:   "karpenter.k8s.aws/instance-family=c7a adjust=-10% capacity=hugepages-2Mi:1Gi",
+			wantErr: false,
+			checkPref: func(t *testing.T, p *Preference) {
+				if p.AdjustmentType != AdjustmentPercent || p.Adjustment != -10 {
+					t.Errorf("expected Adjustment=-10%%, got %v %f", p.AdjustmentType, p.Adjustment)
+				}
+				if q := p.Capacity["hugepages-2Mi"]; q.String() != "1Gi" {
+					t.Errorf("expected hugepages-2Mi capacity 1Gi, got %v", p.Capacity)
+				}
+			},
+		},
+		{
+			name:    "capacity without matcher",
+			value:   "capacity=example.com/fpga:1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid adjustment - unsigned absolute",
+			value:   "karpenter.k8s.aws/instance-family=c7a adjust=20",
 			wantErr: true,
 		},
 		{
@@ -421,7 +493,7 @@ func TestParsePreferenceValue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pref, err := parsePreferenceValue(tt.value)
+			pref, err := parsePreferenceValue(tt.value, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
 			}
@@ -506,10 +578,40 @@ func TestParseMatcher(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "no operator",
-			expr:    "karpenter.k8s.aws/instance-family",
+			name:    "bare key is Exists",
+			expr:    "karpenter.k8s.aws/instance-gpu-name",
+			wantErr: false,
+			check: func(t *testing.T, m *LabelMatcher) {
+				if m.Operator != OperatorExists || m.Key != LabelInstanceGPUName || len(m.Values) != 0 {
+					t.Errorf("expected Exists on %s with no values, got %+v", LabelInstanceGPUName, m)
+				}
+			},
+		},
+		{
+			name:    "negated key is DoesNotExist",
+			expr:    "!karpenter.k8s.aws/instance-accelerator-name",
+			wantErr: false,
+			check: func(t *testing.T, m *LabelMatcher) {
+				if m.Operator != OperatorDoesNotExist || m.Key != LabelInstanceAcceleratorName || len(m.Values) != 0 {
+					t.Errorf("expected DoesNotExist on %s with no values, got %+v", LabelInstanceAcceleratorName, m)
+				}
+			},
+		},
+		{
+			name:    "Exists on unsupported label",
+			expr:    "example.com/team",
+			wantErr: true,
+		},
+		{
+			name:    "DoesNotExist without key",
+			expr:    "!",
 			wantErr: true,
 		},
+		{
+			name:    "well-known zone label",
+			expr:    "topology.kubernetes.io/zone=us-west-2a,us-west-2b",
+			wantErr: false,
+		},
 		{
 			name:    "Gt with non-numeric value",
 			expr:    "karpenter.k8s.aws/instance-cpu>large",
@@ -529,7 +631,7 @@ func TestParseMatcher(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m, err := parseMatcher(tt.expr)
+			m, err := parseMatcher(tt.expr, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
 			}
@@ -542,10 +644,11 @@ func TestParseMatcher(t *testing.T) {
 
 func TestParseAdjustment(t *testing.T) {
 	tests := []struct {
-		name    string
-		expr    string
-		want    float64
-		wantErr bool
+		name     string
+		expr     string
+		want     float64
+		wantType AdjustmentType
+		wantErr  bool
 	}{
 		{
 			name:    "negative integer",
@@ -584,8 +687,20 @@ func TestParseAdjustment(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "missing percent",
-			expr:    "adjust=-20",
+			name:     "absolute negative",
+			expr:     "adjust=-0.05",
+			want:     -0.05,
+			wantType: AdjustmentAbsolute,
+		},
+		{
+			name:     "absolute positive",
+			expr:     "adjust=+1",
+			want:     1,
+			wantType: AdjustmentAbsolute,
+		},
+		{
+			name:    "absolute without sign",
+			expr:    "adjust=0.05",
 			wantErr: true,
 		},
 		{
@@ -607,7 +722,36 @@ func TestParseAdjustment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseAdjustment(tt.expr)
+			got, gotType, err := parseAdjustment(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && (got != tt.want || gotType != tt.wantType) {
+				t.Errorf("expected %f (type %v), got %f (type %v)", tt.want, tt.wantType, got, gotType)
+			}
+		})
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    float64
+		wantErr bool
+	}{
+		{name: "decimal", expr: "price=0.10", want: 0.10},
+		{name: "integer", expr: "price=2", want: 2},
+		{name: "zero", expr: "price=0", want: 0},
+		{name: "negative", expr: "price=-0.10", wantErr: true},
+		{name: "signed", expr: "price=+0.10", wantErr: true},
+		{name: "percentage", expr: "price=10%", wantErr: true},
+		{name: "missing value", expr: "price=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePrice(tt.expr)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
 			}
@@ -617,3 +761,310 @@ func TestParseAdjustment(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCapacity(t *testing.T) {
+	tests := []struct {
+		name    string
+		exprs   []string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "single resource",
+			exprs: []string{"capacity=example.com/fpga:1"},
+			want:  map[string]string{"example.com/fpga": "1"},
+		},
+		{
+			name:  "multiple resources",
+			exprs: []string{"capacity=example.com/fpga:2,hugepages-2Mi:512Mi"},
+			want:  map[string]string{"example.com/fpga": "2", "hugepages-2Mi": "512Mi"},
+		},
+		{
+			name:  "multiple expressions are merged",
+			exprs: []string{"capacity=example.com/fpga:2", "capacity=example.com/nic:1"},
+			want:  map[string]string{"example.com/fpga": "2", "example.com/nic": "1"},
+		},
+		{
+			name:    "missing quantity",
+			exprs:   []string{"capacity=example.com/fpga"},
+			wantErr: "expected 'name:qty'",
+		},
+		{
+			name:    "empty",
+			exprs:   []string{"capacity="},
+			wantErr: "expected format",
+		},
+		{
+			name:    "invalid quantity",
+			exprs:   []string{"capacity=example.com/fpga:lots"},
+			wantErr: "invalid quantity",
+		},
+		{
+			name:    "negative quantity",
+			exprs:   []string{"capacity=example.com/fpga:-1"},
+			wantErr: "must not be negative",
+		},
+		{
+			name:    "invalid resource name",
+			exprs:   []string{"capacity=example.com/fp ga:1"},
+			wantErr: "invalid resource name",
+		},
+		{
+			name:    "restricted resource",
+			exprs:   []string{"capacity=memory:1Gi"},
+			wantErr: "cannot be overridden",
+		},
+		{
+			name:    "duplicate resource",
+			exprs:   []string{"capacity=example.com/fpga:1", "capacity=example.com/fpga:2"},
+			wantErr: "set more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capacity corev1.ResourceList
+			var err error
+			for _, expr := range tt.exprs {
+				if err = parseCapacity(expr, &capacity); err != nil {
+					break
+				}
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make(map[string]string, len(capacity))
+			for name, qty := range capacity {
+				got[string(name)] = qty.String()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected capacity %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckAdjustmentRange(t *testing.T) {
+	prefs := []Preference{
+		{Number: 1, Adjustment: -20},
+		{Number: 2, Adjustment: -100},
+		{Number: 3, Adjustment: -150},
+		{Number: 4, Adjustment: 50},
+		{Number: 5, Adjustment: 120},
+		// Absolute adjustments and fixed prices are checked against the dollar limit
+		{Number: 6, Adjustment: -0.05, AdjustmentType: AdjustmentAbsolute},
+		{Number: 7, Adjustment: -150, AdjustmentType: AdjustmentAbsolute},
+		{Number: 8, Price: 2.5, AdjustmentType: AdjustmentPrice},
+		{Number: 9, Price: 500, AdjustmentType: AdjustmentPrice},
+		{Number: 10, Price: -1, AdjustmentType: AdjustmentPrice},
+	}
+
+	valid, errs := CheckAdjustmentRange(prefs, -100, 100, 10)
+
+	var validNumbers []int
+	for _, pref := range valid {
+		validNumbers = append(validNumbers, pref.Number)
+	}
+	if !reflect.DeepEqual(validNumbers, []int{1, 2, 4, 6, 8}) {
+		t.Errorf("expected preferences 1, 2, 4, 6 and 8 to be valid, got %v", validNumbers)
+	}
+
+	wantErrors := []string{
+		`annotation "veneer.io/preference.3": adjustment -150% is outside the allowed range -100% to +100%`,
+		`annotation "veneer.io/preference.5": adjustment +120% is outside the allowed range -100% to +100%`,
+		`annotation "veneer.io/preference.7": adjustment -150 dollars per hour is outside the allowed range -10 to +10 ` +
+			`(use adjust=-150% for a percentage)`,
+		`annotation "veneer.io/preference.9": price 500 dollars per hour is outside the allowed range 0 to 10`,
+		`annotation "veneer.io/preference.10": price -1 dollars per hour is outside the allowed range 0 to 10`,
+	}
+	if len(errs) != len(wantErrors) {
+		t.Fatalf("expected %d errors, got %d: %v", len(wantErrors), len(errs), errs)
+	}
+	for i, want := range wantErrors {
+		if errs[i].Error() != want {
+			t.Errorf("error %d = %q, want %q", i, errs[i].Error(), want)
+		}
+	}
+}
+
+func TestCheckAdjustmentRange_ForgottenPercent(t *testing.T) {
+	annotations := map[string]string{
+		"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7g adjust=-20",
+	}
+	prefs, errs := ParseNodePoolPreferences(annotations, "my-pool", nil)
+	if len(errs) != 0 || len(prefs) != 1 {
+		t.Fatalf("expected adjust=-20 to parse as an absolute adjustment, got %v, %v", prefs, errs)
+	}
+
+	valid, errs := CheckAdjustmentRange(prefs, -100, 100, 10)
+	if len(valid) != 0 || len(errs) != 1 {
+		t.Fatalf("expected adjust=-20 to be rejected, got valid %v, errors %v", valid, errs)
+	}
+	if !strings.Contains(errs[0].Error(), "use adjust=-20% for a percentage") {
+		t.Errorf("expected the error to suggest a percentage, got %q", errs[0].Error())
+	}
+}
+
+func TestCheckAdjustmentRange_CapacityOnly(t *testing.T) {
+	annotations := map[string]string{
+		"veneer.io/preference.1": "node.kubernetes.io/instance-type=f1.2xlarge capacity=example.com/fpga:1",
+	}
+	prefs, errs := ParseNodePoolPreferences(annotations, "my-pool", nil)
+	if len(errs) != 0 || len(prefs) != 1 {
+		t.Fatalf("expected the capacity-only preference to parse, got %v, %v", prefs, errs)
+	}
+
+	// The range excludes 0, which a capacity-only preference's adjustment is
+	valid, errs := CheckAdjustmentRange(prefs, -50, -5, 10)
+	if len(valid) != 1 || len(errs) != 0 {
+		t.Errorf("expected the capacity-only preference to be allowed, got valid %v, errors %v", valid, errs)
+	}
+}
+
+func TestValidateMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher LabelMatcher
+		wantErr string
+	}{
+		{
+			name:    "In with multiple values",
+			matcher: LabelMatcher{Key: LabelInstanceFamily, Operator: OperatorIn, Values: []string{"c7g", "m7g"}},
+		},
+		{
+			name:    "Gt with numeric value",
+			matcher: LabelMatcher{Key: LabelInstanceCPU, Operator: OperatorGt, Values: []string{"8"}},
+		},
+		{
+			name:    "unsupported key",
+			matcher: LabelMatcher{Key: "example.com/team", Operator: OperatorIn, Values: []string{"platform"}},
+			wantErr: "unsupported label key",
+		},
+		{
+			name:    "no values",
+			matcher: LabelMatcher{Key: LabelArch, Operator: OperatorIn},
+			wantErr: "at least one value is required",
+		},
+		{
+			name:    "Lt with non-numeric value",
+			matcher: LabelMatcher{Key: LabelInstanceMemory, Operator: OperatorLt, Values: []string{"large"}},
+			wantErr: "requires a numeric value",
+		},
+		{
+			name:    "Gt with multiple values",
+			matcher: LabelMatcher{Key: LabelInstanceCPU, Operator: OperatorGt, Values: []string{"4", "8"}},
+			wantErr: "exactly one value",
+		},
+		{
+			name:    "Exists without values",
+			matcher: LabelMatcher{Key: LabelInstanceGPUCount, Operator: OperatorExists},
+		},
+		{
+			name:    "DoesNotExist with values",
+			matcher: LabelMatcher{Key: LabelArch, Operator: OperatorDoesNotExist, Values: []string{"arm64"}},
+			wantErr: "takes no values",
+		},
+		{
+			name:    "unknown operator",
+			matcher: LabelMatcher{Key: LabelArch, Operator: "Gte", Values: []string{"4"}},
+			wantErr: "unsupported operator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMatcher(tt.matcher, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParsePreference_SupportedLabels(t *testing.T) {
+	custom := NewLabelSet([]string{"example.com/team"})
+
+	if _, err := ParsePreference("example.com/team=platform adjust=-10%", custom); err != nil {
+		t.Errorf("expected custom label to be allowed, got %v", err)
+	}
+	if _, err := ParsePreference("karpenter.k8s.aws/instance-family=c7g adjust=-10%", custom); err == nil {
+		t.Error("expected default label to be rejected when not in the configured set")
+	}
+	if _, err := ParsePreference("example.com/team=platform adjust=-10%", nil); err == nil {
+		t.Error("expected custom label to be rejected by the default set")
+	}
+}
+
+func TestSupportedLabels(t *testing.T) {
+	for _, key := range karpenterv1.WellKnownLabels.UnsortedList() {
+		if !SupportedLabels[key] {
+			t.Errorf("expected Karpenter well-known label %q to be supported", key)
+		}
+	}
+
+	// Pins the default set; update it together with the Karpenter AWS provider's labels.
+	want := []string{
+		"karpenter.k8s.aws/capacity-reservation-id",
+		"karpenter.k8s.aws/capacity-reservation-type",
+		"karpenter.k8s.aws/instance-accelerator-count",
+		"karpenter.k8s.aws/instance-accelerator-manufacturer",
+		"karpenter.k8s.aws/instance-accelerator-name",
+		"karpenter.k8s.aws/instance-capability-flex",
+		"karpenter.k8s.aws/instance-category",
+		"karpenter.k8s.aws/instance-cpu",
+		"karpenter.k8s.aws/instance-cpu-manufacturer",
+		"karpenter.k8s.aws/instance-cpu-sustained-clock-speed-mhz",
+		"karpenter.k8s.aws/instance-ebs-bandwidth",
+		"karpenter.k8s.aws/instance-encryption-in-transit-supported",
+		"karpenter.k8s.aws/instance-family",
+		"karpenter.k8s.aws/instance-generation",
+		"karpenter.k8s.aws/instance-gpu-count",
+		"karpenter.k8s.aws/instance-gpu-manufacturer",
+		"karpenter.k8s.aws/instance-gpu-memory",
+		"karpenter.k8s.aws/instance-gpu-name",
+		"karpenter.k8s.aws/instance-hypervisor",
+		"karpenter.k8s.aws/instance-local-nvme",
+		"karpenter.k8s.aws/instance-memory",
+		"karpenter.k8s.aws/instance-network-bandwidth",
+		"karpenter.k8s.aws/instance-size",
+		"karpenter.k8s.aws/instance-tenancy",
+		"karpenter.sh/capacity-type",
+		"karpenter.sh/nodepool",
+		"kubernetes.io/arch",
+		"kubernetes.io/os",
+		"node.kubernetes.io/instance-type",
+		"node.kubernetes.io/windows-build",
+		"topology.k8s.aws/zone-id",
+		"topology.kubernetes.io/region",
+		"topology.kubernetes.io/zone",
+	}
+	got := make([]string, 0, len(SupportedLabels))
+	for key := range SupportedLabels {
+		got = append(got, key)
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SupportedLabels = %v, want %v", got, want)
+	}
+}
+
+func TestNewLabelSet(t *testing.T) {
+	if got := NewLabelSet(nil); !reflect.DeepEqual(got, SupportedLabels) {
+		t.Error("expected empty keys to return SupportedLabels")
+	}
+	if got := NewLabelSet([]string{LabelArch, LabelZone}); len(got) != 2 || !got[LabelArch] || !got[LabelZone] {
+		t.Errorf("expected set of the given keys, got %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preference
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Status is the machine-readable parse result of a NodePool's preference annotations,
+// written to the AnnotationPreferencesStatus annotation so CI tooling and humans can
+// confirm their annotations took effect.
+type Status struct {
+	// Preferences has one entry per preference annotation, sorted by annotation key number.
+	Preferences []PreferenceStatus `json:"preferences"`
+}
+
+// PreferenceStatus is the parse result of a single veneer.io/preference.N annotation.
+type PreferenceStatus struct {
+	// Annotation is the full annotation key (e.g., "veneer.io/preference.3").
+	Annotation string `json:"annotation"`
+
+	// Number is the preference number. Omitted if the annotation key has no valid number.
+	Number int `json:"number,omitempty"`
+
+	// Valid reports whether the annotation parsed successfully.
+	Valid bool `json:"valid"`
+
+	// Overlay is the name of the NodeOverlay generated from the preference. Empty if invalid.
+	Overlay string `json:"overlay,omitempty"`
+
+	// Error describes why the annotation failed to parse. Empty if valid.
+	Error string `json:"error,omitempty"`
+}
+
+// NewStatus builds the Status of a NodePool from the results of ParseNodePoolPreferences.
+func NewStatus(nodePoolName string, prefs []Preference, parseErrors []error) Status {
+	status := Status{Preferences: make([]PreferenceStatus, 0, len(prefs)+len(parseErrors))}
+
+	for _, pref := range prefs {
+		status.Preferences = append(status.Preferences, PreferenceStatus{
+			Annotation: AnnotationPrefix + strconv.Itoa(pref.Number),
+			Number:     pref.Number,
+			Valid:      true,
+			Overlay:    OverlayNameForPreference(nodePoolName, pref.Number),
+		})
+	}
+
+	for _, err := range parseErrors {
+		entry := PreferenceStatus{Error: err.Error()}
+		var parseErr ParseError
+		if errors.As(err, &parseErr) {
+			entry.Annotation = parseErr.AnnotationKey
+			entry.Error = parseErr.Message
+			if num, convErr := strconv.Atoi(strings.TrimPrefix(parseErr.AnnotationKey, AnnotationPrefix)); convErr == nil && num > 0 {
+				entry.Number = num
+			}
+		}
+		status.Preferences = append(status.Preferences, entry)
+	}
+
+	// Annotations come from a map, so sort for a stable annotation value.
+	// Entries without a number sort last, by annotation key.
+	sort.Slice(status.Preferences, func(i, j int) bool {
+		a, b := status.Preferences[i], status.Preferences[j]
+		if (a.Number == 0) != (b.Number == 0) {
+			return b.Number == 0
+		}
+		if a.Number != b.Number {
+			return a.Number < b.Number
+		}
+		return a.Annotation < b.Annotation
+	})
+
+	return status
+}
+
+// Encode returns the Status as the JSON value of the AnnotationPreferencesStatus annotation.
+func (s Status) Encode() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preference
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []PreferenceStatus
+	}{
+		{
+			name:        "no preferences",
+			annotations: map[string]string{"other": "value"},
+			want:        []PreferenceStatus{},
+		},
+		{
+			name: "valid preferences sorted by number",
+			annotations: map[string]string{
+				"veneer.io/preference.10": "kubernetes.io/arch=arm64 adjust=-10%",
+				"veneer.io/preference.2":  "karpenter.k8s.aws/instance-family=c7g adjust=-20%",
+			},
+			want: []PreferenceStatus{
+				{Annotation: "veneer.io/preference.2", Number: 2, Valid: true, Overlay: "pref-web-2"},
+				{Annotation: "veneer.io/preference.10", Number: 10, Valid: true, Overlay: "pref-web-10"},
+			},
+		},
+		{
+			name: "invalid preferences report their error",
+			annotations: map[string]string{
+				"veneer.io/preference.1":   "karpenter.k8s.aws/instance-family=c7g adjust=-20%",
+				"veneer.io/preference.3":   "example.com/team=web adjust=-10%",
+				"veneer.io/preference.abc": "kubernetes.io/arch=arm64 adjust=-10%",
+			},
+			want: []PreferenceStatus{
+				{Annotation: "veneer.io/preference.1", Number: 1, Valid: true, Overlay: "pref-web-1"},
+				{
+					Annotation: "veneer.io/preference.3",
+					Number:     3,
+					Error:      `unsupported label key "example.com/team": must be one of the supported Karpenter labels`,
+				},
+				{
+					Annotation: "veneer.io/preference.abc",
+					Error:      `invalid preference number "abc": must be a positive integer`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs, errs := ParseNodePoolPreferences(tt.annotations, "web", nil)
+			got := NewStatus("web", prefs, errs)
+			if !reflect.DeepEqual(got.Preferences, tt.want) {
+				t.Errorf("NewStatus() = %+v, want %+v", got.Preferences, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatus_Encode(t *testing.T) {
+	status := Status{Preferences: []PreferenceStatus{
+		{Annotation: "veneer.io/preference.1", Number: 1, Valid: true, Overlay: "pref-web-1"},
+		{Annotation: "veneer.io/preference.2", Number: 2, Error: "empty preference value"},
+	}}
+
+	got, err := status.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := `{"preferences":[` +
+		`{"annotation":"veneer.io/preference.1","number":1,"valid":true,"overlay":"pref-web-1"},` +
+		`{"annotation":"veneer.io/preference.2","number":2,"valid":false,"error":"empty preference value"}]}`
+	if got != want {
+		t.Errorf("Encode() = %s, want %s", got, want)
+	}
+}
@@ -23,11 +23,34 @@ limitations under the License.
 // to influence provisioning decisions.
 package preference
 
+import (
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
 // Annotation prefix for all preference annotations on NodePools.
 // Preferences are numbered (1-N) and parsed in order. Lower numbers have lower
 // weight/priority, higher numbers override when multiple preferences match.
 const AnnotationPrefix = "veneer.io/preference."
 
+// AnnotationPreferencesStatus is the NodePool annotation Veneer writes the parse result of
+// the NodePool's preference annotations to, as JSON (see Status).
+const AnnotationPreferencesStatus = "veneer.io/preferences-status"
+
+// AnnotationPreferencesDisabled opts a NodePool out of preferences when set to "true":
+// Veneer ignores its preference annotations, leaves it out of VeneerPreference and
+// cluster-wide preference selections, and removes the preference overlays targeting it.
+const AnnotationPreferencesDisabled = "veneer.io/preferences-disabled"
+
+// PreferencesDisabled reports whether a NodePool with the given annotations has opted out
+// of preferences with the veneer.io/preferences-disabled annotation.
+func PreferencesDisabled(annotations map[string]string) bool {
+	return annotations[AnnotationPreferencesDisabled] == "true"
+}
+
 // Label constants used on preference-based NodeOverlays.
 const (
 	// LabelManagedBy identifies that Veneer manages this NodeOverlay.
@@ -49,6 +72,18 @@ const (
 	// LabelPreferenceNumber identifies the preference number (1-N) from the annotation.
 	LabelPreferenceNumber = "veneer.io/preference-number"
 
+	// LabelSourcePreference identifies which VeneerPreference this overlay was generated from.
+	// Overlays generated from VeneerPreferences carry this label instead of LabelSourceNodePool
+	// and LabelPreferenceNumber, so the NodePool reconciler never treats them as its own.
+	LabelSourcePreference = "veneer.io/source-preference"
+
+	// LabelSourceClusterPreference identifies which cluster-wide preference (from the
+	// preferences.clusterPreferences config section) this overlay was generated from.
+	LabelSourceClusterPreference = "veneer.io/source-cluster-preference"
+
+	// LabelTargetNodePool identifies the NodePool a VeneerPreference overlay is scoped to.
+	LabelTargetNodePool = "veneer.io/target-nodepool"
+
 	// LabelDisabledKey is the label key used to create an impossible requirement
 	// when disabled mode is enabled.
 	LabelDisabledKey = "veneer.io/disabled"
@@ -100,22 +135,141 @@ const (
 	// LabelInstanceType is the standard Kubernetes label for instance type.
 	// Examples: "m5.xlarge", "c7g.large"
 	LabelInstanceType = "node.kubernetes.io/instance-type"
+
+	// LabelOS is the standard Kubernetes label for the operating system.
+	// Examples: "linux", "windows"
+	LabelOS = "kubernetes.io/os"
+
+	// LabelZone is the standard Kubernetes label for the availability zone.
+	// Examples: "us-west-2a"
+	LabelZone = "topology.kubernetes.io/zone"
+
+	// LabelRegion is the standard Kubernetes label for the region.
+	// Examples: "us-west-2"
+	LabelRegion = "topology.kubernetes.io/region"
+
+	// LabelZoneID is the Karpenter AWS label for the availability zone ID.
+	// Examples: "usw2-az1"
+	LabelZoneID = "topology.k8s.aws/zone-id"
+
+	// LabelInstanceHypervisor is the Karpenter label for the instance hypervisor.
+	// Examples: "nitro", "xen", "" (bare metal)
+	LabelInstanceHypervisor = "karpenter.k8s.aws/instance-hypervisor"
+
+	// LabelInstanceEncryptionInTransitSupported is the Karpenter label for whether the
+	// instance supports encryption in transit. Examples: "true", "false"
+	LabelInstanceEncryptionInTransitSupported = "karpenter.k8s.aws/instance-encryption-in-transit-supported"
+
+	// LabelInstanceCPUSustainedClockSpeed is the Karpenter label for the sustained CPU clock speed in MHz.
+	LabelInstanceCPUSustainedClockSpeed = "karpenter.k8s.aws/instance-cpu-sustained-clock-speed-mhz"
+
+	// LabelInstanceLocalNVMe is the Karpenter label for local NVMe storage in GiB.
+	LabelInstanceLocalNVMe = "karpenter.k8s.aws/instance-local-nvme"
+
+	// LabelInstanceEBSBandwidth is the Karpenter label for maximum EBS bandwidth in Mbps.
+	LabelInstanceEBSBandwidth = "karpenter.k8s.aws/instance-ebs-bandwidth"
+
+	// LabelInstanceNetworkBandwidth is the Karpenter label for baseline network bandwidth in Mbps.
+	LabelInstanceNetworkBandwidth = "karpenter.k8s.aws/instance-network-bandwidth"
+
+	// LabelInstanceGPUName is the Karpenter label for the GPU model.
+	// Examples: "t4", "a100"
+	LabelInstanceGPUName = "karpenter.k8s.aws/instance-gpu-name"
+
+	// LabelInstanceGPUManufacturer is the Karpenter label for the GPU manufacturer.
+	// Examples: "nvidia", "amd"
+	LabelInstanceGPUManufacturer = "karpenter.k8s.aws/instance-gpu-manufacturer"
+
+	// LabelInstanceGPUCount is the Karpenter label for the number of GPUs.
+	LabelInstanceGPUCount = "karpenter.k8s.aws/instance-gpu-count"
+
+	// LabelInstanceGPUMemory is the Karpenter label for GPU memory in MiB.
+	LabelInstanceGPUMemory = "karpenter.k8s.aws/instance-gpu-memory"
+
+	// LabelInstanceAcceleratorName is the Karpenter label for the accelerator model.
+	// Examples: "inferentia", "trainium"
+	LabelInstanceAcceleratorName = "karpenter.k8s.aws/instance-accelerator-name"
+
+	// LabelInstanceAcceleratorManufacturer is the Karpenter label for the accelerator manufacturer.
+	// Examples: "aws"
+	LabelInstanceAcceleratorManufacturer = "karpenter.k8s.aws/instance-accelerator-manufacturer"
+
+	// LabelInstanceAcceleratorCount is the Karpenter label for the number of accelerators.
+	LabelInstanceAcceleratorCount = "karpenter.k8s.aws/instance-accelerator-count"
+
+	// LabelInstanceCapabilityFlex is the Karpenter label for whether the instance type is a
+	// flex instance type. Examples: "true", "false"
+	LabelInstanceCapabilityFlex = "karpenter.k8s.aws/instance-capability-flex"
+
+	// LabelInstanceTenancy is the Karpenter label for the instance tenancy.
+	// Examples: "default", "dedicated"
+	LabelInstanceTenancy = "karpenter.k8s.aws/instance-tenancy"
+
+	// LabelCapacityReservationID is the Karpenter label for the ID of the capacity
+	// reservation an instance launches into.
+	LabelCapacityReservationID = "karpenter.k8s.aws/capacity-reservation-id"
+
+	// LabelCapacityReservationType is the Karpenter label for the type of the capacity
+	// reservation. Examples: "default", "capacity-block"
+	LabelCapacityReservationType = "karpenter.k8s.aws/capacity-reservation-type"
 )
 
-// SupportedLabels is the set of labels that can be used in preference matchers.
-// Using labels outside this set will result in a parse error to prevent typos
+// LabelSet is a set of label keys that can be used in preference matchers.
+type LabelSet map[string]bool
+
+// awsWellKnownLabels are the labels the Karpenter AWS provider adds to Karpenter's
+// well-known labels. Veneer does not depend on the provider, so they are listed here.
+var awsWellKnownLabels = []string{
+	LabelInstanceFamily,
+	LabelInstanceCategory,
+	LabelInstanceGeneration,
+	LabelInstanceSize,
+	LabelInstanceCPU,
+	LabelInstanceCPUManufacturer,
+	LabelInstanceCPUSustainedClockSpeed,
+	LabelInstanceMemory,
+	LabelInstanceHypervisor,
+	LabelInstanceEncryptionInTransitSupported,
+	LabelInstanceLocalNVMe,
+	LabelInstanceEBSBandwidth,
+	LabelInstanceNetworkBandwidth,
+	LabelInstanceGPUName,
+	LabelInstanceGPUManufacturer,
+	LabelInstanceGPUCount,
+	LabelInstanceGPUMemory,
+	LabelInstanceAcceleratorName,
+	LabelInstanceAcceleratorManufacturer,
+	LabelInstanceAcceleratorCount,
+	LabelInstanceCapabilityFlex,
+	LabelInstanceTenancy,
+	LabelCapacityReservationID,
+	LabelCapacityReservationType,
+	LabelZoneID,
+}
+
+// SupportedLabels is the default set of labels that can be used in preference matchers:
+// Karpenter's well-known labels (karpenterv1.WellKnownLabels) plus the ones the Karpenter
+// AWS provider adds. Deployments can replace it with the preferences.supportedLabels
+// config option.
+// Using labels outside the set will result in a parse error to prevent typos
 // and unsupported label usage.
-var SupportedLabels = map[string]bool{
-	LabelInstanceFamily:          true,
-	LabelInstanceCategory:        true,
-	LabelInstanceGeneration:      true,
-	LabelInstanceSize:            true,
-	LabelInstanceCPU:             true,
-	LabelInstanceCPUManufacturer: true,
-	LabelInstanceMemory:          true,
-	LabelArch:                    true,
-	LabelCapacityType:            true,
-	LabelInstanceType:            true,
+var SupportedLabels = labelSetOf(append(karpenterv1.WellKnownLabels.UnsortedList(), awsWellKnownLabels...))
+
+// NewLabelSet returns the set of the given label keys, or SupportedLabels if keys is empty.
+func NewLabelSet(keys []string) LabelSet {
+	if len(keys) == 0 {
+		return SupportedLabels
+	}
+	return labelSetOf(keys)
+}
+
+// labelSetOf returns the set of the given label keys.
+func labelSetOf(keys []string) LabelSet {
+	set := make(LabelSet, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
 }
 
 // Operator represents the comparison operator in a label matcher.
@@ -140,13 +294,21 @@ const (
 	// Used for numeric labels like instance-cpu or instance-memory.
 	// Example: "karpenter.k8s.aws/instance-memory<16384"
 	OperatorLt Operator = "Lt"
+
+	// OperatorExists matches when the label is present, with any value.
+	// Example: "karpenter.k8s.aws/instance-gpu-name"
+	OperatorExists Operator = "Exists"
+
+	// OperatorDoesNotExist matches when the label is absent.
+	// Example: "!karpenter.k8s.aws/instance-accelerator-name"
+	OperatorDoesNotExist Operator = "DoesNotExist"
 )
 
 // LabelMatcher represents a single label matching condition.
 // Multiple matchers in a preference are ANDed together.
 type LabelMatcher struct {
 	// Key is the label key to match against.
-	// Must be one of the SupportedLabels.
+	// Must be in the configured LabelSet (SupportedLabels by default).
 	Key string
 
 	// Operator is the comparison operator.
@@ -155,9 +317,41 @@ type LabelMatcher struct {
 	// Values are the values to compare against.
 	// For In/NotIn: multiple values allowed (any match)
 	// For Gt/Lt: exactly one numeric value
+	// For Exists/DoesNotExist: no values
 	Values []string
 }
 
+// AdjustmentType identifies how a preference changes the price of matching instances.
+type AdjustmentType int
+
+const (
+	// AdjustmentPercent adjusts the price by a percentage.
+	// Example: "adjust=-20%"
+	AdjustmentPercent AdjustmentType = iota
+
+	// AdjustmentAbsolute adjusts the price by a signed amount in dollars per hour.
+	// Example: "adjust=-0.05"
+	AdjustmentAbsolute
+
+	// AdjustmentPrice replaces the price with a fixed amount in dollars per hour.
+	// Useful for pinning a negotiated rate for a specific instance type.
+	// Example: "node.kubernetes.io/instance-type=m7i.xlarge price=0.10"
+	AdjustmentPrice
+
+	// AdjustmentNone leaves the price unchanged, for preferences that only set Capacity.
+	// Example: "node.kubernetes.io/instance-type=f1.2xlarge capacity=example.com/fpga:1"
+	AdjustmentNone
+)
+
+// RestrictedCapacityResources are the resources Karpenter does not allow a NodeOverlay to
+// override. Capacity preferences may only add extended resources.
+var RestrictedCapacityResources = map[corev1.ResourceName]bool{
+	corev1.ResourceCPU:              true,
+	corev1.ResourceMemory:           true,
+	corev1.ResourceEphemeralStorage: true,
+	corev1.ResourcePods:             true,
+}
+
 // Preference represents a parsed instance type preference from a NodePool annotation.
 // A preference specifies which instance types should have their effective price adjusted,
 // making them more or less attractive to Karpenter's provisioning logic.
@@ -171,12 +365,53 @@ type Preference struct {
 	// Multiple matchers are ANDed together.
 	Matchers []LabelMatcher
 
-	// Adjustment is the price adjustment percentage.
+	// Adjustment is the price adjustment, as a percentage or in dollars per hour
+	// depending on AdjustmentType. Unused for AdjustmentPrice.
 	// Negative values (e.g., -20) make instances cheaper (more preferred).
 	// Positive values (e.g., +40) make instances more expensive (less preferred).
 	Adjustment float64
 
+	// AdjustmentType determines how the preference changes the price of matching instances.
+	// The zero value is AdjustmentPercent.
+	AdjustmentType AdjustmentType
+
+	// Price is the fixed price in dollars per hour for AdjustmentPrice preferences.
+	Price float64
+
+	// Capacity lists extended resources to add to matching instance types
+	// (e.g., "example.com/fpga": 1). Optional; may be combined with any AdjustmentType.
+	Capacity corev1.ResourceList
+
 	// NodePoolName is the name of the NodePool this preference came from.
 	// Used to scope the generated overlay to only affect this NodePool's instances.
 	NodePoolName string
 }
+
+// CheckAdjustment returns an error if the preference's price change is outside the allowed
+// limits: percentage adjustments must lie within [minAdjustment, maxAdjustment] percent,
+// absolute adjustments within maxAbsoluteAdjustment dollars per hour in either direction,
+// and fixed prices between 0 and maxAbsoluteAdjustment dollars per hour. Capacity-only
+// preferences leave the price unchanged, so they are always allowed.
+func (p Preference) CheckAdjustment(minAdjustment, maxAdjustment, maxAbsoluteAdjustment float64) error {
+	switch p.AdjustmentType {
+	case AdjustmentNone:
+		return nil
+	case AdjustmentAbsolute:
+		if math.Abs(p.Adjustment) > maxAbsoluteAdjustment {
+			// A forgotten % is the likely mistake, e.g. adjust=-20 meant as -20%
+			return fmt.Errorf("adjustment %+g dollars per hour is outside the allowed range -%g to +%g "+
+				"(use adjust=%+g%% for a percentage)", p.Adjustment, maxAbsoluteAdjustment, maxAbsoluteAdjustment, p.Adjustment)
+		}
+	case AdjustmentPrice:
+		if p.Price < 0 || p.Price > maxAbsoluteAdjustment {
+			return fmt.Errorf("price %g dollars per hour is outside the allowed range 0 to %g",
+				p.Price, maxAbsoluteAdjustment)
+		}
+	default:
+		if p.Adjustment < minAdjustment || p.Adjustment > maxAdjustment {
+			return fmt.Errorf("adjustment %+g%% is outside the allowed range %+g%% to %+g%%",
+				p.Adjustment, minAdjustment, maxAdjustment)
+		}
+	}
+	return nil
+}
@@ -150,7 +150,8 @@ type ReservedInstance struct {
 	// InstanceType is the EC2 instance type (e.g., "m5.xlarge")
 	InstanceType string
 
-	// AvailabilityZone is the AZ where the RI is available
+	// AvailabilityZone is the AZ a zonal RI is scoped to.
+	// Empty for regional RIs, which apply to matching instances in any AZ of the region.
 	AvailabilityZone string
 
 	// Count is the number of RIs (typically 1 per metric)
@@ -160,6 +161,11 @@ type ReservedInstance struct {
 	Timestamp time.Time
 }
 
+// Zonal reports whether the RI is scoped to a single availability zone.
+func (ri ReservedInstance) Zonal() bool {
+	return ri.AvailabilityZone != ""
+}
+
 // QuerySavingsPlanCapacity queries Prometheus for Savings Plan remaining capacity and hourly commitment.
 // The instanceFamily parameter filters results (e.g., "m5", "c5").
 // Pass empty string to get all instance families (includes both EC2 Instance and Compute SPs).
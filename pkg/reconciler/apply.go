@@ -0,0 +1,233 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/overlay"
+	"github.com/nextdoor/veneer/pkg/preference"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// FieldManager is the server-side apply field manager Veneer writes NodeOverlays as.
+const FieldManager = "veneer"
+
+// GroupVersionKinds of the Karpenter resources Veneer applies.
+// Karpenter doesn't export SchemeGroupVersion, so we define them manually.
+var (
+	nodeOverlayGVK = schema.GroupVersionKind{Group: "karpenter.sh", Version: "v1alpha1", Kind: "NodeOverlay"}
+	nodePoolGVK    = schema.GroupVersionKind{Group: "karpenter.sh", Version: "v1", Kind: "NodePool"}
+)
+
+// applyOverlay creates or updates a NodeOverlay with server-side apply.
+//
+// Only the fields set on desired are sent, so Veneer owns exactly those fields: labels,
+// annotations, and spec fields set by other actors (GitOps tools, admins) are preserved,
+// and fields Veneer stops setting are removed unless another manager also owns them.
+// No resourceVersion is sent, so concurrent writes to other fields never fail the apply.
+//
+// If another manager owns a field Veneer sets to a different value, the apply fails with
+// a conflict error unless force is true, in which case Veneer takes ownership of the field.
+//
+// existing is the overlay currently in the cluster, or nil if there is none. Overlays Veneer
+// wrote before it used server-side apply are owned by its old Create/Update field manager,
+// so their first apply always forces ownership to migrate them.
+//
+// On success, desired's UID and resourceVersion are set from the applied object.
+func applyOverlay(
+	ctx context.Context,
+	c client.Client,
+	desired *karpenterv1alpha1.NodeOverlay,
+	existing *karpenterv1alpha1.NodeOverlay,
+	force bool,
+) error {
+	if existing != nil && needsOwnershipMigration(existing) {
+		force = true
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return fmt.Errorf("failed to convert NodeOverlay %s: %w", desired.Name, err)
+	}
+
+	// Drop fields Veneer never sets so it doesn't claim ownership of them
+	delete(content, "status")
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetGroupVersionKind(nodeOverlayGVK)
+
+	opts := []client.ApplyOption{client.FieldOwner(FieldManager)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	if err := c.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj), opts...); err != nil {
+		if errors.IsConflict(err) && !force {
+			return fmt.Errorf("NodeOverlay %s has fields owned by another field manager "+
+				"(set overlays.forceConflicts to take ownership): %w", desired.Name, err)
+		}
+		return err
+	}
+
+	// Record the server-assigned identity so events can reference the overlay
+	desired.UID = obj.GetUID()
+	desired.ResourceVersion = obj.GetResourceVersion()
+	return nil
+}
+
+// overlayChanged reports whether an apply of desired changed existing. The API server only
+// assigns a new resourceVersion when the stored object changes, so an apply that matched
+// what was already stored leaves it as it was.
+func overlayChanged(existing, desired *karpenterv1alpha1.NodeOverlay) bool {
+	return desired.ResourceVersion != existing.ResourceVersion
+}
+
+// applyPreferencesStatus writes the preferences-status annotation of a NodePool with
+// server-side apply, so Veneer owns only that annotation and never touches the rest of
+// the NodePool. An empty value removes the annotation.
+//
+// The annotation is Veneer's alone, so ownership is always forced.
+func applyPreferencesStatus(ctx context.Context, c client.Client, nodePoolName, value string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(nodePoolGVK)
+	obj.SetName(nodePoolName)
+	if value != "" {
+		obj.SetAnnotations(map[string]string{preference.AnnotationPreferencesStatus: value})
+	}
+
+	return c.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj),
+		client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// needsOwnershipMigration reports whether an overlay is labeled as managed by Veneer
+// but has never been applied by the Veneer field manager.
+func needsOwnershipMigration(existing *karpenterv1alpha1.NodeOverlay) bool {
+	if existing.Labels[overlay.LabelManagedBy] != overlay.LabelManagedByValue {
+		return false
+	}
+	for _, entry := range existing.ManagedFields {
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return false
+		}
+	}
+	return true
+}
+
+// appliedSpec returns the part of existing's spec an apply of desired covers: the fields set
+// on desired, and the fields the Veneer field manager owns, which the apply removes if desired
+// no longer sets them. Fields owned only by other managers are left out, since the apply
+// leaves them alone. Nested objects such as capacity are narrowed per key; lists and scalars
+// are kept whole.
+func appliedSpec(existing, desired *karpenterv1alpha1.NodeOverlay) (karpenterv1alpha1.NodeOverlaySpec, error) {
+	var spec karpenterv1alpha1.NodeOverlaySpec
+
+	existingContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&existing.Spec)
+	if err != nil {
+		return spec, fmt.Errorf("failed to convert NodeOverlay %s spec: %w", existing.Name, err)
+	}
+	desiredContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&desired.Spec)
+	if err != nil {
+		return spec, fmt.Errorf("failed to convert NodeOverlay %s spec: %w", desired.Name, err)
+	}
+
+	content := selectFields(existingContent, desiredContent, ownedSpecFields(existing))
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &spec); err != nil {
+		return spec, fmt.Errorf("failed to convert NodeOverlay %s spec: %w", existing.Name, err)
+	}
+	return spec, nil
+}
+
+// ownedSpecFields returns the spec fields the Veneer field manager applied on an overlay,
+// as a managedFields field set (keys such as "f:price"), or nil if it applied none.
+func ownedSpecFields(existing *karpenterv1alpha1.NodeOverlay) map[string]interface{} {
+	for _, entry := range existing.ManagedFields {
+		if entry.Manager != FieldManager || entry.Operation != metav1.ManagedFieldsOperationApply ||
+			entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil
+		}
+		spec, _ := fields["f:spec"].(map[string]interface{})
+		return spec
+	}
+	return nil
+}
+
+// selectFields returns the fields of content that are set in desired or listed in the
+// managedFields field set owned, recursing into nested objects.
+func selectFields(content, desired, owned map[string]interface{}) map[string]interface{} {
+	selected := map[string]interface{}{}
+	for key, value := range content {
+		desiredValue, inDesired := desired[key]
+		ownedValue, inOwned := owned["f:"+key]
+		if !inDesired && !inOwned {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			desiredNested, _ := desiredValue.(map[string]interface{})
+			ownedNested, _ := ownedValue.(map[string]interface{})
+			value = selectFields(nested, desiredNested, ownedNested)
+		}
+		selected[key] = value
+	}
+	return selected
+}
+
+// forceConflicts reports whether applies should take ownership of conflicting fields.
+func forceConflicts(provider config.Provider) bool {
+	cfg := currentConfig(provider)
+	return cfg != nil && cfg.Overlays.ForceConflicts
+}
+
+// preferencesEnabled reports whether preference processing is enabled in the current
+// configuration. Preferences are enabled when there is no configuration.
+func preferencesEnabled(provider config.Provider) bool {
+	cfg := currentConfig(provider)
+	return cfg == nil || cfg.Preferences.Enabled
+}
+
+// currentConfig returns the configuration currently supplied by provider, or nil when
+// there is none.
+func currentConfig(provider config.Provider) *config.Config {
+	if provider == nil {
+		return nil
+	}
+	return provider.Current()
+}
+
+// applyErrorType classifies a failed apply for the overlay operation error metric.
+func applyErrorType(err error) metrics.ErrorType {
+	if errors.IsConflict(err) {
+		return metrics.ErrorTypeConflict
+	}
+	return metrics.ErrorTypeAPI
+}
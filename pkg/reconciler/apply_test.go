@@ -0,0 +1,224 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/overlay"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// testVeneerOverlay returns a NodeOverlay as Veneer would generate it.
+func testVeneerOverlay(price string, weight int32) *karpenterv1alpha1.NodeOverlay {
+	return &karpenterv1alpha1.NodeOverlay{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cost-aware-ri-m5.xlarge-us-west-2",
+			Labels: map[string]string{overlay.LabelManagedBy: overlay.LabelManagedByValue},
+		},
+		Spec: karpenterv1alpha1.NodeOverlaySpec{
+			Price:  strPtr(price),
+			Weight: int32Ptr(weight),
+		},
+	}
+}
+
+// applyAs server-side applies the given NodeOverlay fields as another field manager.
+// The overlay defaults to the one testVeneerOverlay returns unless fields name another.
+func applyAs(t *testing.T, c client.Client, manager string, fields map[string]interface{}, force bool) error {
+	t.Helper()
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetGroupVersionKind(nodeOverlayGVK)
+	if obj.GetName() == "" {
+		obj.SetName("cost-aware-ri-m5.xlarge-us-west-2")
+	}
+
+	opts := []client.ApplyOption{client.FieldOwner(manager)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	return c.Apply(context.Background(), client.ApplyConfigurationFromUnstructured(obj), opts...)
+}
+
+func getTestOverlay(t *testing.T, c client.Client) *karpenterv1alpha1.NodeOverlay {
+	t.Helper()
+	got := &karpenterv1alpha1.NodeOverlay{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "cost-aware-ri-m5.xlarge-us-west-2"}, got); err != nil {
+		t.Fatalf("failed to get overlay: %v", err)
+	}
+	return got
+}
+
+func TestApplyOverlay_PreservesForeignFields(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithReturnManagedFields().Build()
+
+	created := testVeneerOverlay("0.10", 30)
+	if err := applyOverlay(ctx, c, created, nil, false); err != nil {
+		t.Fatalf("initial apply failed: %v", err)
+	}
+	if created.ResourceVersion == "" {
+		t.Error("expected resourceVersion from the applied object")
+	}
+
+	// A GitOps tool adds its own label and annotation to the same overlay
+	err := applyAs(t, c, "gitops", map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{"team": "platform"},
+			"annotations": map[string]interface{}{"gitops.example.com/sync": "true"},
+		},
+	}, false)
+	if err != nil {
+		t.Fatalf("gitops apply failed: %v", err)
+	}
+
+	existing := getTestOverlay(t, c)
+	if err := applyOverlay(ctx, c, testVeneerOverlay("0.08", 30), existing, false); err != nil {
+		t.Fatalf("update apply failed: %v", err)
+	}
+
+	got := getTestOverlay(t, c)
+	if got.Spec.Price == nil || *got.Spec.Price != "0.08" {
+		t.Errorf("price = %v, want 0.08", got.Spec.Price)
+	}
+	if got.Labels["team"] != "platform" {
+		t.Errorf("foreign label was not preserved: %v", got.Labels)
+	}
+	if got.Annotations["gitops.example.com/sync"] != "true" {
+		t.Errorf("foreign annotation was not preserved: %v", got.Annotations)
+	}
+	if got.Labels[overlay.LabelManagedBy] != overlay.LabelManagedByValue {
+		t.Errorf("managed-by label missing: %v", got.Labels)
+	}
+}
+
+func TestApplyOverlay_Conflict(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithReturnManagedFields().Build()
+
+	if err := applyOverlay(ctx, c, testVeneerOverlay("0.10", 30), nil, false); err != nil {
+		t.Fatalf("initial apply failed: %v", err)
+	}
+
+	// An admin takes ownership of the weight
+	err := applyAs(t, c, "admin", map[string]interface{}{
+		"spec": map[string]interface{}{"weight": int64(50)},
+	}, true)
+	if err != nil {
+		t.Fatalf("admin apply failed: %v", err)
+	}
+
+	// Without force, the conflicting apply is rejected and the admin's value stays
+	existing := getTestOverlay(t, c)
+	err = applyOverlay(ctx, c, testVeneerOverlay("0.08", 30), existing, false)
+	if !errors.IsConflict(err) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if got := applyErrorType(err); got != metrics.ErrorTypeConflict {
+		t.Errorf("applyErrorType() = %q, want %q", got, metrics.ErrorTypeConflict)
+	}
+	got := getTestOverlay(t, c)
+	if *got.Spec.Weight != 50 || *got.Spec.Price != "0.10" {
+		t.Errorf("overlay changed despite conflict: weight=%d price=%s", *got.Spec.Weight, *got.Spec.Price)
+	}
+
+	// With force, Veneer takes the field back
+	if err := applyOverlay(ctx, c, testVeneerOverlay("0.08", 30), got, true); err != nil {
+		t.Fatalf("forced apply failed: %v", err)
+	}
+	got = getTestOverlay(t, c)
+	if *got.Spec.Weight != 30 || *got.Spec.Price != "0.08" {
+		t.Errorf("forced apply not applied: weight=%d price=%s", *got.Spec.Weight, *got.Spec.Price)
+	}
+}
+
+func TestApplyOverlay_MigratesLegacyOverlay(t *testing.T) {
+	ctx := context.Background()
+
+	// Overlay written by a Veneer version that used Create/Update
+	legacy := testVeneerOverlay("0.10", 30)
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithReturnManagedFields().
+		WithObjects(legacy).
+		Build()
+
+	existing := getTestOverlay(t, c)
+	if !needsOwnershipMigration(existing) {
+		t.Fatal("expected legacy overlay to need ownership migration")
+	}
+	if err := applyOverlay(ctx, c, testVeneerOverlay("0.08", 30), existing, false); err != nil {
+		t.Fatalf("apply to legacy overlay failed: %v", err)
+	}
+
+	got := getTestOverlay(t, c)
+	if *got.Spec.Price != "0.08" {
+		t.Errorf("price = %s, want 0.08", *got.Spec.Price)
+	}
+	if needsOwnershipMigration(got) {
+		t.Error("expected overlay to be owned by the veneer field manager after apply")
+	}
+
+	// Overlays not managed by Veneer are never forced
+	unmanaged := testVeneerOverlay("0.10", 30)
+	unmanaged.Labels = nil
+	if needsOwnershipMigration(unmanaged) {
+		t.Error("expected overlay without the managed-by label to be left alone")
+	}
+}
+
+func TestOverlayNeedsUpdate_AppliedFields(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithReturnManagedFields().Build()
+
+	if err := applyOverlay(ctx, c, testVeneerOverlay("0.10", 30), nil, false); err != nil {
+		t.Fatalf("initial apply failed: %v", err)
+	}
+
+	// An admin sets a field Veneer never sets
+	err := applyAs(t, c, "admin", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"capacity": map[string]interface{}{"example.com/fpga": "1"},
+		},
+	}, false)
+	if err != nil {
+		t.Fatalf("admin apply failed: %v", err)
+	}
+	existing := getTestOverlay(t, c)
+
+	if overlayNeedsUpdate(existing, testVeneerOverlay("0.10", 30)) {
+		t.Error("expected a field owned by another manager not to need an update")
+	}
+	if !overlayNeedsUpdate(existing, testVeneerOverlay("0.08", 30)) {
+		t.Error("expected a changed price to need an update")
+	}
+
+	// Veneer owns the price, so no longer setting it removes it
+	withoutPrice := testVeneerOverlay("0.10", 30)
+	withoutPrice.Spec.Price = nil
+	if !overlayNeedsUpdate(existing, withoutPrice) {
+		t.Error("expected a price Veneer no longer sets to need an update")
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/preference"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// clusterPreferencesRequest is the single reconcile request for all cluster-wide preferences.
+// Every cluster preference may select any NodePool, so they are always reconciled together.
+var clusterPreferencesRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster-preferences"}}
+
+// ClusterPreferenceReconciler generates preference-based NodeOverlays from the cluster-wide
+// preferences in the preferences.clusterPreferences config section.
+//
+// Each cluster preference selects NodePools by label selector, and Veneer generates a single
+// NodeOverlay for it whose karpenter.sh/nodepool requirement lists every matching NodePool.
+// Overlays are re-synced at startup, whenever NodePools are created, deleted, or relabeled,
+// and when the configuration is reloaded.
+type ClusterPreferenceReconciler struct {
+	// Client is the Kubernetes client for managing resources
+	client.Client
+
+	// Logger is the structured logger for this reconciler
+	Logger logr.Logger
+
+	// Generator creates NodeOverlay specs from preferences
+	Generator *preference.Generator
+
+	// Metrics holds the Prometheus metrics for recording reconciler behavior
+	Metrics *metrics.Metrics
+
+	// Config supplies the controller configuration holding the cluster-wide preferences.
+	// Optional: no cluster preferences are applied when nil.
+	Config config.Provider
+
+	// ConfigReloaded receives an event for every applied config reload, so edited cluster
+	// preferences take effect without waiting for a NodePool change.
+	// Optional: reloads aren't watched when nil.
+	ConfigReloaded <-chan event.TypedGenericEvent[*config.Config]
+
+	// Recorder emits Kubernetes Events on the overlays this reconciler writes.
+	// Optional: no events are recorded when nil.
+	Recorder events.EventRecorder
+}
+
+// Reconcile syncs the overlays of every cluster-wide preference with the current NodePools.
+//
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodeoverlays,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+func (r *ClusterPreferenceReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := r.Logger
+
+	var nodePoolList karpenterv1.NodePoolList
+	if err := r.List(ctx, &nodePoolList); err != nil {
+		log.Error(err, "Failed to list NodePools")
+		return ctrl.Result{}, err
+	}
+
+	// Generate desired overlays. Invalid preferences, and preferences selecting no NodePools,
+	// generate none, so overlays from a previous configuration are removed.
+	var desiredOverlays []*karpenterv1alpha1.NodeOverlay
+	for _, clusterPref := range r.clusterPreferences() {
+		overlay, err := r.desiredOverlay(clusterPref, nodePoolList.Items)
+		if err != nil {
+			log.Error(err, "Invalid cluster preference", "preference", clusterPref.Name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, metrics.ErrorTypeValidation)
+			}
+			continue
+		}
+		if overlay == nil {
+			log.V(1).Info("Cluster preference selects no NodePools", "preference", clusterPref.Name)
+			continue
+		}
+		desiredOverlays = append(desiredOverlays, overlay)
+	}
+
+	var overlayList karpenterv1alpha1.NodeOverlayList
+	if err := r.List(ctx, &overlayList,
+		client.MatchingLabels{
+			preference.LabelManagedBy:      preference.LabelManagedByValue,
+			preference.LabelPreferenceType: preference.LabelPreferenceTypeValue,
+		},
+		client.HasLabels{preference.LabelSourceClusterPreference},
+	); err != nil {
+		log.Error(err, "Failed to list existing cluster preference overlays")
+		return ctrl.Result{}, err
+	}
+
+	r.reconcileOverlays(ctx, log, desiredOverlays, overlayList.Items)
+
+	return ctrl.Result{}, nil
+}
+
+// clusterPreferences returns the configured cluster-wide preferences, or none when
+// preferences are disabled, so their overlays are removed.
+func (r *ClusterPreferenceReconciler) clusterPreferences() []config.ClusterPreference {
+	cfg := currentConfig(r.Config)
+	if cfg == nil || !cfg.Preferences.Enabled {
+		return nil
+	}
+	return cfg.Preferences.ClusterPreferences
+}
+
+// desiredOverlay parses a cluster preference and generates its overlay, scoped to the sorted
+// names of the NodePools its selector matches. Returns nil if no NodePool matches.
+func (r *ClusterPreferenceReconciler) desiredOverlay(
+	clusterPref config.ClusterPreference, nodePools []karpenterv1.NodePool,
+) (*karpenterv1alpha1.NodeOverlay, error) {
+	selector, err := labels.Parse(clusterPref.NodePoolSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nodePoolSelector: %w", err)
+	}
+
+	var prefsConfig config.PreferencesConfig
+	if cfg := currentConfig(r.Config); cfg != nil {
+		prefsConfig = cfg.Preferences
+	}
+	pref, err := preference.ParsePreference(clusterPref.Preference, preference.NewLabelSet(prefsConfig.SupportedLabels))
+	if err != nil {
+		return nil, err
+	}
+	minAdjustment, maxAdjustment := prefsConfig.AdjustmentRange()
+	if err := pref.CheckAdjustment(minAdjustment, maxAdjustment, prefsConfig.AbsoluteAdjustmentLimit()); err != nil {
+		return nil, err
+	}
+
+	var nodePoolNames []string
+	for i := range nodePools {
+		if selector.Matches(labels.Set(nodePools[i].Labels)) && !preference.PreferencesDisabled(nodePools[i].Annotations) {
+			nodePoolNames = append(nodePoolNames, nodePools[i].Name)
+		}
+	}
+	if len(nodePoolNames) == 0 || r.Generator == nil {
+		return nil, nil
+	}
+	sort.Strings(nodePoolNames)
+
+	overlay := r.Generator.GenerateForNodePools(clusterPref.Name, *pref, nodePoolNames, clusterPref.OverlayWeight())
+	if err := preference.ValidateOverlay(overlay); err != nil {
+		return nil, err
+	}
+	return overlay, nil
+}
+
+// overlayNodePools returns the NodePools an overlay is scoped to by its karpenter.sh/nodepool requirement.
+func overlayNodePools(overlay *karpenterv1alpha1.NodeOverlay) []string {
+	for _, req := range overlay.Spec.Requirements {
+		if req.Key == preference.LabelNodePool {
+			return req.Values
+		}
+	}
+	return nil
+}
+
+// reconcileOverlays compares desired vs existing overlays and performs CRUD operations.
+func (r *ClusterPreferenceReconciler) reconcileOverlays(
+	ctx context.Context,
+	log logr.Logger,
+	desired []*karpenterv1alpha1.NodeOverlay,
+	existing []karpenterv1alpha1.NodeOverlay,
+) {
+	existingByName := make(map[string]*karpenterv1alpha1.NodeOverlay)
+	for i := range existing {
+		existingByName[existing[i].Name] = &existing[i]
+	}
+
+	desiredByName := make(map[string]*karpenterv1alpha1.NodeOverlay)
+	for _, overlay := range desired {
+		desiredByName[overlay.Name] = overlay
+	}
+
+	var createCount, updateCount, deleteCount, errorCount int
+
+	// Create or update desired overlays
+	for name, desiredOverlay := range desiredByName {
+		existingOverlay, exists := existingByName[name]
+		clusterPrefName := desiredOverlay.Labels[preference.LabelSourceClusterPreference]
+		nodePoolCount := len(overlayNodePools(desiredOverlay))
+
+		if !exists {
+			if err := applyOverlay(ctx, r.Client, desiredOverlay, nil, forceConflicts(r.Config)); err != nil {
+				log.Error(err, "Failed to create cluster preference overlay", "overlay", name)
+				if r.Metrics != nil {
+					r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, applyErrorType(err))
+				}
+				errorCount++
+				continue
+			}
+			log.Info("Created cluster preference overlay", "overlay", name, "nodepools", nodePoolCount)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperation(metrics.OperationCreate, metrics.CapacityTypePreference)
+			}
+			recordEvent(r.Recorder, desiredOverlay, corev1.EventTypeNormal, EventReasonOverlayCreated, eventActionCreate,
+				"Created from cluster preference %s", clusterPrefName)
+			createCount++
+			continue
+		}
+
+		if !overlayNeedsUpdate(existingOverlay, desiredOverlay) {
+			log.V(2).Info("Cluster preference overlay already up to date", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperation(metrics.OperationUnchanged, metrics.CapacityTypePreference)
+			}
+			continue
+		}
+
+		if err := applyOverlay(ctx, r.Client, desiredOverlay, existingOverlay, forceConflicts(r.Config)); err != nil {
+			log.Error(err, "Failed to update cluster preference overlay", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperationError(metrics.OperationUpdate, applyErrorType(err))
+			}
+			recordEvent(r.Recorder, existingOverlay, corev1.EventTypeWarning, EventReasonOverlayUpdateFailed,
+				eventActionUpdate, "%s", err.Error())
+			errorCount++
+			continue
+		}
+		log.Info("Updated cluster preference overlay", "overlay", name, "nodepools", nodePoolCount)
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperation(metrics.OperationUpdate, metrics.CapacityTypePreference)
+		}
+		recordEvent(r.Recorder, desiredOverlay, corev1.EventTypeNormal, EventReasonOverlayUpdated, eventActionUpdate,
+			"Updated from cluster preference %s", clusterPrefName)
+		updateCount++
+	}
+
+	// Delete stale overlays: preferences removed from the config, invalid, or selecting no NodePools
+	for name, existingOverlay := range existingByName {
+		if _, desired := desiredByName[name]; desired {
+			continue
+		}
+		if err := r.Delete(ctx, existingOverlay); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete stale cluster preference overlay", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperationError(metrics.OperationDelete, metrics.ErrorTypeAPI)
+			}
+			errorCount++
+			continue
+		}
+		log.Info("Deleted stale cluster preference overlay", "overlay", name)
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperation(metrics.OperationDelete, metrics.CapacityTypePreference)
+		}
+		recordEvent(r.Recorder, existingOverlay, corev1.EventTypeNormal, EventReasonOverlayDeleted, eventActionDelete,
+			"Cluster preference %s removed, invalid, selecting no NodePools, or disabled",
+			existingOverlay.Labels[preference.LabelSourceClusterPreference])
+		deleteCount++
+	}
+
+	if createCount > 0 || updateCount > 0 || deleteCount > 0 || errorCount > 0 {
+		log.Info("Cluster preference overlay reconciliation complete",
+			"created", createCount,
+			"updated", updateCount,
+			"deleted", deleteCount,
+			"errors", errorCount,
+		)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+//
+// Cluster preferences are reconciled once at startup, on every config reload, and whenever a
+// NodePool is created, deleted, relabeled, or opted out of preferences; other NodePool
+// updates can't change which NodePools match.
+func (r *ClusterPreferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueue := func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{clusterPreferencesRequest}
+	}
+	startup := source.Func(func(_ context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		queue.Add(clusterPreferencesRequest)
+		return nil
+	})
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("clusterpreference").
+		Watches(&karpenterv1.NodePool{},
+			handler.EnqueueRequestsFromMapFunc(enqueue),
+			builder.WithPredicates(nodePoolSelectionChanged)).
+		WatchesRawSource(startup)
+	if r.ConfigReloaded != nil {
+		b = b.WatchesRawSource(source.Channel(r.ConfigReloaded,
+			handler.TypedEnqueueRequestsFromMapFunc(func(context.Context, *config.Config) []reconcile.Request {
+				return []reconcile.Request{clusterPreferencesRequest}
+			})))
+	}
+	return b.Complete(r)
+}
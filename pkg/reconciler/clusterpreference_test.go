@@ -0,0 +1,229 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/preference"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClusterPreferenceReconciler(c client.Client, prefs ...config.ClusterPreference) *ClusterPreferenceReconciler {
+	return &ClusterPreferenceReconciler{
+		Client:    c,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+		Config: &config.Config{Preferences: config.PreferencesConfig{
+			Enabled:            true,
+			ClusterPreferences: prefs,
+		}},
+	}
+}
+
+func reconcileClusterPreferences(t *testing.T, r *ClusterPreferenceReconciler) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+var testClusterPreference = config.ClusterPreference{
+	Name:             "prefer-graviton",
+	NodePoolSelector: "team=platform",
+	Preference:       "karpenter.k8s.aws/instance-family=m7g,c7g kubernetes.io/arch=arm64 adjust=-20%",
+	Weight:           5,
+}
+
+func TestClusterPreferenceReconciler_Reconcile_OneOverlayForAllNodePools(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(
+			testLabeledNodePool("platform-b", map[string]string{"team": "platform"}),
+			testLabeledNodePool("platform-a", map[string]string{"team": "platform"}),
+			testLabeledNodePool("batch", map[string]string{"team": "data"}),
+		).
+		Build()
+
+	reconcileClusterPreferences(t, newClusterPreferenceReconciler(c, testClusterPreference))
+
+	overlays := listOverlaysByName(t, c)
+	if len(overlays) != 1 {
+		t.Fatalf("expected 1 overlay, got %d: %v", len(overlays), overlays)
+	}
+	overlay, ok := overlays["cluster-pref-prefer-graviton"]
+	if !ok {
+		t.Fatalf("expected overlay cluster-pref-prefer-graviton, got %v", overlays)
+	}
+
+	if got := overlayNodePools(overlay); !reflect.DeepEqual(got, []string{"platform-a", "platform-b"}) {
+		t.Errorf("expected overlay scoped to [platform-a platform-b], got %v", got)
+	}
+	if len(overlay.Spec.Requirements) != 3 {
+		t.Errorf("expected nodepool requirement plus 2 matchers, got %v", overlay.Spec.Requirements)
+	}
+	if *overlay.Spec.Weight != 5 {
+		t.Errorf("expected weight 5, got %d", *overlay.Spec.Weight)
+	}
+	if *overlay.Spec.PriceAdjustment != "-20%" {
+		t.Errorf("expected price adjustment -20%%, got %s", *overlay.Spec.PriceAdjustment)
+	}
+	if overlay.Labels[preference.LabelSourceClusterPreference] != "prefer-graviton" {
+		t.Errorf("expected source-cluster-preference label, got %v", overlay.Labels)
+	}
+}
+
+func TestClusterPreferenceReconciler_Reconcile_ResyncsOnNodePoolChanges(t *testing.T) {
+	platformA := testLabeledNodePool("platform-a", map[string]string{"team": "platform"})
+	batch := testLabeledNodePool("batch", map[string]string{"team": "data"})
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(platformA, batch).
+		Build()
+	r := newClusterPreferenceReconciler(c, testClusterPreference)
+
+	reconcileClusterPreferences(t, r)
+
+	// A NodePool is relabeled into the selection and a new one is added
+	batch.Labels = map[string]string{"team": "platform"}
+	if err := c.Update(context.Background(), batch); err != nil {
+		t.Fatalf("failed to update NodePool: %v", err)
+	}
+	if err := c.Create(context.Background(),
+		testLabeledNodePool("platform-c", map[string]string{"team": "platform"})); err != nil {
+		t.Fatalf("failed to create NodePool: %v", err)
+	}
+	reconcileClusterPreferences(t, r)
+
+	overlay := listOverlaysByName(t, c)["cluster-pref-prefer-graviton"]
+	if overlay == nil {
+		t.Fatal("expected overlay cluster-pref-prefer-graviton to exist")
+	}
+	if got := overlayNodePools(overlay); !reflect.DeepEqual(got, []string{"batch", "platform-a", "platform-c"}) {
+		t.Errorf("expected overlay scoped to [batch platform-a platform-c], got %v", got)
+	}
+
+	// Once no NodePool matches, the overlay is removed
+	for _, name := range []string{"batch", "platform-a", "platform-c"} {
+		nodePool := testLabeledNodePool(name, nil)
+		if err := c.Delete(context.Background(), nodePool); err != nil {
+			t.Fatalf("failed to delete NodePool %s: %v", name, err)
+		}
+	}
+	reconcileClusterPreferences(t, r)
+
+	if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+		t.Errorf("expected overlay to be deleted, got %v", overlays)
+	}
+}
+
+func TestClusterPreferenceReconciler_Reconcile_RemovedAndInvalidPreferences(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(testLabeledNodePool("platform-a", map[string]string{"team": "platform"})).
+		Build()
+
+	spot := config.ClusterPreference{
+		Name:       "prefer-spot",
+		Preference: "karpenter.sh/capacity-type=spot adjust=-10%",
+	}
+	reconcileClusterPreferences(t, newClusterPreferenceReconciler(c, testClusterPreference, spot))
+	if overlays := listOverlaysByName(t, c); len(overlays) != 2 {
+		t.Fatalf("expected 2 overlays, got %v", overlays)
+	}
+
+	// prefer-graviton is removed from the config, and prefer-spot becomes invalid
+	spot.Preference = "example.com/team=platform adjust=-10%"
+	reconcileClusterPreferences(t, newClusterPreferenceReconciler(c, spot))
+
+	if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+		t.Errorf("expected overlays to be deleted, got %v", overlays)
+	}
+}
+
+func TestClusterPreferenceReconciler_Reconcile_SkipsInvalidOverlays(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(testLabeledNodePool("platform-a", map[string]string{"team": "platform"})).
+		Build()
+
+	// The name is a valid object name suffix, but too long for the source label value
+	longName := config.ClusterPreference{
+		Name:       strings.Repeat("a", 64),
+		Preference: "karpenter.sh/capacity-type=spot adjust=-10%",
+	}
+	reconcileClusterPreferences(t, newClusterPreferenceReconciler(c, testClusterPreference, longName))
+
+	overlays := listOverlaysByName(t, c)
+	if len(overlays) != 1 {
+		t.Fatalf("expected only the valid preference's overlay, got %v", overlays)
+	}
+	if _, ok := overlays["cluster-pref-prefer-graviton"]; !ok {
+		t.Errorf("expected overlay cluster-pref-prefer-graviton, got %v", overlays)
+	}
+}
+
+func TestClusterPreferenceReconciler_Reconcile_LeavesOtherPreferenceOverlays(t *testing.T) {
+	nodePool := testLabeledNodePool("platform-a", map[string]string{"team": "platform"})
+	nodePool.Annotations = map[string]string{
+		"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-10%",
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(nodePool).
+		Build()
+
+	nodePoolReconciler := &NodePoolReconciler{Client: c, Logger: logr.Discard(), Generator: preference.NewGenerator()}
+	if _, err := nodePoolReconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKeyFromObject(nodePool),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// No cluster preferences configured: only cluster preference overlays are considered stale
+	reconcileClusterPreferences(t, newClusterPreferenceReconciler(c))
+
+	if _, ok := listOverlaysByName(t, c)["pref-platform-a-1"]; !ok {
+		t.Error("expected annotation preference overlay to be left alone")
+	}
+}
+
+func TestClusterPreferenceReconciler_Reconcile_SkipsOptedOutNodePools(t *testing.T) {
+	optedOut := testLabeledNodePool("platform-b", map[string]string{"team": "platform"})
+	optedOut.Annotations = map[string]string{preference.AnnotationPreferencesDisabled: "true"}
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(testLabeledNodePool("platform-a", map[string]string{"team": "platform"}), optedOut).
+		Build()
+
+	reconcileClusterPreferences(t, newClusterPreferenceReconciler(c, testClusterPreference))
+
+	overlay := listOverlaysByName(t, c)["cluster-pref-prefer-graviton"]
+	if overlay == nil {
+		t.Fatal("expected overlay cluster-pref-prefer-graviton to exist")
+	}
+	if got := overlayNodePools(overlay); !reflect.DeepEqual(got, []string{"platform-a"}) {
+		t.Errorf("expected overlay scoped to [platform-a], got %v", got)
+	}
+}
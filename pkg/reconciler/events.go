@@ -0,0 +1,91 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nextdoor/veneer/pkg/preference"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// EventRecorderName is the controller name Veneer reports Kubernetes Events as.
+const EventRecorderName = "veneer"
+
+// Event reasons recorded on NodeOverlays.
+const (
+	// EventReasonOverlayCreated is recorded when Veneer creates a NodeOverlay.
+	EventReasonOverlayCreated = "OverlayCreated"
+
+	// EventReasonOverlayUpdated is recorded when Veneer changes an existing NodeOverlay.
+	EventReasonOverlayUpdated = "OverlayUpdated"
+
+	// EventReasonOverlayDeleted is recorded when Veneer deletes a NodeOverlay.
+	EventReasonOverlayDeleted = "OverlayDeleted"
+
+	// EventReasonOverlayUpdateFailed is recorded when writing an existing NodeOverlay fails.
+	EventReasonOverlayUpdateFailed = "OverlayUpdateFailed"
+)
+
+// Event reasons recorded on NodePools.
+const (
+	// EventReasonInvalidPreference is recorded for each preference annotation that fails to parse.
+	EventReasonInvalidPreference = "InvalidPreference"
+
+	// EventReasonPreferencesSynced is recorded when preference overlays were changed without errors.
+	EventReasonPreferencesSynced = "PreferencesSynced"
+
+	// EventReasonPreferencesSyncFailed is recorded when some preference overlays could not be written.
+	EventReasonPreferencesSyncFailed = "PreferencesSyncFailed"
+)
+
+// Event actions, describing what Veneer did when the event was recorded.
+const (
+	eventActionCreate = "Create"
+	eventActionUpdate = "Update"
+	eventActionDelete = "Delete"
+	eventActionParse  = "Parse"
+	eventActionSync   = "Sync"
+)
+
+// recordEvent emits a Kubernetes Event regarding obj. It is a no-op when recorder is nil,
+// so reconcilers work without an EventRecorder (e.g., in tests).
+func recordEvent(
+	recorder events.EventRecorder,
+	obj runtime.Object,
+	eventtype, reason, action, note string,
+	args ...interface{},
+) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, nil, eventtype, reason, action, note, args...)
+}
+
+// preferenceErrorNote formats a preference parse error for a NodePool event,
+// e.g. "preference.3 invalid: unsupported label key ...".
+func preferenceErrorNote(err error) string {
+	var parseErr preference.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Sprintf("preference.%s invalid: %s",
+			strings.TrimPrefix(parseErr.AnnotationKey, preference.AnnotationPrefix), parseErr.Message)
+	}
+	return err.Error()
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nextdoor/veneer/pkg/overlay"
+	corev1 "k8s.io/api/core/v1"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+// EKS managed node group capacity type label, for on-demand Nodes not launched by Karpenter.
+const (
+	eksCapacityTypeLabelKey = "eks.amazonaws.com/capacityType"
+	eksCapacityTypeOnDemand = "ON_DEMAND"
+)
+
+// pendingLaunches summarizes on-demand capacity Karpenter launched after Lumina last
+// collected data. Lumina's remaining capacity and RI counts don't include these instances
+// yet, so without them Veneer keeps advertising capacity that is already being used.
+//
+// Each launch counts against every Savings Plan that could cover it (its family's EC2
+// Instance SP and Compute SPs). AWS applies only one of them, so this overestimates
+// usage and withdraws overlays early rather than late.
+type pendingLaunches struct {
+	// costByFamily maps "family:region" to the on-demand price of launches in $/hour
+	costByFamily map[string]float64
+
+	// totalCost is the on-demand price of all launches in $/hour
+	totalCost float64
+
+	// unpriced counts launches with no known on-demand price, which are left out of the costs
+	unpriced int
+}
+
+// queryPendingLaunches lists on-demand NodeClaims created after since and estimates their
+// cost from on-demand prices (keyed by "instanceType:region", see pricingData).
+//
+// NodeClaims without an instance type label haven't been launched yet and are skipped.
+// Launches with no known on-demand price can't be costed; they are counted in unpriced.
+// List failures are logged and treated as no pending launches.
+func (r *MetricsReconciler) queryPendingLaunches(
+	ctx context.Context,
+	since time.Time,
+	onDemandPrices map[string]float64,
+) pendingLaunches {
+	pending := pendingLaunches{
+		costByFamily: map[string]float64{},
+	}
+	if r.Client == nil {
+		return pending
+	}
+
+	nodeClaims := &karpenterv1.NodeClaimList{}
+	if err := r.Client.List(ctx, nodeClaims); err != nil {
+		r.Logger.Error(err, "Failed to list NodeClaims, ignoring launches since last Lumina refresh")
+		return pending
+	}
+
+	for _, nc := range nodeClaims.Items {
+		if !nc.CreationTimestamp.After(since) || nc.DeletionTimestamp != nil {
+			continue
+		}
+		if nc.Labels[karpenterv1.CapacityTypeLabelKey] != karpenterv1.CapacityTypeOnDemand {
+			continue
+		}
+		key, ok := r.instanceTypeRegionKey(nc.Labels)
+		if !ok {
+			continue
+		}
+
+		price, exists := onDemandPrices[key]
+		if !exists {
+			pending.unpriced++
+			continue
+		}
+		instanceType, region, _ := strings.Cut(key, ":")
+		family, _, _ := strings.Cut(instanceType, ".")
+		pending.costByFamily[family+":"+region] += price
+		pending.totalCost += price
+	}
+
+	return pending
+}
+
+// reservedInstanceUsage counts the on-demand instances that occupy Reserved Instances.
+// ByType maps are keyed by "instanceType:region" and byZone maps by
+// "instanceType:region:zone", matching the keys of AggregateReservedInstances.
+type reservedInstanceUsage struct {
+	// runningByType counts on-demand Nodes
+	runningByType map[string]int
+
+	// launchingByType counts on-demand NodeClaims that haven't registered a Node yet
+	launchingByType map[string]int
+
+	// runningByZone counts on-demand Nodes with a known zone
+	runningByZone map[string]int
+
+	// launchingByZone counts launching on-demand NodeClaims with a known zone
+	launchingByZone map[string]int
+}
+
+// queryReservedInstanceUsage counts on-demand instances in the cluster by instance type.
+//
+// Nodes count as on-demand when Karpenter's capacity type label says so, or for EKS managed
+// node groups, when eks.amazonaws.com/capacityType is ON_DEMAND. Nodes with neither label
+// may be spot and are skipped. NodeClaims are counted only until their Node registers, so
+// no instance is counted twice. Instances outside this cluster aren't visible, so RIs they
+// occupy still look available.
+func (r *MetricsReconciler) queryReservedInstanceUsage(ctx context.Context) (reservedInstanceUsage, error) {
+	usage := reservedInstanceUsage{
+		runningByType:   map[string]int{},
+		launchingByType: map[string]int{},
+		runningByZone:   map[string]int{},
+		launchingByZone: map[string]int{},
+	}
+	if r.Client == nil {
+		return usage, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodes); err != nil {
+		return usage, fmt.Errorf("failed to list Nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		if node.Labels[karpenterv1.CapacityTypeLabelKey] != karpenterv1.CapacityTypeOnDemand &&
+			node.Labels[eksCapacityTypeLabelKey] != eksCapacityTypeOnDemand {
+			continue
+		}
+		if key, ok := r.instanceTypeRegionKey(node.Labels); ok {
+			usage.runningByType[key]++
+			if zone := node.Labels[corev1.LabelTopologyZone]; zone != "" {
+				usage.runningByZone[key+":"+zone]++
+			}
+		}
+	}
+
+	nodeClaims := &karpenterv1.NodeClaimList{}
+	if err := r.Client.List(ctx, nodeClaims); err != nil {
+		return usage, fmt.Errorf("failed to list NodeClaims: %w", err)
+	}
+	for _, nc := range nodeClaims.Items {
+		if nc.Status.NodeName != "" || nc.DeletionTimestamp != nil {
+			continue
+		}
+		if nc.Labels[karpenterv1.CapacityTypeLabelKey] != karpenterv1.CapacityTypeOnDemand {
+			continue
+		}
+		if key, ok := r.instanceTypeRegionKey(nc.Labels); ok {
+			usage.launchingByType[key]++
+			if zone := nc.Labels[corev1.LabelTopologyZone]; zone != "" {
+				usage.launchingByZone[key+":"+zone]++
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// assignReservedInstanceUsage sets RunningCount and PendingLaunchCount on each aggregated RI.
+//
+// AWS applies zonal RIs to matching instances in their AZ before regional RIs, so instances
+// occupying a zonal RI are removed from the counts the region-wide RI of that type sees.
+// Running instances take RIs before launching ones.
+func assignReservedInstanceUsage(aggs map[string]overlay.AggregatedReservedInstance, usage reservedInstanceUsage) {
+	for key, agg := range aggs {
+		if agg.AvailabilityZone == "" {
+			continue
+		}
+		agg.RunningCount = usage.runningByZone[key]
+		agg.PendingLaunchCount = usage.launchingByZone[key]
+		aggs[key] = agg
+	}
+
+	zonalRunning, zonalLaunching := zonalReservedInstanceOccupancy(aggs)
+	for key, agg := range aggs {
+		if agg.AvailabilityZone != "" {
+			continue
+		}
+		agg.RunningCount = usage.runningByType[key] - zonalRunning[key]
+		agg.PendingLaunchCount = usage.launchingByType[key] - zonalLaunching[key]
+		aggs[key] = agg
+	}
+}
+
+// assignReservedInstanceFamilyUsage sets RunningUnits and PendingLaunchUnits on each
+// size-flexible RI family from the instances left over once zonal RIs are applied.
+// Call it after assignReservedInstanceUsage has counted usage of the zonal aggregates.
+func assignReservedInstanceFamilyUsage(
+	families map[string]overlay.AggregatedReservedInstanceFamily,
+	aggs map[string]overlay.AggregatedReservedInstance,
+	usage reservedInstanceUsage,
+) {
+	zonalRunning, zonalLaunching := zonalReservedInstanceOccupancy(aggs)
+
+	addUnits := func(counts, zonal map[string]int, add func(*overlay.AggregatedReservedInstanceFamily, float64)) {
+		for key, count := range counts {
+			instanceType, region, _ := strings.Cut(key, ":")
+			factor, known := overlay.NormalizationFactor(instanceType)
+			if !known {
+				continue
+			}
+			family, _, _ := strings.Cut(instanceType, ".")
+			familyKey := family + ":" + region
+			agg, exists := families[familyKey]
+			if !exists {
+				continue
+			}
+			add(&agg, float64(count-zonal[key])*factor)
+			families[familyKey] = agg
+		}
+	}
+	addUnits(usage.runningByType, zonalRunning, func(agg *overlay.AggregatedReservedInstanceFamily, units float64) {
+		agg.RunningUnits += units
+	})
+	addUnits(usage.launchingByType, zonalLaunching, func(agg *overlay.AggregatedReservedInstanceFamily, units float64) {
+		agg.PendingLaunchUnits += units
+	})
+}
+
+// zonalReservedInstanceOccupancy counts, per "instanceType:region", the running and launching
+// instances that zonal RIs absorb. Running instances take RIs before launching ones.
+func zonalReservedInstanceOccupancy(
+	aggs map[string]overlay.AggregatedReservedInstance,
+) (running, launching map[string]int) {
+	running = make(map[string]int)
+	launching = make(map[string]int)
+	for _, agg := range aggs {
+		if agg.AvailabilityZone == "" {
+			continue
+		}
+		regionKey := agg.InstanceType + ":" + agg.Region
+		occupied := min(agg.RunningCount, agg.TotalCount)
+		running[regionKey] += occupied
+		launching[regionKey] += min(agg.PendingLaunchCount, agg.TotalCount-occupied)
+	}
+	return running, launching
+}
+
+// instanceTypeRegionKey builds an "instanceType:region" key from Node or NodeClaim labels.
+// The region falls back to the configured AWS region when unlabeled. Returns false if
+// the instance type is unknown.
+func (r *MetricsReconciler) instanceTypeRegionKey(labels map[string]string) (string, bool) {
+	instanceType := labels[corev1.LabelInstanceTypeStable]
+	if instanceType == "" {
+		return "", false
+	}
+	region := labels[corev1.LabelTopologyRegion]
+	if cfg := currentConfig(r.Config); region == "" && cfg != nil {
+		region = cfg.AWS.Region
+	}
+	return instanceType + ":" + region, true
+}
@@ -0,0 +1,542 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/internal/testutil"
+	"github.com/nextdoor/veneer/pkg/config"
+	veneermetrics "github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/overlay"
+	"github.com/nextdoor/veneer/pkg/prometheus"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// testNodeClaim builds a NodeClaim created at the given time with the given labels.
+func testNodeClaim(name string, created time.Time, labels map[string]string) *karpenterv1.NodeClaim {
+	return &karpenterv1.NodeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            labels,
+		},
+	}
+}
+
+func TestMetricsReconciler_QueryPendingLaunches(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-10 * time.Minute)
+
+	onDemand := map[string]float64{
+		"m5.xlarge:us-west-2":  0.192,
+		"m5.2xlarge:us-west-2": 0.384,
+		"c5.large:us-east-1":   0.085,
+	}
+
+	objects := []client.Object{
+		// Counted: on-demand, launched after since
+		testNodeClaim("recent-m5-xlarge", now.Add(-1*time.Minute), map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+			"node.kubernetes.io/instance-type": "m5.xlarge",
+			"topology.kubernetes.io/region":    "us-west-2",
+		}),
+		// Counted: region falls back to the configured AWS region
+		testNodeClaim("recent-m5-2xlarge", now.Add(-2*time.Minute), map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+			"node.kubernetes.io/instance-type": "m5.2xlarge",
+		}),
+		// Counted as unpriced: no on-demand price known
+		testNodeClaim("recent-unpriced", now.Add(-3*time.Minute), map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+			"node.kubernetes.io/instance-type": "r5.large",
+			"topology.kubernetes.io/region":    "us-west-2",
+		}),
+		// Ignored: launched before Lumina collected its data
+		testNodeClaim("old-m5-xlarge", now.Add(-30*time.Minute), map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+			"node.kubernetes.io/instance-type": "m5.xlarge",
+			"topology.kubernetes.io/region":    "us-west-2",
+		}),
+		// Ignored: spot capacity doesn't consume commitments
+		testNodeClaim("recent-spot", now.Add(-1*time.Minute), map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeSpot,
+			"node.kubernetes.io/instance-type": "m5.xlarge",
+			"topology.kubernetes.io/region":    "us-west-2",
+		}),
+		// Ignored: not launched yet, so no instance type
+		testNodeClaim("recent-pending", now.Add(-1*time.Minute), map[string]string{
+			karpenterv1.CapacityTypeLabelKey: karpenterv1.CapacityTypeOnDemand,
+		}),
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithObjects(objects...).Build()
+	cfg := &config.Config{}
+	cfg.AWS.Region = "us-west-2"
+
+	reconciler := &MetricsReconciler{
+		Client: k8sClient,
+		Config: cfg,
+		Logger: logr.Discard(),
+	}
+
+	got := reconciler.queryPendingLaunches(context.Background(), since, onDemand)
+
+	if cost := got.costByFamily["m5:us-west-2"]; math.Abs(cost-0.576) > 1e-9 {
+		t.Errorf("costByFamily[m5:us-west-2] = %v, want 0.576", cost)
+	}
+	if len(got.costByFamily) != 1 {
+		t.Errorf("costByFamily = %v, want only m5:us-west-2", got.costByFamily)
+	}
+	if math.Abs(got.totalCost-0.576) > 1e-9 {
+		t.Errorf("totalCost = %v, want 0.576", got.totalCost)
+	}
+	if got.unpriced != 1 {
+		t.Errorf("unpriced = %d, want 1", got.unpriced)
+	}
+}
+
+func TestMetricsReconciler_SavingsPlanPendingLaunches(t *testing.T) {
+	launch := testNodeClaim("recent-m5-xlarge", time.Now().Add(-1*time.Minute), map[string]string{
+		karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+		"node.kubernetes.io/instance-type": "m5.xlarge",
+		"topology.kubernetes.io/region":    "us-west-2",
+	})
+
+	tests := []struct {
+		name         string
+		objects      []client.Object
+		onDemand     map[string]float64
+		lastOnDemand map[string]float64
+		wantOK       bool
+		wantCost     float64
+		wantUnpriced int
+	}{
+		{
+			name:     "current prices",
+			objects:  []client.Object{launch},
+			onDemand: map[string]float64{"m5.xlarge:us-west-2": 0.192},
+			wantOK:   true,
+			wantCost: 0.192,
+		},
+		{
+			name:         "stale prices fall back to the last known prices",
+			objects:      []client.Object{launch},
+			lastOnDemand: map[string]float64{"m5.xlarge:us-west-2": 0.2},
+			wantOK:       true,
+			wantCost:     0.2,
+		},
+		{
+			name:         "launch missing from known prices is left out",
+			objects:      []client.Object{launch},
+			onDemand:     map[string]float64{"c5.large:us-west-2": 0.085},
+			wantOK:       true,
+			wantUnpriced: 1,
+		},
+		{
+			name:         "no known prices skips analysis",
+			objects:      []client.Object{launch},
+			wantOK:       false,
+			wantUnpriced: 1,
+		},
+		{
+			name:   "no known prices and no launches",
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithObjects(tt.objects...).Build()
+			reconciler := &MetricsReconciler{
+				Client:             k8sClient,
+				Logger:             logr.Discard(),
+				Metrics:            veneermetrics.NewMetrics(promclient.NewRegistry()),
+				lastOnDemandPrices: tt.lastOnDemand,
+			}
+
+			got, ok := reconciler.savingsPlanPendingLaunches(context.Background(), 600, pricingData{onDemand: tt.onDemand})
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(got.totalCost-tt.wantCost) > 1e-9 {
+				t.Errorf("totalCost = %v, want %v", got.totalCost, tt.wantCost)
+			}
+			if got.unpriced != tt.wantUnpriced {
+				t.Errorf("unpriced = %d, want %d", got.unpriced, tt.wantUnpriced)
+			}
+			if metric := promtestutil.ToFloat64(reconciler.Metrics.SavingsPlanUnpricedLaunches); metric != float64(tt.wantUnpriced) {
+				t.Errorf("unpriced launches metric = %v, want %d", metric, tt.wantUnpriced)
+			}
+		})
+	}
+}
+
+func TestMetricsReconciler_QueryPendingLaunches_ListError(t *testing.T) {
+	// A scheme without NodeClaim makes List fail, e.g. when the CRD isn't installed
+	k8sClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+
+	reconciler := &MetricsReconciler{
+		Client: k8sClient,
+		Logger: logr.Discard(),
+	}
+
+	got := reconciler.queryPendingLaunches(context.Background(), time.Now().Add(-time.Hour), nil)
+	if len(got.costByFamily) != 0 || got.totalCost != 0 {
+		t.Errorf("queryPendingLaunches() = %+v, want no pending launches", got)
+	}
+}
+
+func TestMetricsReconciler_QueryReservedInstanceUsage(t *testing.T) {
+	onDemandLabels := func(instanceType string) map[string]string {
+		return map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+			"node.kubernetes.io/instance-type": instanceType,
+			"topology.kubernetes.io/region":    "us-west-2",
+		}
+	}
+	registered := testNodeClaim("registered", time.Now(), onDemandLabels("m5.xlarge"))
+	registered.Status.NodeName = "node-karpenter"
+
+	objects := []client.Object{
+		// Counted: Karpenter on-demand Node
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-karpenter", Labels: onDemandLabels("m5.xlarge")}},
+		// Counted: EKS managed node group on-demand Node
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-eks", Labels: map[string]string{
+			"eks.amazonaws.com/capacityType":   "ON_DEMAND",
+			"node.kubernetes.io/instance-type": "m5.xlarge",
+			"topology.kubernetes.io/region":    "us-west-2",
+		}}},
+		// Ignored: spot Node
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-spot", Labels: map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeSpot,
+			"node.kubernetes.io/instance-type": "m5.xlarge",
+			"topology.kubernetes.io/region":    "us-west-2",
+		}}},
+		// Ignored: NodeClaim whose Node is already counted
+		registered,
+		// Counted: NodeClaim still launching
+		testNodeClaim("launching", time.Now(), onDemandLabels("c5.large")),
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithObjects(objects...).Build()
+	reconciler := &MetricsReconciler{
+		Client: k8sClient,
+		Logger: logr.Discard(),
+	}
+
+	got, err := reconciler.queryReservedInstanceUsage(context.Background())
+	if err != nil {
+		t.Fatalf("queryReservedInstanceUsage() unexpected error: %v", err)
+	}
+
+	if want := map[string]int{"m5.xlarge:us-west-2": 2}; !reflect.DeepEqual(got.runningByType, want) {
+		t.Errorf("runningByType = %v, want %v", got.runningByType, want)
+	}
+	if want := map[string]int{"c5.large:us-west-2": 1}; !reflect.DeepEqual(got.launchingByType, want) {
+		t.Errorf("launchingByType = %v, want %v", got.launchingByType, want)
+	}
+}
+
+func TestMetricsReconciler_ReservedInstanceUsageWithdrawsOverlay(t *testing.T) {
+	// Lumina reports a single m5.xlarge RI in us-west-2
+	onDemandNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{
+		karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+		"node.kubernetes.io/instance-type": "m5.xlarge",
+		"topology.kubernetes.io/region":    "us-west-2",
+	}}}
+	spotNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{
+		karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeSpot,
+		"node.kubernetes.io/instance-type": "m5.xlarge",
+		"topology.kubernetes.io/region":    "us-west-2",
+	}}}
+	launching := testNodeClaim("burst-1", time.Now(), map[string]string{
+		karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+		"node.kubernetes.io/instance-type": "m5.xlarge",
+		"topology.kubernetes.io/region":    "us-west-2",
+	})
+
+	tests := []struct {
+		name        string
+		objects     []client.Object
+		wantOverlay bool
+	}{
+		{"no instances running", nil, true},
+		{"spot instance doesn't occupy the RI", []client.Object{spotNode}, true},
+		{"running on-demand instance occupies the RI", []client.Object{onDemandNode}, false},
+		{"launching on-demand instance occupies the RI", []client.Object{launching}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewMockPrometheusServer()
+			defer server.Close()
+
+			server.SetMetrics(testutil.LuminaMetricsWithSPCapacity(), testutil.LuminaMetricsWithRegionalRI())
+			server.SetMetrics(testutil.MetricFixture{
+				`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+					"status": "success",
+					"data": {
+						"resultType": "vector",
+						"result": [{
+							"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+							"value": [1640000000, "30"]
+						}]
+					}
+				}`,
+			})
+
+			promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+			k8sClient := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithObjects(tt.objects...).Build()
+
+			cfg := &config.Config{}
+			cfg.Overlays.Weights.ReservedInstance = config.DefaultOverlayWeightReservedInstance
+
+			reconciler := &MetricsReconciler{
+				PrometheusClient: promClient,
+				Config:           cfg,
+				DecisionEngine:   overlay.NewDecisionEngine(cfg),
+				Generator:        overlay.NewGenerator(),
+				Client:           k8sClient,
+				Logger:           logr.Discard(),
+			}
+
+			if err := reconciler.reconcile(context.Background()); err != nil {
+				t.Fatalf("reconcile() unexpected error: %v", err)
+			}
+
+			var got karpenterv1alpha1.NodeOverlay
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "cost-aware-ri-m5.xlarge-us-west-2"}, &got)
+			if tt.wantOverlay && err != nil {
+				t.Errorf("expected RI overlay to be created: %v", err)
+			}
+			if !tt.wantOverlay && !apierrors.IsNotFound(err) {
+				t.Errorf("expected RI overlay to be withdrawn, got err=%v", err)
+			}
+		})
+	}
+}
+
+func TestAssignReservedInstanceUsage(t *testing.T) {
+	aggs := map[string]overlay.AggregatedReservedInstance{
+		"m5.xlarge:us-west-2": {InstanceType: "m5.xlarge", Region: "us-west-2", TotalCount: 2},
+		"m5.xlarge:us-west-2:us-west-2a": {
+			InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2a", TotalCount: 2,
+		},
+	}
+
+	// 3 running in us-west-2a (2 take the zonal RIs, 1 overflows to the regional RIs),
+	// 1 running in us-west-2b, and 1 launching in us-west-2a (overflows to regional)
+	usage := reservedInstanceUsage{
+		runningByType:   map[string]int{"m5.xlarge:us-west-2": 4},
+		launchingByType: map[string]int{"m5.xlarge:us-west-2": 1},
+		runningByZone: map[string]int{
+			"m5.xlarge:us-west-2:us-west-2a": 3,
+			"m5.xlarge:us-west-2:us-west-2b": 1,
+		},
+		launchingByZone: map[string]int{"m5.xlarge:us-west-2:us-west-2a": 1},
+	}
+
+	assignReservedInstanceUsage(aggs, usage)
+
+	tests := []struct {
+		key           string
+		wantRunning   int
+		wantLaunching int
+	}{
+		{"m5.xlarge:us-west-2:us-west-2a", 3, 1},
+		{"m5.xlarge:us-west-2", 2, 1},
+	}
+
+	for _, tt := range tests {
+		agg := aggs[tt.key]
+		if agg.RunningCount != tt.wantRunning {
+			t.Errorf("%s: RunningCount = %d, want %d", tt.key, agg.RunningCount, tt.wantRunning)
+		}
+		if agg.PendingLaunchCount != tt.wantLaunching {
+			t.Errorf("%s: PendingLaunchCount = %d, want %d", tt.key, agg.PendingLaunchCount, tt.wantLaunching)
+		}
+	}
+}
+
+func TestAssignReservedInstanceFamilyUsage(t *testing.T) {
+	// Zonal m5.xlarge RIs are matched exactly; regional m5 RIs are size-flexible
+	aggs := map[string]overlay.AggregatedReservedInstance{
+		"m5.xlarge:us-west-2:us-west-2a": {
+			InstanceType: "m5.xlarge", Region: "us-west-2", AvailabilityZone: "us-west-2a", TotalCount: 1,
+		},
+	}
+	families := map[string]overlay.AggregatedReservedInstanceFamily{
+		"m5:us-west-2": {InstanceFamily: "m5", Region: "us-west-2", TotalUnits: 32},
+	}
+
+	// 2 m5.xlarge running in us-west-2a (1 takes the zonal RI), 1 m5.2xlarge running,
+	// 1 m5.large launching, and instances of other families or without a factor
+	usage := reservedInstanceUsage{
+		runningByType: map[string]int{
+			"m5.xlarge:us-west-2":  2,
+			"m5.2xlarge:us-west-2": 1,
+			"m5.metal:us-west-2":   1,
+			"c5.xlarge:us-west-2":  3,
+		},
+		launchingByType: map[string]int{"m5.large:us-west-2": 1},
+		runningByZone:   map[string]int{"m5.xlarge:us-west-2:us-west-2a": 2},
+		launchingByZone: map[string]int{},
+	}
+
+	assignReservedInstanceUsage(aggs, usage)
+	assignReservedInstanceFamilyUsage(families, aggs, usage)
+
+	family := families["m5:us-west-2"]
+	// 1 overflowing m5.xlarge (8) + 1 m5.2xlarge (16)
+	if family.RunningUnits != 24 {
+		t.Errorf("RunningUnits = %f, want 24", family.RunningUnits)
+	}
+	if family.PendingLaunchUnits != 4 {
+		t.Errorf("PendingLaunchUnits = %f, want 4", family.PendingLaunchUnits)
+	}
+	if _, exists := families["c5:us-west-2"]; exists {
+		t.Error("expected no aggregate for a family without RIs")
+	}
+}
+
+func TestMetricsReconciler_SizeFlexibleReservedInstances(t *testing.T) {
+	// Lumina reports a single regional m5.xlarge RI in us-west-2: 8 normalized units
+	runningNode := func(instanceType string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + instanceType, Labels: map[string]string{
+			karpenterv1.CapacityTypeLabelKey:   karpenterv1.CapacityTypeOnDemand,
+			"node.kubernetes.io/instance-type": instanceType,
+			"topology.kubernetes.io/region":    "us-west-2",
+		}}}
+	}
+
+	tests := []struct {
+		name      string
+		objects   []client.Object
+		wantTypes []string // nil if the overlay should be withdrawn
+	}{
+		{"no instances running", nil, []string{"m5.large", "m5.xlarge"}},
+		{"smaller size occupies part of the RI", []client.Object{runningNode("m5.large")}, []string{"m5.large"}},
+		{"same size occupies the whole RI", []client.Object{runningNode("m5.xlarge")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewMockPrometheusServer()
+			defer server.Close()
+
+			server.SetMetrics(testutil.LuminaMetricsWithSPCapacity(), testutil.LuminaMetricsWithRegionalRI())
+			server.SetMetrics(testutil.MetricFixture{
+				`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+					"status": "success",
+					"data": {
+						"resultType": "vector",
+						"result": [{
+							"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+							"value": [1640000000, "30"]
+						}]
+					}
+				}`,
+				`lumina_data_freshness_seconds{account_id="123456789012", data_type="pricing"}`: `{
+					"status": "success",
+					"data": {
+						"resultType": "vector",
+						"result": [{
+							"metric": {"account_id": "123456789012", "data_type": "pricing"},
+							"value": [1640000000, "30"]
+						}]
+					}
+				}`,
+				`ec2_ondemand_price`: `{
+					"status": "success",
+					"data": {
+						"resultType": "vector",
+						"result": [
+							{"metric": {"instance_type": "m5.large", "region": "us-west-2"}, "value": [1640000000, "0.096"]},
+							{"metric": {"instance_type": "m5.xlarge", "region": "us-west-2"}, "value": [1640000000, "0.192"]},
+							{"metric": {"instance_type": "m5.2xlarge", "region": "us-west-2"}, "value": [1640000000, "0.384"]}
+						]
+					}
+				}`,
+			})
+
+			promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+			k8sClient := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).WithObjects(tt.objects...).Build()
+
+			cfg := &config.Config{}
+			cfg.Overlays.Weights.ReservedInstance = config.DefaultOverlayWeightReservedInstance
+			cfg.Overlays.ReservedInstanceMatching = config.ReservedInstanceMatchingSizeFlexible
+
+			reconciler := &MetricsReconciler{
+				PrometheusClient: promClient,
+				Config:           cfg,
+				DecisionEngine:   overlay.NewDecisionEngine(cfg),
+				Generator:        overlay.NewGenerator(),
+				Client:           k8sClient,
+				Logger:           logr.Discard(),
+			}
+
+			if err := reconciler.reconcile(context.Background()); err != nil {
+				t.Fatalf("reconcile() unexpected error: %v", err)
+			}
+
+			// The exact-type overlay is never created in size-flexible mode
+			var exact karpenterv1alpha1.NodeOverlay
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "cost-aware-ri-m5.xlarge-us-west-2"}, &exact)
+			if !apierrors.IsNotFound(err) {
+				t.Errorf("expected no exact-type RI overlay, got err=%v", err)
+			}
+
+			var got karpenterv1alpha1.NodeOverlay
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "cost-aware-ri-m5-us-west-2"}, &got)
+			if tt.wantTypes == nil {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected family RI overlay to be withdrawn, got err=%v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected family RI overlay to be created: %v", err)
+			}
+
+			var gotTypes []string
+			for _, req := range got.Spec.Requirements {
+				if req.Key == corev1.LabelInstanceTypeStable {
+					gotTypes = req.Values
+				}
+			}
+			if !reflect.DeepEqual(gotTypes, tt.wantTypes) {
+				t.Errorf("covered instance types = %v, want %v", gotTypes, tt.wantTypes)
+			}
+		})
+	}
+}
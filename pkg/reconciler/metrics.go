@@ -16,48 +16,46 @@ limitations under the License.
 
 // Package reconciler provides Kubernetes controllers for managing cost-aware provisioning.
 //
-// The metrics reconciler periodically queries Prometheus for Lumina metrics,
-// makes overlay lifecycle decisions, and creates/updates/deletes NodeOverlay resources.
+// The metrics reconciler queries Prometheus for Lumina metrics whenever Lumina publishes
+// new data (and periodically as a backstop), makes overlay lifecycle decisions, and
+// creates/updates/deletes NodeOverlay resources.
 package reconciler
 
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/nextdoor/veneer/pkg/config"
 	veneermetrics "github.com/nextdoor/veneer/pkg/metrics"
 	"github.com/nextdoor/veneer/pkg/overlay"
+	"github.com/nextdoor/veneer/pkg/preference"
 	"github.com/nextdoor/veneer/pkg/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 )
 
-// Default configuration values for the reconciler.
-const (
-	// DefaultReconcileInterval is the default interval between reconciliation cycles.
-	DefaultReconcileInterval = 5 * time.Minute
-
-	// MaxSavingsPlanFreshnessSeconds is the maximum age of Savings Plan data before it's considered stale.
-	// Lumina refreshes SP data hourly, so we allow 65 minutes (1 hour + buffer).
-	MaxSavingsPlanFreshnessSeconds = 3900.0 // 65 minutes
-
-	// MaxReservedInstanceFreshnessSeconds is the maximum age of Reserved Instance data before it's considered stale.
-	// Lumina refreshes RI data hourly, so we allow 65 minutes (1 hour + buffer).
-	MaxReservedInstanceFreshnessSeconds = 3900.0 // 65 minutes
-)
-
-// MetricsReconciler periodically queries Prometheus for Lumina metrics.
+// MetricsReconciler queries Prometheus for Lumina metrics.
 // It analyzes capacity utilization, makes overlay lifecycle decisions,
 // and creates/updates/deletes NodeOverlay resources in the cluster.
+//
+// It reconciles when Lumina publishes new data, detected by polling Lumina data freshness,
+// when a reconcile is requested with Trigger, and on a fixed interval as a backstop.
 type MetricsReconciler struct {
 	// PrometheusClient is the client for querying Lumina metrics
 	PrometheusClient *prometheus.Client
 
-	// Config is the controller configuration
-	Config *config.Config
+	// Config supplies the controller configuration, including the maximum age of each type
+	// of Lumina data. It is read at the start of every reconciliation, so reloaded settings
+	// apply from the next cycle.
+	Config config.Provider
 
 	// DecisionEngine analyzes capacity and determines overlay lifecycle
 	DecisionEngine *overlay.DecisionEngine
@@ -71,31 +69,99 @@ type MetricsReconciler struct {
 	// Logger is the structured logger for this reconciler
 	Logger logr.Logger
 
-	// Interval is how often to query metrics (default: 5 minutes)
+	// Interval is the maximum time between reconciles (default: config.DefaultReconcileInterval)
 	Interval time.Duration
 
+	// PollInterval is how often to poll Lumina data freshness for new data
+	// (default: config.DefaultReconcilePollInterval)
+	PollInterval time.Duration
+
 	// Metrics holds the Prometheus metrics for recording reconciler behavior.
 	// This follows Lumina's pattern of passing metrics struct to reconcilers.
 	Metrics *veneermetrics.Metrics
+
+	// Hysteresis remembers overlay states across reconcile cycles to prevent flapping.
+	// Optional: when nil, decisions are applied as the DecisionEngine makes them.
+	Hysteresis *overlay.HysteresisTracker
+
+	// Recorder emits Kubernetes Events on the NodeOverlays this reconciler writes.
+	// Optional: no events are recorded when nil.
+	Recorder events.EventRecorder
+
+	// mu guards status, which is read by the VeneerConfig reconciler, trigger, and lastHTTPTrigger.
+	mu     sync.Mutex
+	status ReconcileStatus
+
+	// trigger holds a pending reconcile request from Trigger.
+	trigger chan struct{}
+
+	// lastHTTPTrigger is when TriggerHandler last accepted a request.
+	lastHTTPTrigger time.Time
+
+	// lastOnDemandPrices holds the most recent on-demand prices queried from Lumina, used to
+	// cost pending launches while prices are stale. Only accessed by reconcile.
+	lastOnDemandPrices map[string]float64
+}
+
+// ReconcileStatus summarizes the metrics reconciler's most recent reconciliation.
+type ReconcileStatus struct {
+	// Time is when the most recent reconciliation finished. Zero until the first one finishes.
+	Time time.Time
+
+	// DataFreshness is the freshness of each type of Lumina data, as of the last time it
+	// was successfully queried.
+	DataFreshness map[prometheus.DataType]DataFreshness
+}
+
+// DataFreshness describes how fresh one type of Lumina data was when it was queried.
+type DataFreshness struct {
+	// CollectedAt is when Lumina collected the data.
+	CollectedAt time.Time
+
+	// Stale is true when the data was too old to analyze.
+	Stale bool
+}
+
+// Status returns a summary of the most recent reconciliation. It is safe to call while the
+// reconciler runs.
+func (r *MetricsReconciler) Status() ReconcileStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return ReconcileStatus{
+		Time:          r.status.Time,
+		DataFreshness: maps.Clone(r.status.DataFreshness),
+	}
 }
 
 // Start begins the metrics reconciliation loop.
 // It runs until the context is cancelled.
 func (r *MetricsReconciler) Start(ctx context.Context) error {
-	r.Logger.Info("Starting metrics reconciler", "interval", r.Interval)
-
-	// Use default interval if not set
+	// Use default intervals if not set
 	if r.Interval == 0 {
-		r.Interval = DefaultReconcileInterval
+		r.Interval = config.DefaultReconcileInterval
+	}
+	if r.PollInterval == 0 {
+		r.PollInterval = config.DefaultReconcilePollInterval
 	}
 
+	r.Logger.Info("Starting metrics reconciler", "interval", r.Interval, "poll_interval", r.PollInterval)
+
 	ticker := time.NewTicker(r.Interval)
 	defer ticker.Stop()
+	poll := time.NewTicker(r.PollInterval)
+	defer poll.Stop()
+	trigger := r.triggerChannel()
+
+	// Record the current data ages, so the first poll only detects data published after startup
+	dataAges := make(map[prometheus.DataType]float64)
+	r.pollDataFreshness(ctx, dataAges)
 
 	// Run once immediately on startup
 	r.runReconcileWithMetrics(ctx)
 
-	// Then run on the ticker interval
+	// Then run whenever Lumina publishes new data or a reconcile is requested, and on the
+	// ticker interval as a backstop. The backstop restarts after every reconcile.
 	for {
 		select {
 		case <-ctx.Done():
@@ -103,6 +169,15 @@ func (r *MetricsReconciler) Start(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			r.runReconcileWithMetrics(ctx)
+		case <-poll.C:
+			if r.pollDataFreshness(ctx, dataAges) {
+				r.runReconcileWithMetrics(ctx)
+				ticker.Reset(r.Interval)
+			}
+		case <-trigger:
+			r.Logger.Info("Reconcile requested")
+			r.runReconcileWithMetrics(ctx)
+			ticker.Reset(r.Interval)
 		}
 	}
 }
@@ -113,6 +188,10 @@ func (r *MetricsReconciler) runReconcileWithMetrics(ctx context.Context) {
 	err := r.reconcile(ctx)
 	duration := time.Since(startTime).Seconds()
 
+	r.mu.Lock()
+	r.status.Time = time.Now()
+	r.mu.Unlock()
+
 	if err != nil {
 		r.Logger.Error(err, "Failed to reconcile metrics")
 		if r.Metrics != nil {
@@ -137,64 +216,91 @@ func (r *MetricsReconciler) reconcile(ctx context.Context) error {
 	// Collect all decisions
 	var decisions []overlay.Decision
 
+	// Track which capacity types were fully analyzed from fresh data this cycle.
+	// Only these types are eligible for orphaned overlay garbage collection, so
+	// stale or unavailable data never causes existing overlays to be removed.
+	analyzedTypes := make(map[overlay.CapacityType]bool)
+
+	// Pricing is best-effort: missing data disables RI absolute pricing and spot comparison
+	// but never blocks analysis
+	pricing := r.queryPricing(ctx)
+
 	// Check Savings Plan data freshness and analyze if data is fresh enough
-	spFreshness, spFreshnessErr := r.queryDataFreshness(ctx, prometheus.DataTypeSavingsPlans)
+	spFreshness, spMaxFreshness, spFreshnessErr := r.queryDataFreshness(ctx, prometheus.DataTypeSavingsPlans)
 	if spFreshnessErr != nil {
 		r.Logger.Error(spFreshnessErr, "Failed to query Savings Plan data freshness")
 	} else {
 		r.Logger.Info("Lumina Savings Plan data freshness", "age_seconds", spFreshness)
 
-		if spFreshness <= MaxSavingsPlanFreshnessSeconds {
+		if spFreshness > spMaxFreshness {
+			r.Logger.Info("Skipping Savings Plan analysis due to stale data",
+				"freshness_seconds", spFreshness,
+				"max_freshness_seconds", spMaxFreshness,
+			)
+		} else if spPending, ok := r.savingsPlanPendingLaunches(ctx, spFreshness, pricing); ok {
 			// Query and analyze Compute Savings Plans
-			computeDecisions, err := r.analyzeComputeSavingsPlans(ctx)
+			computeDecisions, err := r.analyzeComputeSavingsPlans(ctx, pricing, spPending)
 			if err != nil {
 				r.Logger.Error(err, "Failed to analyze Compute Savings Plans")
 			} else {
 				decisions = append(decisions, computeDecisions...)
+				analyzedTypes[overlay.CapacityTypeComputeSavingsPlan] = true
 			}
 
 			// Query and analyze EC2 Instance Savings Plans
-			ec2Decisions, err := r.analyzeEC2InstanceSavingsPlans(ctx)
+			ec2Decisions, err := r.analyzeEC2InstanceSavingsPlans(ctx, pricing, spPending)
 			if err != nil {
 				r.Logger.Error(err, "Failed to analyze EC2 Instance Savings Plans")
 			} else {
 				decisions = append(decisions, ec2Decisions...)
+				analyzedTypes[overlay.CapacityTypeEC2InstanceSavingsPlan] = true
 			}
-		} else {
-			r.Logger.Info("Skipping Savings Plan analysis due to stale data",
-				"freshness_seconds", spFreshness,
-				"max_freshness_seconds", MaxSavingsPlanFreshnessSeconds,
-			)
 		}
 	}
 
 	// Check Reserved Instance data freshness and analyze if data is fresh enough
-	riFreshness, riFreshnessErr := r.queryDataFreshness(ctx, prometheus.DataTypeReservedInstances)
+	riFreshness, riMaxFreshness, riFreshnessErr := r.queryDataFreshness(ctx, prometheus.DataTypeReservedInstances)
 	if riFreshnessErr != nil {
 		r.Logger.Error(riFreshnessErr, "Failed to query Reserved Instance data freshness")
 	} else {
 		r.Logger.Info("Lumina Reserved Instance data freshness", "age_seconds", riFreshness)
 
-		if riFreshness <= MaxReservedInstanceFreshnessSeconds {
+		if riFreshness <= riMaxFreshness {
+			// Usage is best-effort: instances that couldn't be counted leave their RIs
+			// looking unoccupied, so a failure only loses precision
+			riUsage, err := r.queryReservedInstanceUsage(ctx)
+			if err != nil {
+				r.Logger.Error(err, "Failed to count on-demand instances occupying Reserved Instances")
+			}
+
 			// Query and analyze Reserved Instances
-			riDecisions, err := r.analyzeReservedInstances(ctx)
+			riDecisions, err := r.analyzeReservedInstances(ctx, pricing, riUsage)
 			if err != nil {
 				r.Logger.Error(err, "Failed to analyze Reserved Instances")
 			} else {
 				decisions = append(decisions, riDecisions...)
+				analyzedTypes[overlay.CapacityTypeReservedInstance] = true
 			}
 		} else {
 			r.Logger.Info("Skipping Reserved Instance analysis due to stale data",
 				"freshness_seconds", riFreshness,
-				"max_freshness_seconds", MaxReservedInstanceFreshnessSeconds,
+				"max_freshness_seconds", riMaxFreshness,
 			)
 		}
 	}
 
 	// Generate and apply NodeOverlay specs from decisions
-	if r.Generator != nil && r.Client != nil && len(decisions) > 0 {
-		generatedOverlays := r.Generator.GenerateAll(decisions)
-		r.applyOverlays(ctx, generatedOverlays)
+	if r.Generator != nil && r.Client != nil {
+		if len(decisions) > 0 {
+			generatedOverlays := r.Generator.GenerateAll(decisions)
+			r.applyOverlays(ctx, generatedOverlays)
+		}
+
+		// Remove overlays whose backing capacity disappeared entirely (e.g., an expired RI).
+		// These produce no decision at all, so applyOverlays never sees them.
+		if r.DecisionEngine != nil {
+			r.deleteOrphanedOverlays(ctx, decisions, analyzedTypes)
+		}
 	}
 
 	r.Logger.V(1).Info("Metrics reconciliation complete",
@@ -205,28 +311,23 @@ func (r *MetricsReconciler) reconcile(ctx context.Context) error {
 }
 
 // queryDataFreshness queries Lumina data freshness for a specific data type with metrics instrumentation.
-func (r *MetricsReconciler) queryDataFreshness(ctx context.Context, dataType prometheus.DataType) (float64, error) {
+// It returns the age of the data and the configured maximum age, both in seconds.
+func (r *MetricsReconciler) queryDataFreshness(
+	ctx context.Context,
+	dataType prometheus.DataType,
+) (freshnessSeconds, maxFreshness float64, err error) {
 	startTime := time.Now()
-	freshnessSeconds, err := r.PrometheusClient.DataFreshness(ctx, dataType)
+	freshnessSeconds, err = r.PrometheusClient.DataFreshness(ctx, dataType)
 	duration := time.Since(startTime).Seconds()
 
-	// Determine max freshness based on data type
-	var maxFreshness float64
-	switch dataType {
-	case prometheus.DataTypeSavingsPlans:
-		maxFreshness = MaxSavingsPlanFreshnessSeconds
-	case prometheus.DataTypeReservedInstances:
-		maxFreshness = MaxReservedInstanceFreshnessSeconds
-	default:
-		maxFreshness = MaxSavingsPlanFreshnessSeconds // Default to SP threshold
-	}
+	maxFreshness = r.maxFreshness(dataType).Seconds()
 
 	if err != nil {
 		if r.Metrics != nil {
 			r.Metrics.RecordPrometheusQuery(veneermetrics.QueryTypeDataFreshness, duration, 0, err)
 			r.Metrics.SetLuminaDataUnavailable()
 		}
-		return 0, err
+		return 0, maxFreshness, err
 	}
 
 	if r.Metrics != nil {
@@ -234,11 +335,69 @@ func (r *MetricsReconciler) queryDataFreshness(ctx context.Context, dataType pro
 		r.Metrics.SetLuminaDataFreshness(freshnessSeconds, maxFreshness)
 	}
 
-	return freshnessSeconds, nil
+	r.mu.Lock()
+	if r.status.DataFreshness == nil {
+		r.status.DataFreshness = make(map[prometheus.DataType]DataFreshness)
+	}
+	r.status.DataFreshness[dataType] = DataFreshness{
+		CollectedAt: dataCollectedAt(freshnessSeconds),
+		Stale:       freshnessSeconds > maxFreshness,
+	}
+	r.mu.Unlock()
+
+	return freshnessSeconds, maxFreshness, nil
+}
+
+// maxFreshness returns the configured maximum age of a type of Lumina data.
+func (r *MetricsReconciler) maxFreshness(dataType prometheus.DataType) time.Duration {
+	var limits config.MaxFreshnessConfig
+	if cfg := currentConfig(r.Config); cfg != nil {
+		limits = cfg.Reconcile.MaxFreshness
+	}
+	limits = limits.Limits()
+
+	switch dataType {
+	case prometheus.DataTypeReservedInstances:
+		return limits.ReservedInstances
+	case prometheus.DataTypeSpotPricing:
+		return limits.SpotPricing
+	case prometheus.DataTypePricing:
+		return limits.Pricing
+	default:
+		return limits.SavingsPlans
+	}
+}
+
+// dataFresh reports whether a type of Lumina data is fresh enough to use. Data whose
+// freshness can't be queried is treated as stale.
+func (r *MetricsReconciler) dataFresh(ctx context.Context, dataType prometheus.DataType) bool {
+	freshness, maxFreshness, err := r.queryDataFreshness(ctx, dataType)
+	if err != nil {
+		r.Logger.Error(err, "Failed to query Lumina data freshness", "data_type", dataType)
+		return false
+	}
+	if freshness > maxFreshness {
+		r.Logger.Info("Lumina data is stale",
+			"data_type", dataType,
+			"freshness_seconds", freshness,
+			"max_freshness_seconds", maxFreshness,
+		)
+		return false
+	}
+	return true
+}
+
+// dataCollectedAt converts a Lumina data age in seconds to the time the data was collected.
+func dataCollectedAt(ageSeconds float64) time.Time {
+	return time.Now().Add(-time.Duration(ageSeconds * float64(time.Second)))
 }
 
 // analyzeComputeSavingsPlans queries and analyzes Compute Savings Plans.
-func (r *MetricsReconciler) analyzeComputeSavingsPlans(ctx context.Context) ([]overlay.Decision, error) {
+func (r *MetricsReconciler) analyzeComputeSavingsPlans(
+	ctx context.Context,
+	pricing pricingData,
+	pending pendingLaunches,
+) ([]overlay.Decision, error) {
 	// Query utilization with metrics
 	startTime := time.Now()
 	utilizations, err := r.PrometheusClient.QuerySavingsPlanUtilization(ctx, prometheus.SavingsPlanTypeCompute)
@@ -279,8 +438,11 @@ func (r *MetricsReconciler) analyzeComputeSavingsPlans(ctx context.Context) ([]o
 	if r.DecisionEngine == nil {
 		return nil, nil
 	}
+	agg.SpotSavingsPercent = pricing.overallSpotSavings
+	agg.PendingLaunchCost = pending.totalCost
 
 	decision := r.DecisionEngine.AnalyzeComputeSavingsPlan(agg)
+	decision = r.applyHysteresis(ctx, decision)
 
 	// Record decision metric
 	if r.Metrics != nil {
@@ -294,6 +456,8 @@ func (r *MetricsReconciler) analyzeComputeSavingsPlans(ctx context.Context) ([]o
 	r.Logger.Info("Compute Savings Plan analysis",
 		"total_remaining_capacity", agg.TotalRemainingCapacity,
 		"utilization_percent", agg.UtilizationPercent,
+		"spot_savings_percent", agg.SpotSavingsPercent,
+		"pending_launch_cost", agg.PendingLaunchCost,
 		"should_exist", decision.ShouldExist,
 		"reason", decision.Reason,
 	)
@@ -302,7 +466,11 @@ func (r *MetricsReconciler) analyzeComputeSavingsPlans(ctx context.Context) ([]o
 }
 
 // analyzeEC2InstanceSavingsPlans queries and analyzes EC2 Instance Savings Plans.
-func (r *MetricsReconciler) analyzeEC2InstanceSavingsPlans(ctx context.Context) ([]overlay.Decision, error) {
+func (r *MetricsReconciler) analyzeEC2InstanceSavingsPlans(
+	ctx context.Context,
+	pricing pricingData,
+	pending pendingLaunches,
+) ([]overlay.Decision, error) {
 	// Query utilization with metrics
 	startTime := time.Now()
 	utilizations, err := r.PrometheusClient.QuerySavingsPlanUtilization(ctx, prometheus.SavingsPlanTypeEC2Instance)
@@ -346,7 +514,11 @@ func (r *MetricsReconciler) analyzeEC2InstanceSavingsPlans(ctx context.Context)
 
 	decisions := make([]overlay.Decision, 0, len(aggByFamily))
 	for key, agg := range aggByFamily {
+		agg.SpotSavingsPercent = pricing.spotSavingsByFamily[key]
+		agg.InstanceTypePrices = overlay.InstanceTypePricesForFamily(pricing.onDemand, agg.InstanceFamily, agg.Region)
+		agg.PendingLaunchCost = pending.costByFamily[key]
 		decision := r.DecisionEngine.AnalyzeEC2InstanceSavingsPlan(agg)
+		decision = r.applyHysteresis(ctx, decision)
 
 		// Record decision metric
 		if r.Metrics != nil {
@@ -361,6 +533,9 @@ func (r *MetricsReconciler) analyzeEC2InstanceSavingsPlans(ctx context.Context)
 			"family_region", key,
 			"total_remaining_capacity", agg.TotalRemainingCapacity,
 			"utilization_percent", agg.UtilizationPercent,
+			"spot_savings_percent", agg.SpotSavingsPercent,
+			"pending_launch_cost", agg.PendingLaunchCost,
+			"covered_instance_types", decision.InstanceTypes,
 			"should_exist", decision.ShouldExist,
 			"reason", decision.Reason,
 		)
@@ -372,7 +547,11 @@ func (r *MetricsReconciler) analyzeEC2InstanceSavingsPlans(ctx context.Context)
 }
 
 // analyzeReservedInstances queries and analyzes Reserved Instances.
-func (r *MetricsReconciler) analyzeReservedInstances(ctx context.Context) ([]overlay.Decision, error) {
+func (r *MetricsReconciler) analyzeReservedInstances(
+	ctx context.Context,
+	pricing pricingData,
+	usage reservedInstanceUsage,
+) ([]overlay.Decision, error) {
 	// Query all RIs with metrics
 	startTime := time.Now()
 	ris, err := r.PrometheusClient.QueryReservedInstances(ctx, "")
@@ -407,15 +586,29 @@ func (r *MetricsReconciler) analyzeReservedInstances(ctx context.Context) ([]ove
 		return nil, nil
 	}
 
-	// Aggregate by instance type+region and analyze each
-	aggByType := overlay.AggregateReservedInstances(ris)
 	if r.DecisionEngine == nil {
 		return nil, nil
 	}
 
-	decisions := make([]overlay.Decision, 0, len(aggByType))
+	// In size-flexible mode, regional RIs are aggregated per family in normalized units;
+	// the rest are aggregated by instance type+region
+	exactRIs := ris
+	var families map[string]overlay.AggregatedReservedInstanceFamily
+	if cfg := currentConfig(r.Config); cfg != nil && cfg.Overlays.SizeFlexibleReservedInstances() {
+		families, exactRIs = overlay.AggregateReservedInstancesByFamily(ris)
+	}
+	aggByType := overlay.AggregateReservedInstances(exactRIs)
+	assignReservedInstanceUsage(aggByType, usage)
+	assignReservedInstanceFamilyUsage(families, aggByType, usage)
+
+	decisions := make([]overlay.Decision, 0, len(aggByType)+len(families))
 	for key, agg := range aggByType {
+		// Prices are per region, even for zonal RIs
+		priceKey := agg.InstanceType + ":" + agg.Region
+		agg.OnDemandPrice = pricing.onDemand[priceKey]
+		agg.SpotPrice = pricing.spot[priceKey]
 		decision := r.DecisionEngine.AnalyzeReservedInstance(agg)
+		decision = r.applyHysteresis(ctx, decision)
 
 		// Record decision metric
 		if r.Metrics != nil {
@@ -429,6 +622,47 @@ func (r *MetricsReconciler) analyzeReservedInstances(ctx context.Context) ([]ove
 		r.Logger.Info("Reserved Instance analysis",
 			"type_region", key,
 			"total_count", agg.TotalCount,
+			"running_count", agg.RunningCount,
+			"pending_launch_count", agg.PendingLaunchCount,
+			"utilization_percent", decision.UtilizationPercent,
+			"on_demand_price", agg.OnDemandPrice,
+			"spot_price", agg.SpotPrice,
+			"price", decision.Price,
+			"price_adjustment", decision.PriceAdjustment,
+			"should_exist", decision.ShouldExist,
+			"reason", decision.Reason,
+		)
+
+		decisions = append(decisions, decision)
+	}
+
+	for key, agg := range families {
+		for instanceType := range overlay.InstanceTypePricesForFamily(pricing.onDemand, agg.InstanceFamily, agg.Region) {
+			if !slices.Contains(agg.InstanceTypes, instanceType) {
+				agg.InstanceTypes = append(agg.InstanceTypes, instanceType)
+			}
+		}
+		agg.SpotSavingsPercent = pricing.spotSavingsByFamily[key]
+		decision := r.DecisionEngine.AnalyzeReservedInstanceFamily(agg)
+		decision = r.applyHysteresis(ctx, decision)
+
+		// Record decision metric
+		if r.Metrics != nil {
+			r.Metrics.RecordDecision(
+				veneermetrics.CapacityTypeRI,
+				veneermetrics.BoolToShouldExist(decision.ShouldExist),
+				veneermetrics.SanitizeReason(decision.Reason),
+			)
+		}
+
+		r.Logger.Info("Size-flexible Reserved Instance analysis",
+			"family_region", key,
+			"total_units", agg.TotalUnits,
+			"running_units", agg.RunningUnits,
+			"pending_launch_units", agg.PendingLaunchUnits,
+			"utilization_percent", decision.UtilizationPercent,
+			"spot_savings_percent", agg.SpotSavingsPercent,
+			"covered_instance_types", decision.InstanceTypes,
 			"should_exist", decision.ShouldExist,
 			"reason", decision.Reason,
 		)
@@ -439,6 +673,161 @@ func (r *MetricsReconciler) analyzeReservedInstances(ctx context.Context) ([]ove
 	return decisions, nil
 }
 
+// applyHysteresis damps a decision using the hysteresis tracker so overlays don't flap
+// when utilization hovers around a threshold. Overlays the tracker has not seen yet are
+// seeded from the cluster, using the creation timestamp of existing overlays as the
+// start of their current state.
+func (r *MetricsReconciler) applyHysteresis(ctx context.Context, decision overlay.Decision) overlay.Decision {
+	if r.Hysteresis == nil {
+		return decision
+	}
+
+	if !r.Hysteresis.Known(decision.Name) && r.Client != nil {
+		existing := &karpenterv1alpha1.NodeOverlay{}
+		err := r.Client.Get(ctx, client.ObjectKey{Name: decision.Name}, existing)
+		switch {
+		case err == nil:
+			r.Hysteresis.Seed(decision.Name, true, existing.CreationTimestamp.Time)
+		case errors.IsNotFound(err):
+			r.Hysteresis.Seed(decision.Name, false, time.Time{})
+		default:
+			// Without the current state the tracker would assume the overlay is absent,
+			// so skip damping this cycle and retry seeding on the next one
+			r.Logger.Error(err, "Failed to read NodeOverlay state for hysteresis", "name", decision.Name)
+			return decision
+		}
+	}
+
+	var minStateDuration time.Duration
+	if cfg := currentConfig(r.Config); cfg != nil {
+		minStateDuration = cfg.Overlays.MinStateDuration
+	}
+
+	damped := r.Hysteresis.Apply(decision, minStateDuration, time.Now())
+	if damped.Suppressed {
+		r.Logger.Info("Overlay decision suppressed by hysteresis",
+			"name", damped.Name,
+			"engine_should_exist", decision.ShouldExist,
+			"should_exist", damped.ShouldExist,
+			"reason", damped.Reason,
+		)
+	}
+
+	return damped
+}
+
+// recordOverlayState tells the hysteresis tracker that an overlay now exists or not, once
+// it was actually written, so a failed write doesn't start the dwell clock.
+func (r *MetricsReconciler) recordOverlayState(name string, exists bool) {
+	if r.Hysteresis != nil {
+		r.Hysteresis.Record(name, exists, time.Now())
+	}
+}
+
+// pricingData holds the Lumina pricing used to price overlays and compare them against spot.
+type pricingData struct {
+	// onDemand maps "instanceType:region" to the on-demand price in $/hour
+	onDemand map[string]float64
+
+	// spot maps "instanceType:region" to the lowest spot price across AZs in $/hour
+	spot map[string]float64
+
+	// spotSavingsByFamily maps "family:region" to the average spot savings percentage
+	spotSavingsByFamily map[string]float64
+
+	// overallSpotSavings is the average spot savings percentage across all instance types
+	overallSpotSavings float64
+}
+
+// queryPricing queries on-demand and spot prices from Lumina.
+// Query failures and stale prices are logged and yield empty pricing so analysis can proceed
+// without it: RI overlays fall back to price adjustments and no spot comparison is made.
+func (r *MetricsReconciler) queryPricing(ctx context.Context) pricingData {
+	pricing := pricingData{
+		onDemand:            map[string]float64{},
+		spot:                map[string]float64{},
+		spotSavingsByFamily: map[string]float64{},
+	}
+
+	if !r.dataFresh(ctx, prometheus.DataTypePricing) {
+		r.Logger.Info("On-demand prices unavailable, falling back to price adjustments")
+		return pricing
+	}
+
+	startTime := time.Now()
+	onDemandPrices, err := r.PrometheusClient.QueryOnDemandPrice(ctx, "")
+	duration := time.Since(startTime).Seconds()
+	if r.Metrics != nil {
+		r.Metrics.RecordPrometheusQuery(veneermetrics.QueryTypeOnDemandPrice, duration, len(onDemandPrices), err)
+	}
+	if err != nil {
+		r.Logger.Error(err, "Failed to query on-demand prices, falling back to price adjustments")
+		return pricing
+	}
+	pricing.onDemand = overlay.AggregateOnDemandPrices(onDemandPrices)
+	r.lastOnDemandPrices = pricing.onDemand
+
+	if !r.dataFresh(ctx, prometheus.DataTypeSpotPricing) {
+		r.Logger.Info("Spot prices unavailable, skipping spot comparison")
+		return pricing
+	}
+
+	startTime = time.Now()
+	spotPrices, err := r.PrometheusClient.QuerySpotPrice(ctx, "")
+	duration = time.Since(startTime).Seconds()
+	if r.Metrics != nil {
+		r.Metrics.RecordPrometheusQuery(veneermetrics.QueryTypeSpotPrice, duration, len(spotPrices), err)
+	}
+	if err != nil {
+		r.Logger.Error(err, "Failed to query spot prices, skipping spot comparison")
+		return pricing
+	}
+	pricing.spot = overlay.AggregateSpotPrices(spotPrices)
+	pricing.spotSavingsByFamily, pricing.overallSpotSavings = overlay.AggregateSpotSavings(pricing.spot, pricing.onDemand)
+
+	return pricing
+}
+
+// savingsPlanPendingLaunches returns the on-demand launches since Lumina collected the Savings
+// Plan data, which are already consuming the commitment. ok is false when Savings Plan analysis
+// should be skipped this cycle because launches can't be costed at all.
+//
+// While on-demand prices are unavailable, launches are costed with the last prices queried:
+// prices change rarely, so they are a much better estimate than leaving launches out.
+// Launches that still have no price are logged and reported with a metric.
+func (r *MetricsReconciler) savingsPlanPendingLaunches(
+	ctx context.Context,
+	spFreshness float64,
+	pricing pricingData,
+) (pendingLaunches, bool) {
+	prices := pricing.onDemand
+	if len(prices) == 0 && len(r.lastOnDemandPrices) > 0 {
+		r.Logger.Info("On-demand prices unavailable, costing launches since the last Lumina refresh with the last known prices")
+		prices = r.lastOnDemandPrices
+	}
+
+	pending := r.queryPendingLaunches(ctx, dataCollectedAt(spFreshness), prices)
+	if r.Metrics != nil {
+		r.Metrics.SetSavingsPlanUnpricedLaunches(pending.unpriced)
+	}
+	if pending.unpriced == 0 {
+		return pending, true
+	}
+
+	// Leaving every launch out would overstate the remaining capacity, so keep the
+	// existing overlays until prices are available
+	if len(prices) == 0 {
+		r.Logger.Info("Skipping Savings Plan analysis: no on-demand prices to cost launches since the last Lumina refresh",
+			"unpriced_launches", pending.unpriced,
+		)
+		return pending, false
+	}
+	r.Logger.Info("Launches since the last Lumina refresh have no known on-demand price and are not counted against Savings Plans",
+		"unpriced_launches", pending.unpriced,
+	)
+	return pending, true
+}
+
 // applyOverlays creates, updates, or deletes NodeOverlay resources based on decisions.
 func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overlay.GeneratedOverlay) {
 	// Track counts by capacity type for metrics
@@ -450,6 +839,7 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 
 	createCount := 0
 	updateCount := 0
+	unchangedCount := 0
 	deleteCount := 0
 	errorCount := 0
 
@@ -478,12 +868,12 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 
 				if errors.IsNotFound(err) {
 					// Create new overlay
-					if err := r.Client.Create(ctx, gen.Overlay); err != nil {
+					if err := applyOverlay(ctx, r.Client, gen.Overlay, nil, forceConflicts(r.Config)); err != nil {
 						r.Logger.Error(err, "Failed to create NodeOverlay",
 							"name", gen.Overlay.Name,
 						)
 						if r.Metrics != nil {
-							r.Metrics.RecordOverlayOperationError(veneermetrics.OperationCreate, veneermetrics.ErrorTypeAPI)
+							r.Metrics.RecordOverlayOperationError(veneermetrics.OperationCreate, applyErrorType(err))
 						}
 						errorCount++
 						continue
@@ -493,6 +883,9 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 					}
 					overlayCounts[capacityType]++
 					createCount++
+					r.recordOverlayState(gen.Decision.Name, true)
+					recordEvent(r.Recorder, gen.Overlay, corev1.EventTypeNormal, EventReasonOverlayCreated,
+						eventActionCreate, "%s", gen.Decision.Reason)
 					r.Logger.Info("Created NodeOverlay",
 						"name", gen.Overlay.Name,
 						"capacity_type", gen.Decision.CapacityType,
@@ -510,24 +903,50 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 					errorCount++
 					continue
 				} else {
-					// Update existing overlay if spec differs
-					// Copy the resource version from existing to allow update
-					gen.Overlay.ResourceVersion = existing.ResourceVersion
-					if err := r.Client.Update(ctx, gen.Overlay); err != nil {
+					overlayCounts[capacityType]++
+					r.recordOverlayState(gen.Decision.Name, true)
+
+					// Skip the write when nothing meaningful changed, so resourceVersion only
+					// moves (and Karpenter only re-evaluates) when the overlay actually differs
+					if !overlayNeedsUpdate(existing, gen.Overlay) {
+						if r.Metrics != nil {
+							r.Metrics.RecordOverlayOperation(veneermetrics.OperationUnchanged, capacityType)
+						}
+						unchangedCount++
+						r.Logger.V(2).Info("NodeOverlay already up to date",
+							"name", gen.Overlay.Name,
+						)
+						continue
+					}
+
+					if err := applyOverlay(ctx, r.Client, gen.Overlay, existing, forceConflicts(r.Config)); err != nil {
 						r.Logger.Error(err, "Failed to update NodeOverlay",
 							"name", gen.Overlay.Name,
 						)
 						if r.Metrics != nil {
-							r.Metrics.RecordOverlayOperationError(veneermetrics.OperationUpdate, veneermetrics.ErrorTypeAPI)
+							r.Metrics.RecordOverlayOperationError(veneermetrics.OperationUpdate, applyErrorType(err))
 						}
+						recordEvent(r.Recorder, existing, corev1.EventTypeWarning, EventReasonOverlayUpdateFailed,
+							eventActionUpdate, "%s", err.Error())
 						errorCount++
 						continue
 					}
+					if !overlayChanged(existing, gen.Overlay) {
+						if r.Metrics != nil {
+							r.Metrics.RecordOverlayOperation(veneermetrics.OperationUnchanged, capacityType)
+						}
+						unchangedCount++
+						r.Logger.V(2).Info("NodeOverlay apply made no changes",
+							"name", gen.Overlay.Name,
+						)
+						continue
+					}
 					if r.Metrics != nil {
 						r.Metrics.RecordOverlayOperation(veneermetrics.OperationUpdate, capacityType)
 					}
-					overlayCounts[capacityType]++
 					updateCount++
+					recordEvent(r.Recorder, gen.Overlay, corev1.EventTypeNormal, EventReasonOverlayUpdated,
+						eventActionUpdate, "%s", gen.Decision.Reason)
 					r.Logger.V(1).Info("Updated NodeOverlay",
 						"name", gen.Overlay.Name,
 						"capacity_type", gen.Decision.CapacityType,
@@ -542,6 +961,7 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 
 			if errors.IsNotFound(err) {
 				// Already gone, nothing to do
+				r.recordOverlayState(gen.Decision.Name, false)
 				r.Logger.V(1).Info("NodeOverlay already deleted",
 					"name", gen.Decision.Name,
 				)
@@ -580,6 +1000,9 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 				r.Metrics.RecordOverlayOperation(veneermetrics.OperationDelete, capacityType)
 			}
 			deleteCount++
+			r.recordOverlayState(gen.Decision.Name, false)
+			recordEvent(r.Recorder, existing, corev1.EventTypeNormal, EventReasonOverlayDeleted,
+				eventActionDelete, "%s", gen.Decision.Reason)
 			r.Logger.Info("Deleted NodeOverlay",
 				"name", gen.Decision.Name,
 				"capacity_type", gen.Decision.CapacityType,
@@ -598,7 +1021,105 @@ func (r *MetricsReconciler) applyOverlays(ctx context.Context, overlays []overla
 	r.Logger.Info("NodeOverlay reconciliation summary",
 		"created", createCount,
 		"updated", updateCount,
+		"unchanged", unchangedCount,
 		"deleted", deleteCount,
 		"errors", errorCount,
 	)
 }
+
+// deleteOrphanedOverlays removes Veneer-managed cost-aware overlays that are not covered
+// by any decision made this cycle.
+//
+// When a Reserved Instance expires or a Savings Plan is retired, Lumina stops emitting its
+// series and no decision is produced, so the overlay would otherwise live forever. This
+// performs a desired-vs-actual sweep over all overlays labelled as managed by Veneer.
+//
+// Only overlays whose capacity type is in analyzedTypes are considered. A capacity type is
+// only analyzed when its data was fresh and all queries succeeded, so stale or missing data
+// keeps existing overlays in place. Preference overlays are owned by the NodePool
+// reconciler and are never touched here.
+func (r *MetricsReconciler) deleteOrphanedOverlays(
+	ctx context.Context,
+	decisions []overlay.Decision,
+	analyzedTypes map[overlay.CapacityType]bool,
+) {
+	if len(analyzedTypes) == 0 {
+		return
+	}
+
+	var overlayList karpenterv1alpha1.NodeOverlayList
+	if err := r.Client.List(ctx, &overlayList, client.MatchingLabels{
+		overlay.LabelManagedBy: overlay.LabelManagedByValue,
+	}); err != nil {
+		r.Logger.Error(err, "Failed to list NodeOverlays for orphan cleanup")
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperationError(veneermetrics.OperationDelete, veneermetrics.ErrorTypeAPI)
+		}
+		return
+	}
+
+	// Every overlay named by a decision is handled by applyOverlays, whether it should exist or not
+	decided := make(map[string]bool, len(decisions))
+	for _, decision := range decisions {
+		decided[decision.Name] = true
+	}
+
+	deleteCount := 0
+	errorCount := 0
+
+	for i := range overlayList.Items {
+		existing := &overlayList.Items[i]
+
+		// Preference overlays are managed by the NodePool reconciler
+		if preference.IsPreferenceOverlay(existing) {
+			continue
+		}
+
+		capacityType := overlay.CapacityTypeFromLabelValue(existing.Labels[overlay.LabelCapacityType])
+		if capacityType == "" || !analyzedTypes[capacityType] {
+			continue
+		}
+
+		if decided[existing.Name] {
+			continue
+		}
+
+		if err := r.Client.Delete(ctx, existing); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			r.Logger.Error(err, "Failed to delete orphaned NodeOverlay",
+				"name", existing.Name,
+			)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperationError(veneermetrics.OperationDelete, veneermetrics.ErrorTypeAPI)
+			}
+			errorCount++
+			continue
+		}
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperation(
+				veneermetrics.OperationDelete,
+				veneermetrics.CapacityTypeFromOverlay(string(capacityType)),
+			)
+		}
+		deleteCount++
+		if r.Hysteresis != nil {
+			r.Hysteresis.Forget(existing.Name)
+		}
+		recordEvent(r.Recorder, existing, corev1.EventTypeNormal, EventReasonOverlayDeleted,
+			eventActionDelete, "backing capacity no longer reported by Lumina")
+		r.Logger.Info("Deleted orphaned NodeOverlay",
+			"name", existing.Name,
+			"capacity_type", capacityType,
+			"reason", "backing capacity no longer reported by Lumina",
+		)
+	}
+
+	if deleteCount > 0 || errorCount > 0 {
+		r.Logger.Info("Orphaned NodeOverlay cleanup summary",
+			"deleted", deleteCount,
+			"errors", errorCount,
+		)
+	}
+}
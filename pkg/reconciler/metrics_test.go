@@ -23,7 +23,23 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/nextdoor/veneer/internal/testutil"
+	"github.com/nextdoor/veneer/pkg/config"
+	veneermetrics "github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/overlay"
+	"github.com/nextdoor/veneer/pkg/preference"
 	"github.com/nextdoor/veneer/pkg/prometheus"
+	promclient "github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 )
 
 func TestMetricsReconciler_Start(t *testing.T) {
@@ -64,6 +80,9 @@ func TestMetricsReconciler_Start(t *testing.T) {
 
 	// Start should run at least twice (once immediately, once on ticker)
 	// If we got here without error, the reconciler ran successfully
+	if reconciler.Status().Time.IsZero() {
+		t.Error("expected Status() to report the last reconcile time")
+	}
 }
 
 func TestMetricsReconciler_StartWithCancel(t *testing.T) {
@@ -289,7 +308,7 @@ func TestMetricsReconciler_DefaultInterval(t *testing.T) {
 	reconciler := &MetricsReconciler{
 		PrometheusClient: client,
 		Logger:           logr.Discard(),
-		// Don't set Interval - should use default
+		// Don't set Interval or PollInterval - should use defaults
 	}
 
 	// Start with short timeout to verify default interval is set
@@ -302,4 +321,848 @@ func TestMetricsReconciler_DefaultInterval(t *testing.T) {
 	if reconciler.Interval != 5*time.Minute {
 		t.Errorf("Expected default interval 5m, got %v", reconciler.Interval)
 	}
+	if reconciler.PollInterval != 30*time.Second {
+		t.Errorf("Expected default poll interval 30s, got %v", reconciler.PollInterval)
+	}
+}
+
+func TestMetricsReconciler_DeleteOrphanedOverlays(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	managedOverlay := func(name, capacityType string) *karpenterv1alpha1.NodeOverlay {
+		return &karpenterv1alpha1.NodeOverlay{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					overlay.LabelManagedBy:    overlay.LabelManagedByValue,
+					overlay.LabelCapacityType: capacityType,
+				},
+			},
+		}
+	}
+
+	existing := []runtime.Object{
+		// Orphaned RI overlay: the RI expired so no decision references it
+		managedOverlay("cost-aware-ri-m5.xlarge-us-west-2", "reserved-instance"),
+		// RI overlay still backed by a decision
+		managedOverlay("cost-aware-ri-c5.large-us-west-2", "reserved-instance"),
+		// Orphaned EC2 Instance SP overlay, but SP data was not analyzed this cycle
+		managedOverlay("cost-aware-ec2-sp-m5-us-west-2", "ec2-instance-savings-plan"),
+		// Preference overlay must never be touched
+		&karpenterv1alpha1.NodeOverlay{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pref-test-pool-1",
+				Labels: map[string]string{
+					preference.LabelManagedBy:      preference.LabelManagedByValue,
+					preference.LabelPreferenceType: preference.LabelPreferenceTypeValue,
+				},
+			},
+		},
+		// Overlay not managed by Veneer
+		&karpenterv1alpha1.NodeOverlay{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "user-overlay",
+				Labels: map[string]string{overlay.LabelCapacityType: "reserved-instance"},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(existing...).Build()
+
+	reconciler := &MetricsReconciler{
+		Client: k8sClient,
+		Logger: logr.Discard(),
+	}
+
+	decisions := []overlay.Decision{
+		{
+			Name:         "cost-aware-ri-c5.large-us-west-2",
+			CapacityType: overlay.CapacityTypeReservedInstance,
+			ShouldExist:  true,
+		},
+	}
+	analyzedTypes := map[overlay.CapacityType]bool{
+		overlay.CapacityTypeReservedInstance: true,
+	}
+
+	reconciler.deleteOrphanedOverlays(context.Background(), decisions, analyzedTypes)
+
+	var overlayList karpenterv1alpha1.NodeOverlayList
+	if err := k8sClient.List(context.Background(), &overlayList); err != nil {
+		t.Fatalf("failed to list overlays: %v", err)
+	}
+
+	remaining := make(map[string]bool)
+	for _, o := range overlayList.Items {
+		remaining[o.Name] = true
+	}
+
+	if remaining["cost-aware-ri-m5.xlarge-us-west-2"] {
+		t.Errorf("expected orphaned RI overlay to be deleted")
+	}
+	for _, name := range []string{
+		"cost-aware-ri-c5.large-us-west-2",
+		"cost-aware-ec2-sp-m5-us-west-2",
+		"pref-test-pool-1",
+		"user-overlay",
+	} {
+		if !remaining[name] {
+			t.Errorf("expected overlay %s to be kept", name)
+		}
+	}
+}
+
+func TestMetricsReconciler_StaleDataKeepsOverlays(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	// Both data types are older than the freshness limit
+	server.SetMetrics(testutil.MetricFixture{
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="savings_plans"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"account_id": "123456789012", "data_type": "savings_plans"},
+					"value": [1640000000, "7200"]
+				}]
+			}
+		}`,
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+					"value": [1640000000, "7200"]
+				}]
+			}
+		}`,
+	})
+
+	promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+
+	existing := &karpenterv1alpha1.NodeOverlay{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cost-aware-ri-m5.xlarge-us-west-2",
+			Labels: map[string]string{
+				overlay.LabelManagedBy:    overlay.LabelManagedByValue,
+				overlay.LabelCapacityType: "reserved-instance",
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	reconciler := &MetricsReconciler{
+		PrometheusClient: promClient,
+		DecisionEngine:   overlay.NewDecisionEngine(&config.Config{}),
+		Generator:        overlay.NewGenerator(),
+		Client:           k8sClient,
+		Logger:           logr.Discard(),
+	}
+
+	if err := reconciler.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile() unexpected error: %v", err)
+	}
+
+	var got karpenterv1alpha1.NodeOverlay
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: existing.Name}, &got); err != nil {
+		t.Errorf("expected overlay to be kept when data is stale, got error: %v", err)
+	}
+
+	for _, dataType := range []prometheus.DataType{prometheus.DataTypeSavingsPlans, prometheus.DataTypeReservedInstances} {
+		freshness, ok := reconciler.Status().DataFreshness[dataType]
+		if !ok || !freshness.Stale {
+			t.Errorf("expected Status() to report %s data as stale, got %+v", dataType, freshness)
+		}
+	}
+}
+
+func TestMetricsReconciler_ConfiguredFreshnessLimits(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	// Two hours old: stale by default, fresh for a Lumina that refreshes less often
+	server.SetMetrics(testutil.MetricFixture{
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+					"value": [1640000000, "7200"]
+				}]
+			}
+		}`,
+	})
+
+	promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+
+	// Lumina no longer reports the RI, so the overlay is removed once RI data is analyzed
+	existing := &karpenterv1alpha1.NodeOverlay{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cost-aware-ri-m5.xlarge-us-west-2",
+			Labels: map[string]string{
+				overlay.LabelManagedBy:    overlay.LabelManagedByValue,
+				overlay.LabelCapacityType: "reserved-instance",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		maxAge    time.Duration
+		wantStale bool
+	}{
+		{name: "default limit", wantStale: true},
+		{name: "raised limit", maxAge: 3 * time.Hour, wantStale: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build()
+
+			cfg := &config.Config{}
+			cfg.Reconcile.MaxFreshness.ReservedInstances = tt.maxAge
+
+			reconciler := &MetricsReconciler{
+				PrometheusClient: promClient,
+				Config:           cfg,
+				DecisionEngine:   overlay.NewDecisionEngine(cfg),
+				Generator:        overlay.NewGenerator(),
+				Client:           k8sClient,
+				Logger:           logr.Discard(),
+			}
+
+			if err := reconciler.reconcile(context.Background()); err != nil {
+				t.Fatalf("reconcile() unexpected error: %v", err)
+			}
+
+			freshness := reconciler.Status().DataFreshness[prometheus.DataTypeReservedInstances]
+			if freshness.Stale != tt.wantStale {
+				t.Errorf("Stale = %v, want %v", freshness.Stale, tt.wantStale)
+			}
+
+			var got karpenterv1alpha1.NodeOverlay
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: existing.Name}, &got)
+			if tt.wantStale && err != nil {
+				t.Errorf("expected overlay to be kept when data is stale, got error: %v", err)
+			}
+			if !tt.wantStale && !apierrors.IsNotFound(err) {
+				t.Errorf("expected orphaned overlay to be deleted, got err=%v", err)
+			}
+		})
+	}
+}
+
+func TestMetricsReconciler_ReservedInstancePricing(t *testing.T) {
+	tests := []struct {
+		name           string
+		fixtures       []testutil.MetricFixture
+		wantPrice      string
+		wantAdjustment string
+	}{
+		{
+			name: "on-demand price available - absolute price",
+			fixtures: []testutil.MetricFixture{
+				testutil.LuminaMetricsWithSPCapacity(),
+				testutil.LuminaMetricsWithRegionalRI(),
+				testutil.LuminaMetricsWithSpotPrices(),
+			},
+			// Linux price wins over Windows: 0.192 * (1 - 0.37)
+			wantPrice: "0.1210",
+		},
+		{
+			name: "on-demand price unavailable - price adjustment",
+			fixtures: []testutil.MetricFixture{
+				testutil.LuminaMetricsWithSPCapacity(),
+				testutil.LuminaMetricsWithRegionalRI(),
+			},
+			wantAdjustment: "-37%",
+		},
+		{
+			name: "on-demand price stale - price adjustment",
+			fixtures: []testutil.MetricFixture{
+				testutil.LuminaMetricsWithSPCapacity(),
+				testutil.LuminaMetricsWithRegionalRI(),
+				testutil.LuminaMetricsWithSpotPrices(),
+				{
+					// Older than the default 25h pricing limit
+					`lumina_data_freshness_seconds{account_id="123456789012", data_type="pricing"}`: `{
+						"status": "success",
+						"data": {
+							"resultType": "vector",
+							"result": [{
+								"metric": {"account_id": "123456789012", "data_type": "pricing"},
+								"value": [1640000000, "100000"]
+							}]
+						}
+					}`,
+				},
+			},
+			wantAdjustment: "-37%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := setupTestScheme(t)
+
+			server := testutil.NewMockPrometheusServer()
+			defer server.Close()
+
+			for _, fixture := range tt.fixtures {
+				server.SetMetrics(fixture)
+			}
+			server.SetMetrics(testutil.MetricFixture{
+				`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+					"status": "success",
+					"data": {
+						"resultType": "vector",
+						"result": [{
+							"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+							"value": [1640000000, "30"]
+						}]
+					}
+				}`,
+			})
+
+			promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cfg := &config.Config{}
+			cfg.Overlays.Weights.ReservedInstance = config.DefaultOverlayWeightReservedInstance
+
+			reconciler := &MetricsReconciler{
+				PrometheusClient: promClient,
+				DecisionEngine:   overlay.NewDecisionEngine(cfg),
+				Generator:        overlay.NewGenerator(),
+				Client:           k8sClient,
+				Logger:           logr.Discard(),
+			}
+
+			if err := reconciler.reconcile(context.Background()); err != nil {
+				t.Fatalf("reconcile() unexpected error: %v", err)
+			}
+
+			var got karpenterv1alpha1.NodeOverlay
+			name := types.NamespacedName{Name: "cost-aware-ri-m5.xlarge-us-west-2"}
+			if err := k8sClient.Get(context.Background(), name, &got); err != nil {
+				t.Fatalf("expected RI overlay to be created: %v", err)
+			}
+
+			gotPrice := ""
+			if got.Spec.Price != nil {
+				gotPrice = *got.Spec.Price
+			}
+			gotAdjustment := ""
+			if got.Spec.PriceAdjustment != nil {
+				gotAdjustment = *got.Spec.PriceAdjustment
+			}
+			if gotPrice != tt.wantPrice {
+				t.Errorf("price = %q, want %q", gotPrice, tt.wantPrice)
+			}
+			if gotAdjustment != tt.wantAdjustment {
+				t.Errorf("priceAdjustment = %q, want %q", gotAdjustment, tt.wantAdjustment)
+			}
+		})
+	}
+}
+
+func TestMetricsReconciler_SpotCheaperSkipsReservedInstanceOverlay(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	server.SetMetrics(
+		testutil.LuminaMetricsWithSPCapacity(),
+		testutil.LuminaMetricsWithRegionalRI(),
+		testutil.LuminaMetricsWithSpotPrices(),
+	)
+	server.SetMetrics(testutil.MetricFixture{
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+					"value": [1640000000, "30"]
+				}]
+			}
+		}`,
+		// Spot at $0.05/hour undercuts the RI-covered price of $0.1210/hour
+		`ec2_spot_price`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"instance_type": "m5.xlarge", "region": "us-west-2", "availability_zone": "us-west-2a"},
+					"value": [1640000000, "0.05"]
+				}]
+			}
+		}`,
+	})
+
+	promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	cfg := &config.Config{}
+	cfg.Overlays.Weights.ReservedInstance = config.DefaultOverlayWeightReservedInstance
+
+	reconciler := &MetricsReconciler{
+		PrometheusClient: promClient,
+		DecisionEngine:   overlay.NewDecisionEngine(cfg),
+		Generator:        overlay.NewGenerator(),
+		Client:           k8sClient,
+		Logger:           logr.Discard(),
+	}
+
+	if err := reconciler.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile() unexpected error: %v", err)
+	}
+
+	var got karpenterv1alpha1.NodeOverlay
+	err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "cost-aware-ri-m5.xlarge-us-west-2"}, &got)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected RI overlay to be skipped when spot is cheaper, got err=%v", err)
+	}
+}
+
+func TestMetricsReconciler_HysteresisHoldsRecentOverlay(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	// Spot undercuts the RI-covered price, so the engine wants the RI overlay deleted
+	server.SetMetrics(
+		testutil.LuminaMetricsWithSPCapacity(),
+		testutil.LuminaMetricsWithRegionalRI(),
+		testutil.LuminaMetricsWithSpotPrices(),
+	)
+	server.SetMetrics(testutil.MetricFixture{
+		`lumina_data_freshness_seconds{account_id="123456789012", data_type="reserved_instances"}`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"account_id": "123456789012", "data_type": "reserved_instances"},
+					"value": [1640000000, "30"]
+				}]
+			}
+		}`,
+		`ec2_spot_price`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"instance_type": "m5.xlarge", "region": "us-west-2", "availability_zone": "us-west-2a"},
+					"value": [1640000000, "0.05"]
+				}]
+			}
+		}`,
+	})
+
+	promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+
+	// Created 5 minutes ago, e.g. before a controller restart
+	existing := &karpenterv1alpha1.NodeOverlay{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cost-aware-ri-m5.xlarge-us-west-2",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+			Labels: map[string]string{
+				overlay.LabelManagedBy:    overlay.LabelManagedByValue,
+				overlay.LabelCapacityType: "reserved-instance",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		minStateDuration time.Duration
+		wantKept         bool
+	}{
+		{name: "within min state duration", minStateDuration: 15 * time.Minute, wantKept: true},
+		{name: "past min state duration", minStateDuration: time.Minute, wantKept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build()
+
+			cfg := &config.Config{}
+			cfg.Overlays.Weights.ReservedInstance = config.DefaultOverlayWeightReservedInstance
+			cfg.Overlays.MinStateDuration = tt.minStateDuration
+
+			reconciler := &MetricsReconciler{
+				PrometheusClient: promClient,
+				Config:           cfg,
+				DecisionEngine:   overlay.NewDecisionEngine(cfg),
+				Generator:        overlay.NewGenerator(),
+				Client:           k8sClient,
+				Logger:           logr.Discard(),
+				Hysteresis:       overlay.NewHysteresisTracker(),
+			}
+
+			if err := reconciler.reconcile(context.Background()); err != nil {
+				t.Fatalf("reconcile() unexpected error: %v", err)
+			}
+
+			var got karpenterv1alpha1.NodeOverlay
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: existing.Name}, &got)
+			if tt.wantKept && err != nil {
+				t.Errorf("expected overlay to be kept by hysteresis, got error: %v", err)
+			}
+			if !tt.wantKept && !apierrors.IsNotFound(err) {
+				t.Errorf("expected overlay to be deleted, got err=%v", err)
+			}
+		})
+	}
+}
+
+func TestMetricsReconciler_HysteresisIgnoresFailedDelete(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	// Spot undercuts the RI-covered price, so the engine wants the RI overlay deleted
+	server.SetMetrics(
+		testutil.LuminaMetricsWithSPCapacity(),
+		testutil.LuminaMetricsWithRegionalRI(),
+		testutil.LuminaMetricsWithSpotPrices(),
+	)
+	server.SetMetrics(freshnessFixture(prometheus.DataTypeReservedInstances, "30"), testutil.MetricFixture{
+		`ec2_spot_price`: `{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"instance_type": "m5.xlarge", "region": "us-west-2", "availability_zone": "us-west-2a"},
+					"value": [1640000000, "0.05"]
+				}]
+			}
+		}`,
+	})
+
+	promClient, _ := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+
+	existing := &karpenterv1alpha1.NodeOverlay{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cost-aware-ri-m5.xlarge-us-west-2",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+			Labels: map[string]string{
+				overlay.LabelManagedBy:    overlay.LabelManagedByValue,
+				overlay.LabelCapacityType: "reserved-instance",
+			},
+		},
+	}
+	deletes := 0
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(existing).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(context.Context, client.WithWatch, client.Object, ...client.DeleteOption) error {
+				deletes++
+				return apierrors.NewServiceUnavailable("unavailable")
+			},
+		}).
+		Build()
+
+	cfg := &config.Config{}
+	cfg.Overlays.Weights.ReservedInstance = config.DefaultOverlayWeightReservedInstance
+	cfg.Overlays.MinStateDuration = 15 * time.Minute
+
+	tracker := overlay.NewHysteresisTracker()
+	reconciler := &MetricsReconciler{
+		PrometheusClient: promClient,
+		Config:           cfg,
+		DecisionEngine:   overlay.NewDecisionEngine(cfg),
+		Generator:        overlay.NewGenerator(),
+		Client:           k8sClient,
+		Logger:           logr.Discard(),
+		Hysteresis:       tracker,
+	}
+
+	if err := reconciler.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile() unexpected error: %v", err)
+	}
+	if deletes != 1 {
+		t.Fatalf("expected 1 delete attempt, got %d", deletes)
+	}
+
+	// The overlay is still there, so keeping it must not be held back by a dwell clock
+	// started by the failed delete
+	got := tracker.Apply(overlay.Decision{Name: existing.Name, ShouldExist: true}, cfg.Overlays.MinStateDuration, time.Now())
+	if !got.ShouldExist || got.Suppressed {
+		t.Errorf("Apply() = (ShouldExist %v, Suppressed %v), want the overlay still recorded as present",
+			got.ShouldExist, got.Suppressed)
+	}
+}
+
+func TestMetricsReconciler_ApplyOverlaysIgnoresForeignSpecFields(t *testing.T) {
+	applies := 0
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithReturnManagedFields().
+		WithInterceptorFuncs(interceptor.Funcs{
+			Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+				applies++
+				return c.Apply(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	cfg := &config.Config{}
+	cfg.Overlays.ForceConflicts = true
+	reconciler := &MetricsReconciler{
+		Config:    cfg,
+		Generator: overlay.NewGenerator(),
+		Client:    k8sClient,
+		Logger:    logr.Discard(),
+		Metrics:   veneermetrics.NewMetrics(promclient.NewRegistry()),
+	}
+
+	decision := overlay.Decision{
+		Name:            "cost-aware-compute-sp-global",
+		CapacityType:    overlay.CapacityTypeComputeSavingsPlan,
+		ShouldExist:     true,
+		Weight:          10,
+		PriceAdjustment: "-28%",
+	}
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+
+	// Another manager adds capacity to the overlay
+	err := applyAs(t, k8sClient, "capacity-admin", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": decision.Name},
+		"spec": map[string]interface{}{
+			"capacity": map[string]interface{}{"example.com/fpga": "1"},
+		},
+	}, false)
+	if err != nil {
+		t.Fatalf("capacity-admin apply failed: %v", err)
+	}
+
+	applies = 0
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+
+	if applies != 0 {
+		t.Errorf("expected no write for a field owned by another manager, got %d applies", applies)
+	}
+	if got := promtestutil.ToFloat64(reconciler.Metrics.OverlayOperationsTotal.WithLabelValues(
+		veneermetrics.OperationUnchanged.String(), veneermetrics.CapacityTypeComputeSP.String())); got != 1 {
+		t.Errorf("expected 1 unchanged operation, got %v", got)
+	}
+	var got karpenterv1alpha1.NodeOverlay
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: decision.Name}, &got); err != nil {
+		t.Fatalf("failed to get overlay: %v", err)
+	}
+	if _, ok := got.Spec.Capacity["example.com/fpga"]; !ok {
+		t.Errorf("expected capacity set by another manager to be kept, got %v", got.Spec.Capacity)
+	}
+}
+
+func TestMetricsReconciler_ApplyOverlaysSkipsUnchanged(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(setupTestScheme(t)).Build()
+	registry := promclient.NewRegistry()
+	reconciler := &MetricsReconciler{
+		Generator: overlay.NewGenerator(),
+		Client:    k8sClient,
+		Logger:    logr.Discard(),
+		Metrics:   veneermetrics.NewMetrics(registry),
+	}
+
+	decision := overlay.Decision{
+		Name:               "cost-aware-compute-sp-global",
+		CapacityType:       overlay.CapacityTypeComputeSavingsPlan,
+		ShouldExist:        true,
+		Weight:             10,
+		PriceAdjustment:    "-28%",
+		UtilizationPercent: 50,
+		Reason:             "utilization 50.0% below threshold 95.0%",
+	}
+
+	getOverlay := func() karpenterv1alpha1.NodeOverlay {
+		t.Helper()
+		var got karpenterv1alpha1.NodeOverlay
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: decision.Name}, &got); err != nil {
+			t.Fatalf("failed to get overlay: %v", err)
+		}
+		return got
+	}
+	operations := func(operation veneermetrics.Operation) float64 {
+		return promtestutil.ToFloat64(reconciler.Metrics.OverlayOperationsTotal.WithLabelValues(
+			operation.String(), veneermetrics.CapacityTypeComputeSP.String()))
+	}
+
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+	created := getOverlay()
+
+	// Only utilization moved, so the overlay must not be rewritten
+	decision.UtilizationPercent = 62.5
+	decision.Reason = "utilization 62.5% below threshold 95.0%"
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+
+	if got := getOverlay(); got.ResourceVersion != created.ResourceVersion {
+		t.Errorf("expected unchanged overlay to keep resourceVersion %s, got %s", created.ResourceVersion, got.ResourceVersion)
+	}
+	if got := operations(veneermetrics.OperationUnchanged); got != 1 {
+		t.Errorf("expected 1 unchanged operation, got %v", got)
+	}
+	if got := operations(veneermetrics.OperationUpdate); got != 0 {
+		t.Errorf("expected no update operations, got %v", got)
+	}
+	if got := promtestutil.ToFloat64(reconciler.Metrics.OverlayCount.WithLabelValues(
+		veneermetrics.CapacityTypeComputeSP.String())); got != 1 {
+		t.Errorf("expected unchanged overlay to be counted, got overlay count %v", got)
+	}
+
+	// A price change is a real difference and is written, refreshing the annotations too
+	decision.PriceAdjustment = "-30%"
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+
+	updated := getOverlay()
+	if updated.ResourceVersion == created.ResourceVersion {
+		t.Error("expected changed overlay to be updated")
+	}
+	if got := updated.Annotations[overlay.AnnotationUtilizationPercent]; got != "62.5" {
+		t.Errorf("expected utilization annotation %q after update, got %q", "62.5", got)
+	}
+	if got := operations(veneermetrics.OperationUpdate); got != 1 {
+		t.Errorf("expected 1 update operation, got %v", got)
+	}
+}
+
+func TestMetricsReconciler_ApplyOverlaysRecordsEvents(t *testing.T) {
+	recorder := events.NewFakeRecorder(10)
+	reconciler := &MetricsReconciler{
+		Generator: overlay.NewGenerator(),
+		Client:    fake.NewClientBuilder().WithScheme(setupTestScheme(t)).Build(),
+		Logger:    logr.Discard(),
+		Recorder:  recorder,
+	}
+
+	decision := overlay.Decision{
+		Name:            "cost-aware-compute-sp-global",
+		CapacityType:    overlay.CapacityTypeComputeSavingsPlan,
+		ShouldExist:     true,
+		Weight:          10,
+		PriceAdjustment: "-28%",
+		Reason:          "utilization 50.0% below threshold 95.0%",
+	}
+	apply := func() {
+		reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+	}
+
+	apply()
+	decision.PriceAdjustment = "-30%"
+	apply()
+	// Unchanged overlays don't record events
+	apply()
+	decision.ShouldExist = false
+	decision.Reason = "utilization 97.0% at or above threshold 95.0%"
+	apply()
+
+	want := []string{
+		"Normal OverlayCreated utilization 50.0% below threshold 95.0%",
+		"Normal OverlayUpdated utilization 50.0% below threshold 95.0%",
+		"Normal OverlayDeleted utilization 97.0% at or above threshold 95.0%",
+	}
+	for _, w := range want {
+		select {
+		case got := <-recorder.Events:
+			if got != w {
+				t.Errorf("event = %q, want %q", got, w)
+			}
+		default:
+			t.Fatalf("missing event %q", w)
+		}
+	}
+	select {
+	case got := <-recorder.Events:
+		t.Errorf("unexpected event %q", got)
+	default:
+	}
+}
+
+func TestMetricsReconciler_ApplyOverlaysNoOpApplyRecordsNoEvent(t *testing.T) {
+	// stale makes Get return an outdated price, as a lagging cache would, so the overlay
+	// looks changed although the apply finds it already up to date. Unlike the API server,
+	// the fake client writes a new resourceVersion on every apply, so the no-op is simulated.
+	stale := false
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil {
+					return err
+				}
+				if nodeOverlay, ok := obj.(*karpenterv1alpha1.NodeOverlay); ok && stale {
+					nodeOverlay.Spec.PriceAdjustment = ptr.To("-20%")
+				}
+				return nil
+			},
+			Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+				if !stale {
+					return c.Apply(ctx, obj, opts...)
+				}
+				applied := obj.(interface {
+					GetName() string
+					SetResourceVersion(string)
+				})
+				current := &karpenterv1alpha1.NodeOverlay{}
+				if err := c.Get(ctx, client.ObjectKey{Name: applied.GetName()}, current); err != nil {
+					return err
+				}
+				applied.SetResourceVersion(current.ResourceVersion)
+				return nil
+			},
+		}).
+		Build()
+
+	recorder := events.NewFakeRecorder(10)
+	reconciler := &MetricsReconciler{
+		Generator: overlay.NewGenerator(),
+		Client:    k8sClient,
+		Logger:    logr.Discard(),
+		Recorder:  recorder,
+		Metrics:   veneermetrics.NewMetrics(promclient.NewRegistry()),
+	}
+
+	decision := overlay.Decision{
+		Name:            "cost-aware-compute-sp-global",
+		CapacityType:    overlay.CapacityTypeComputeSavingsPlan,
+		ShouldExist:     true,
+		Weight:          10,
+		PriceAdjustment: "-28%",
+		Reason:          "utilization 50.0% below threshold 95.0%",
+	}
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+	if got := <-recorder.Events; got != "Normal OverlayCreated utilization 50.0% below threshold 95.0%" {
+		t.Errorf("event = %q, want OverlayCreated", got)
+	}
+
+	stale = true
+	reconciler.applyOverlays(context.Background(), reconciler.Generator.GenerateAll([]overlay.Decision{decision}))
+
+	select {
+	case got := <-recorder.Events:
+		t.Errorf("unexpected event %q for an apply that changed nothing", got)
+	default:
+	}
+	operations := func(operation veneermetrics.Operation) float64 {
+		return promtestutil.ToFloat64(reconciler.Metrics.OverlayOperationsTotal.WithLabelValues(
+			operation.String(), veneermetrics.CapacityTypeComputeSP.String()))
+	}
+	if got := operations(veneermetrics.OperationUpdate); got != 0 {
+		t.Errorf("expected no update operations, got %v", got)
+	}
+	if got := operations(veneermetrics.OperationUnchanged); got != 1 {
+		t.Errorf("expected 1 unchanged operation, got %v", got)
+	}
 }
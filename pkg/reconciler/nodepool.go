@@ -18,15 +18,25 @@ package reconciler
 
 import (
 	"context"
-	"reflect"
+	"strings"
 
 	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/pkg/config"
 	"github.com/nextdoor/veneer/pkg/metrics"
 	"github.com/nextdoor/veneer/pkg/preference"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 )
@@ -53,6 +63,18 @@ type NodePoolReconciler struct {
 
 	// Metrics holds the Prometheus metrics for recording reconciler behavior
 	Metrics *metrics.Metrics
+
+	// Config supplies the controller configuration. Optional: defaults apply when nil.
+	Config config.Provider
+
+	// ConfigReloaded receives an event for every applied config reload, which re-reconciles
+	// every NodePool so changed preference settings take effect.
+	// Optional: reloads aren't watched when nil.
+	ConfigReloaded <-chan event.TypedGenericEvent[*config.Config]
+
+	// Recorder emits Kubernetes Events on NodePools and their preference overlays.
+	// Optional: no events are recorded when nil.
+	Recorder events.EventRecorder
 }
 
 // Reconcile handles NodePool create/update/delete events.
@@ -66,8 +88,10 @@ type NodePoolReconciler struct {
 //   - Find all preference overlays sourced from this NodePool
 //   - Delete them
 //
-// +kubebuilder:rbac:groups=karpenter.sh,resources=nodepools,verbs=get;list;watch
-// +kubebuilder:rbac:groups=karpenter.sh,resources=nodeoverlays,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodepools,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodeoverlays,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Logger.WithValues("nodepool", req.Name)
 
@@ -77,19 +101,49 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		if errors.IsNotFound(err) {
 			// NodePool was deleted - clean up any preference overlays from it
 			log.Info("NodePool deleted, cleaning up preference overlays")
-			return r.cleanupOverlaysForNodePool(ctx, req.Name)
+			return r.cleanupOverlaysForNodePool(ctx, req.Name, "Source NodePool %s deleted")
 		}
 		log.Error(err, "Failed to get NodePool")
 		return ctrl.Result{}, err
 	}
 
-	// Parse preference annotations
-	prefs, parseErrors := preference.ParseNodePoolPreferences(nodePool.Annotations, nodePool.Name)
+	// A NodePool that opted out of preferences keeps no preference overlays or status
+	if preference.PreferencesDisabled(nodePool.Annotations) {
+		log.V(1).Info("Preferences disabled on NodePool, cleaning up preference overlays")
+		result, err := r.cleanupOverlaysForNodePool(ctx, nodePool.Name, "Preferences disabled on NodePool %s")
+		r.updatePreferencesStatus(ctx, log, &nodePool, nil, nil)
+		return result, err
+	}
+
+	// With preferences disabled (preferences.enabled: false), no NodePool keeps preference
+	// overlays or status. The setting is read on every reconcile, and config reloads
+	// re-reconcile every NodePool, so turning it off cleans up without a restart.
+	if !preferencesEnabled(r.Config) {
+		log.V(1).Info("Preferences disabled, cleaning up preference overlays")
+		result, err := r.cleanupOverlaysForNodePool(ctx, nodePool.Name,
+			"Preferences disabled, overlay from NodePool %s removed")
+		r.updatePreferencesStatus(ctx, log, &nodePool, nil, nil)
+		return result, err
+	}
+
+	// Parse preference annotations, treating adjustments outside the configured range as invalid
+	var prefsConfig config.PreferencesConfig
+	if cfg := currentConfig(r.Config); cfg != nil {
+		prefsConfig = cfg.Preferences
+	}
+	prefs, parseErrors := preference.ParseNodePoolPreferences(nodePool.Annotations, nodePool.Name,
+		preference.NewLabelSet(prefsConfig.SupportedLabels))
+	minAdjustment, maxAdjustment := prefsConfig.AdjustmentRange()
+	prefs, rangeErrors := preference.CheckAdjustmentRange(prefs, minAdjustment, maxAdjustment,
+		prefsConfig.AbsoluteAdjustmentLimit())
+	parseErrors = append(parseErrors, rangeErrors...)
 	for _, err := range parseErrors {
 		log.Error(err, "Failed to parse preference annotation")
 		if r.Metrics != nil {
 			r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, metrics.ErrorTypeValidation)
 		}
+		recordEvent(r.Recorder, &nodePool, corev1.EventTypeWarning, EventReasonInvalidPreference, eventActionParse,
+			"%s", preferenceErrorNote(err))
 	}
 
 	// Generate desired overlays from preferences
@@ -106,7 +160,44 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	// Reconcile: create new, update existing, delete stale
-	return r.reconcileOverlays(ctx, log, &nodePool, desiredOverlays, existingOverlays)
+	result, err := r.reconcileOverlays(ctx, log, &nodePool, desiredOverlays, existingOverlays)
+
+	r.updatePreferencesStatus(ctx, log, &nodePool, prefs, parseErrors)
+
+	return result, err
+}
+
+// updatePreferencesStatus writes the parse result of the NodePool's preference annotations
+// to its veneer.io/preferences-status annotation. The annotation is only written when its
+// value changes, and is removed once the NodePool has no preference annotations left.
+//
+// Failures are logged but don't fail the reconcile, since overlays are already in place.
+func (r *NodePoolReconciler) updatePreferencesStatus(
+	ctx context.Context,
+	log logr.Logger,
+	nodePool *karpenterv1.NodePool,
+	prefs []preference.Preference,
+	parseErrors []error,
+) {
+	var value string
+	if len(prefs) > 0 || len(parseErrors) > 0 {
+		encoded, err := preference.NewStatus(nodePool.Name, prefs, parseErrors).Encode()
+		if err != nil {
+			log.Error(err, "Failed to encode preferences status")
+			return
+		}
+		value = encoded
+	}
+
+	if value == nodePool.Annotations[preference.AnnotationPreferencesStatus] {
+		return
+	}
+
+	if err := applyPreferencesStatus(ctx, r.Client, nodePool.Name, value); err != nil {
+		log.Error(err, "Failed to write preferences status annotation")
+		return
+	}
+	log.V(1).Info("Updated preferences status annotation", "status", value)
 }
 
 // listPreferenceOverlaysForNodePool returns all preference overlays generated from a NodePool.
@@ -155,10 +246,10 @@ func (r *NodePoolReconciler) reconcileOverlays(
 
 		if !exists {
 			// Create new overlay
-			if err := r.Create(ctx, desiredOverlay); err != nil {
+			if err := applyOverlay(ctx, r.Client, desiredOverlay, nil, forceConflicts(r.Config)); err != nil {
 				log.Error(err, "Failed to create preference overlay", "overlay", name)
 				if r.Metrics != nil {
-					r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, metrics.ErrorTypeAPI)
+					r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, applyErrorType(err))
 				}
 				errorCount++
 				continue
@@ -167,28 +258,42 @@ func (r *NodePoolReconciler) reconcileOverlays(
 			if r.Metrics != nil {
 				r.Metrics.RecordOverlayOperation(metrics.OperationCreate, metrics.CapacityTypePreference)
 			}
+			recordEvent(r.Recorder, desiredOverlay, corev1.EventTypeNormal, EventReasonOverlayCreated, eventActionCreate,
+				"Created from NodePool %s preference.%s", nodePool.Name, desiredOverlay.Labels[preference.LabelPreferenceNumber])
 			createCount++
 		} else {
 			// Update existing overlay only if spec or labels actually differ
 			if !overlayNeedsUpdate(existingOverlay, desiredOverlay) {
 				log.V(2).Info("Preference overlay already up to date", "overlay", name)
+				if r.Metrics != nil {
+					r.Metrics.RecordOverlayOperation(metrics.OperationUnchanged, metrics.CapacityTypePreference)
+				}
 				continue
 			}
 
-			// Copy resource version to allow update
-			desiredOverlay.ResourceVersion = existingOverlay.ResourceVersion
-			if err := r.Update(ctx, desiredOverlay); err != nil {
+			if err := applyOverlay(ctx, r.Client, desiredOverlay, existingOverlay, forceConflicts(r.Config)); err != nil {
 				log.Error(err, "Failed to update preference overlay", "overlay", name)
 				if r.Metrics != nil {
-					r.Metrics.RecordOverlayOperationError(metrics.OperationUpdate, metrics.ErrorTypeAPI)
+					r.Metrics.RecordOverlayOperationError(metrics.OperationUpdate, applyErrorType(err))
 				}
+				recordEvent(r.Recorder, existingOverlay, corev1.EventTypeWarning, EventReasonOverlayUpdateFailed,
+					eventActionUpdate, "%s", err.Error())
 				errorCount++
 				continue
 			}
+			if !overlayChanged(existingOverlay, desiredOverlay) {
+				log.V(2).Info("Preference overlay apply made no changes", "overlay", name)
+				if r.Metrics != nil {
+					r.Metrics.RecordOverlayOperation(metrics.OperationUnchanged, metrics.CapacityTypePreference)
+				}
+				continue
+			}
 			log.V(1).Info("Updated preference overlay", "overlay", name)
 			if r.Metrics != nil {
 				r.Metrics.RecordOverlayOperation(metrics.OperationUpdate, metrics.CapacityTypePreference)
 			}
+			recordEvent(r.Recorder, desiredOverlay, corev1.EventTypeNormal, EventReasonOverlayUpdated, eventActionUpdate,
+				"Updated from NodePool %s preference.%s", nodePool.Name, desiredOverlay.Labels[preference.LabelPreferenceNumber])
 			updateCount++
 		}
 	}
@@ -208,6 +313,8 @@ func (r *NodePoolReconciler) reconcileOverlays(
 			if r.Metrics != nil {
 				r.Metrics.RecordOverlayOperation(metrics.OperationDelete, metrics.CapacityTypePreference)
 			}
+			recordEvent(r.Recorder, existingOverlay, corev1.EventTypeNormal, EventReasonOverlayDeleted, eventActionDelete,
+				"Preference removed from NodePool %s", nodePool.Name)
 			deleteCount++
 		}
 	}
@@ -222,6 +329,17 @@ func (r *NodePoolReconciler) reconcileOverlays(
 		)
 	}
 
+	// Only report changes, since NodePools reconcile on every update
+	if errorCount > 0 {
+		recordEvent(r.Recorder, nodePool, corev1.EventTypeWarning, EventReasonPreferencesSyncFailed, eventActionSync,
+			"Failed to write %d preference overlays (%d created, %d updated, %d deleted)",
+			errorCount, createCount, updateCount, deleteCount)
+	} else if createCount > 0 || updateCount > 0 || deleteCount > 0 {
+		recordEvent(r.Recorder, nodePool, corev1.EventTypeNormal, EventReasonPreferencesSynced, eventActionSync,
+			"Preference overlays synced: %d created, %d updated, %d deleted",
+			createCount, updateCount, deleteCount)
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -247,9 +365,11 @@ func (r *NodePoolReconciler) setOwnerReferences(
 	}
 }
 
-// cleanupOverlaysForNodePool deletes all preference overlays generated from a deleted NodePool.
+// cleanupOverlaysForNodePool deletes all preference overlays generated from a NodePool that was
+// deleted or opted out of preferences, or when preferences are disabled. noteFormat explains why
+// in the OverlayDeleted event, with the NodePool name as its only argument.
 func (r *NodePoolReconciler) cleanupOverlaysForNodePool(
-	ctx context.Context, nodePoolName string,
+	ctx context.Context, nodePoolName, noteFormat string,
 ) (ctrl.Result, error) {
 	log := r.Logger.WithValues("nodepool", nodePoolName)
 
@@ -276,11 +396,13 @@ func (r *NodePoolReconciler) cleanupOverlaysForNodePool(
 		if r.Metrics != nil {
 			r.Metrics.RecordOverlayOperation(metrics.OperationDelete, metrics.CapacityTypePreference)
 		}
+		recordEvent(r.Recorder, &overlays[i], corev1.EventTypeNormal, EventReasonOverlayDeleted, eventActionDelete,
+			noteFormat, nodePoolName)
 		deleteCount++
 	}
 
 	if deleteCount > 0 || errorCount > 0 {
-		log.Info("Cleaned up preference overlays for deleted NodePool",
+		log.Info("Cleaned up preference overlays for NodePool",
 			"deleted", deleteCount,
 			"errors", errorCount,
 		)
@@ -291,17 +413,62 @@ func (r *NodePoolReconciler) cleanupOverlaysForNodePool(
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NodePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&karpenterv1.NodePool{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&karpenterv1.NodePool{})
+	if r.ConfigReloaded != nil {
+		b = b.WatchesRawSource(source.Channel(r.ConfigReloaded,
+			handler.TypedEnqueueRequestsFromMapFunc(r.allNodePools)))
+	}
+	return b.Complete(r)
 }
 
+// allNodePools maps a config reload to every NodePool.
+func (r *NodePoolReconciler) allNodePools(ctx context.Context, _ *config.Config) []reconcile.Request {
+	var nodePoolList karpenterv1.NodePoolList
+	if err := r.List(ctx, &nodePoolList); err != nil {
+		r.Logger.Error(err, "Failed to list NodePools for config reload")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(nodePoolList.Items))
+	for i := range nodePoolList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: nodePoolList.Items[i].Name},
+		})
+	}
+	return requests
+}
+
+// nodePoolSelectionChanged passes the NodePool events that can change which NodePools a
+// VeneerPreference or cluster-wide preference applies to: creates, deletes, label changes,
+// and changes to the veneer.io/preferences-disabled opt-out.
+var nodePoolSelectionChanged = predicate.Or[client.Object](
+	predicate.LabelChangedPredicate{},
+	predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return preference.PreferencesDisabled(e.ObjectOld.GetAnnotations()) !=
+				preference.PreferencesDisabled(e.ObjectNew.GetAnnotations())
+		},
+	},
+)
+
+// veneerLabelPrefix is the prefix of the labels Veneer owns on the overlays it manages.
+const veneerLabelPrefix = "veneer.io/"
+
 // overlayNeedsUpdate returns true if the existing overlay differs from the desired overlay
 // in any meaningful way (spec or labels). This prevents unnecessary updates that would
 // trigger additional reconciliation loops.
+//
+// Only spec fields Veneer applies are compared (see appliedSpec), so fields set by other
+// field managers never cause an update. Annotations are ignored: they carry volatile context
+// such as utilization that would otherwise force a write every cycle. Veneer-owned labels
+// present on the existing overlay but no longer desired (e.g., from an older Veneer version)
+// do count as a difference.
 func overlayNeedsUpdate(existing, desired *karpenterv1alpha1.NodeOverlay) bool {
-	// Compare specs using reflect.DeepEqual for the full spec comparison
-	if !reflect.DeepEqual(existing.Spec, desired.Spec) {
+	// Compare the spec semantically, so capacity quantities such as "1000m" and "1"
+	// (as returned by the API server) compare equal
+	existingSpec, err := appliedSpec(existing, desired)
+	if err != nil || !equality.Semantic.DeepEqual(existingSpec, desired.Spec) {
 		return true
 	}
 
@@ -312,6 +479,11 @@ func overlayNeedsUpdate(existing, desired *karpenterv1alpha1.NodeOverlay) bool {
 			return true
 		}
 	}
+	for key := range existing.Labels {
+		if _, ok := desired.Labels[key]; !ok && strings.HasPrefix(key, veneerLabelPrefix) {
+			return true
+		}
+	}
 
 	return false
 }
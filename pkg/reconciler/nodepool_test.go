@@ -18,17 +18,27 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/go-logr/logr"
+	veneerv1alpha1 "github.com/nextdoor/veneer/pkg/apis/v1alpha1"
+	"github.com/nextdoor/veneer/pkg/config"
 	"github.com/nextdoor/veneer/pkg/preference"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
 )
@@ -41,10 +51,13 @@ func setupTestScheme(t *testing.T) *runtime.Scheme {
 		t.Fatalf("failed to add core types to scheme: %v", err)
 	}
 
-	// Add Karpenter v1 types (NodePool)
+	// Add Karpenter v1 types (NodePool, NodeClaim)
 	// Karpenter doesn't export SchemeGroupVersion, so we define it manually
 	karpenterv1GV := schema.GroupVersion{Group: "karpenter.sh", Version: "v1"}
-	scheme.AddKnownTypes(karpenterv1GV, &karpenterv1.NodePool{}, &karpenterv1.NodePoolList{})
+	scheme.AddKnownTypes(karpenterv1GV,
+		&karpenterv1.NodePool{}, &karpenterv1.NodePoolList{},
+		&karpenterv1.NodeClaim{}, &karpenterv1.NodeClaimList{},
+	)
 	metav1.AddToGroupVersion(scheme, karpenterv1GV)
 
 	// Add Karpenter v1alpha1 types (NodeOverlay)
@@ -52,6 +65,11 @@ func setupTestScheme(t *testing.T) *runtime.Scheme {
 	scheme.AddKnownTypes(karpenterv1alpha1GV, &karpenterv1alpha1.NodeOverlay{}, &karpenterv1alpha1.NodeOverlayList{})
 	metav1.AddToGroupVersion(scheme, karpenterv1alpha1GV)
 
+	// Add Veneer types (VeneerPreference)
+	if err := veneerv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add Veneer types to scheme: %v", err)
+	}
+
 	return scheme
 }
 
@@ -413,6 +431,151 @@ func TestNodePoolReconciler_Reconcile_InvalidPreference(t *testing.T) {
 	}
 }
 
+func TestNodePoolReconciler_Reconcile_RecordsEvents(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	nodePool := &karpenterv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "events-pool",
+			Annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-20%",
+				"veneer.io/preference.3": "example.com/team=web adjust=-10%",
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nodePool).
+		Build()
+
+	recorder := events.NewFakeRecorder(10)
+	reconciler := &NodePoolReconciler{
+		Client:    client,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+		Recorder:  recorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "events-pool"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		`Warning InvalidPreference preference.3 invalid: unsupported label key "example.com/team": ` +
+			`must be one of the supported Karpenter labels`,
+		"Normal OverlayCreated Created from NodePool events-pool preference.1",
+		"Normal PreferencesSynced Preference overlays synced: 1 created, 0 updated, 0 deleted",
+	}
+	for _, w := range want {
+		select {
+		case got := <-recorder.Events:
+			if got != w {
+				t.Errorf("event = %q, want %q", got, w)
+			}
+		default:
+			t.Fatalf("missing event %q", w)
+		}
+	}
+
+	// A second reconcile changes nothing, so only the parse error is reported again
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(recorder.Events); got != 1 {
+		t.Errorf("expected 1 event after no-op reconcile, got %d", got)
+	}
+}
+
+func TestNodePoolReconciler_Reconcile_PreferencesStatus(t *testing.T) {
+	scheme := setupTestScheme(t)
+	ctx := context.Background()
+
+	nodePool := &karpenterv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "status-pool",
+			Annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-20%",
+				"veneer.io/preference.3": "example.com/team=web adjust=-10%",
+				"team.example.com/owner": "web",
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nodePool).
+		Build()
+
+	reconciler := &NodePoolReconciler{
+		Client:    client,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "status-pool"}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got karpenterv1.NodePool
+	if err := client.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("failed to get NodePool: %v", err)
+	}
+
+	var status preference.Status
+	if err := json.Unmarshal([]byte(got.Annotations[preference.AnnotationPreferencesStatus]), &status); err != nil {
+		t.Fatalf("failed to decode preferences status %q: %v", got.Annotations[preference.AnnotationPreferencesStatus], err)
+	}
+	want := []preference.PreferenceStatus{
+		{Annotation: "veneer.io/preference.1", Number: 1, Valid: true, Overlay: "pref-status-pool-1"},
+		{
+			Annotation: "veneer.io/preference.3",
+			Number:     3,
+			Error:      `unsupported label key "example.com/team": must be one of the supported Karpenter labels`,
+		},
+	}
+	if !reflect.DeepEqual(status.Preferences, want) {
+		t.Errorf("preferences status = %+v, want %+v", status.Preferences, want)
+	}
+	if got.Annotations["team.example.com/owner"] != "web" {
+		t.Errorf("expected other annotations to be preserved, got %v", got.Annotations)
+	}
+
+	// Reconciling again leaves the NodePool alone
+	resourceVersion := got.ResourceVersion
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("failed to get NodePool: %v", err)
+	}
+	if got.ResourceVersion != resourceVersion {
+		t.Errorf("expected unchanged status not to be rewritten, resourceVersion %s -> %s",
+			resourceVersion, got.ResourceVersion)
+	}
+
+	// Removing every preference removes the status
+	delete(got.Annotations, "veneer.io/preference.1")
+	delete(got.Annotations, "veneer.io/preference.3")
+	if err := client.Update(ctx, &got); err != nil {
+		t.Fatalf("failed to update NodePool: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("failed to get NodePool: %v", err)
+	}
+	if value, exists := got.Annotations[preference.AnnotationPreferencesStatus]; exists {
+		t.Errorf("expected preferences status to be removed, got %q", value)
+	}
+	if got.Annotations["team.example.com/owner"] != "web" {
+		t.Errorf("expected other annotations to be preserved, got %v", got.Annotations)
+	}
+}
+
 func TestNodePoolReconciler_Reconcile_DisabledMode(t *testing.T) {
 	scheme := setupTestScheme(t)
 
@@ -726,6 +889,87 @@ func TestOverlayNeedsUpdate(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "stale veneer label on existing should need update",
+			existing: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cost-aware-compute-sp-global",
+					Labels: map[string]string{
+						"managed-by":                    "veneer",
+						"veneer.io/optimization-reason": "utilization-50.0-below-threshold-95.0",
+					},
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					PriceAdjustment: strPtr("-20%"),
+				},
+			},
+			desired: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cost-aware-compute-sp-global",
+					Labels: map[string]string{"managed-by": "veneer"},
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					PriceAdjustment: strPtr("-20%"),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "different annotations should not need update",
+			existing: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cost-aware-compute-sp-global",
+					Labels:      map[string]string{"managed-by": "veneer"},
+					Annotations: map[string]string{"veneer.io/utilization-percent": "50.0"},
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					PriceAdjustment: strPtr("-20%"),
+				},
+			},
+			desired: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cost-aware-compute-sp-global",
+					Labels:      map[string]string{"managed-by": "veneer"},
+					Annotations: map[string]string{"veneer.io/utilization-percent": "62.5"},
+				},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					PriceAdjustment: strPtr("-20%"),
+				},
+			},
+			want: false,
+		},
+		{
+			name: "equivalent capacity quantities should not need update",
+			existing: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{Name: "pref-test-1"},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Capacity: corev1.ResourceList{"example.com/fpga": resource.MustParse("1")},
+				},
+			},
+			desired: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{Name: "pref-test-1"},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Capacity: corev1.ResourceList{"example.com/fpga": resource.MustParse("1000m")},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "different capacity should need update",
+			existing: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{Name: "pref-test-1"},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Capacity: corev1.ResourceList{"example.com/fpga": resource.MustParse("1")},
+				},
+			},
+			desired: &karpenterv1alpha1.NodeOverlay{
+				ObjectMeta: metav1.ObjectMeta{Name: "pref-test-1"},
+				Spec: karpenterv1alpha1.NodeOverlaySpec{
+					Capacity: corev1.ResourceList{"example.com/fpga": resource.MustParse("2")},
+				},
+			},
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -834,3 +1078,326 @@ func strPtr(s string) *string {
 func int32Ptr(i int32) *int32 {
 	return &i
 }
+
+func TestNodePoolReconciler_Reconcile_CapacityPreference(t *testing.T) {
+	nodePool := &karpenterv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fpga-pool",
+			Annotations: map[string]string{
+				"veneer.io/preference.1": "node.kubernetes.io/instance-type=f1.2xlarge capacity=example.com/fpga:1000m",
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(nodePool).
+		WithReturnManagedFields().
+		Build()
+
+	reconciler := &NodePoolReconciler{
+		Client:    c,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "fpga-pool"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var overlay karpenterv1alpha1.NodeOverlay
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "pref-fpga-pool-1"}, &overlay); err != nil {
+		t.Fatalf("failed to get overlay: %v", err)
+	}
+	if q, ok := overlay.Spec.Capacity["example.com/fpga"]; !ok || q.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("expected example.com/fpga capacity 1, got %v", overlay.Spec.Capacity)
+	}
+	if overlay.Spec.PriceAdjustment != nil || overlay.Spec.Price != nil {
+		t.Errorf("expected capacity-only overlay to leave the price unchanged, got %v", overlay.Spec)
+	}
+
+	// Reconciling again must not rewrite the overlay
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var after karpenterv1alpha1.NodeOverlay
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "pref-fpga-pool-1"}, &after); err != nil {
+		t.Fatalf("failed to get overlay: %v", err)
+	}
+	if after.ResourceVersion != overlay.ResourceVersion {
+		t.Errorf("expected overlay not to be updated, ResourceVersion %s -> %s",
+			overlay.ResourceVersion, after.ResourceVersion)
+	}
+}
+
+func TestNodePoolReconciler_Reconcile_PreferencesDisabledAnnotation(t *testing.T) {
+	ctx := context.Background()
+
+	nodePool := &karpenterv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "opt-out-pool",
+			Annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-20%",
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(nodePool).
+		Build()
+
+	reconciler := &NodePoolReconciler{
+		Client:    c,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "opt-out-pool"}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := listOverlaysByName(t, c)["pref-opt-out-pool-1"]; !ok {
+		t.Fatal("expected overlay pref-opt-out-pool-1 to be created")
+	}
+
+	// Opting out removes the overlays and the status annotation, but keeps the preferences
+	var got karpenterv1.NodePool
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("failed to get NodePool: %v", err)
+	}
+	got.Annotations[preference.AnnotationPreferencesDisabled] = "true"
+	if err := c.Update(ctx, &got); err != nil {
+		t.Fatalf("failed to update NodePool: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+		t.Errorf("expected overlays to be deleted, got %v", overlays)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("failed to get NodePool: %v", err)
+	}
+	if value, exists := got.Annotations[preference.AnnotationPreferencesStatus]; exists {
+		t.Errorf("expected preferences status to be removed, got %q", value)
+	}
+	if _, exists := got.Annotations["veneer.io/preference.1"]; !exists {
+		t.Error("expected preference annotation to be left in place")
+	}
+}
+
+func TestNodePoolSelectionChanged(t *testing.T) {
+	nodePool := func(labels, annotations map[string]string) *karpenterv1.NodePool {
+		return &karpenterv1.NodePool{ObjectMeta: metav1.ObjectMeta{
+			Name: "pool", Labels: labels, Annotations: annotations,
+		}}
+	}
+	disabled := map[string]string{preference.AnnotationPreferencesDisabled: "true"}
+
+	tests := []struct {
+		name     string
+		old, new *karpenterv1.NodePool
+		want     bool
+	}{
+		{
+			name: "label change",
+			old:  nodePool(map[string]string{"team": "web"}, nil),
+			new:  nodePool(map[string]string{"team": "api"}, nil),
+			want: true,
+		},
+		{
+			name: "opt-out added",
+			old:  nodePool(nil, nil),
+			new:  nodePool(nil, disabled),
+			want: true,
+		},
+		{
+			name: "opt-out removed",
+			old:  nodePool(nil, disabled),
+			new:  nodePool(nil, nil),
+			want: true,
+		},
+		{
+			name: "other annotation change",
+			old:  nodePool(nil, nil),
+			new:  nodePool(nil, map[string]string{preference.AnnotationPreferencesStatus: "{}"}),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nodePoolSelectionChanged.Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new})
+			if got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodePoolReconciler_Reconcile_ConfigReload(t *testing.T) {
+	ctx := context.Background()
+
+	nodePool := &karpenterv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "reload-pool",
+			Annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-50%",
+			},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(nodePool).
+		Build()
+
+	cfg := &config.Config{
+		PrometheusURL: "http://prometheus:9090",
+		AWS:           config.AWSConfig{AccountID: "123456789012", Region: "us-west-2"},
+		Preferences:   config.PreferencesConfig{Enabled: true},
+	}
+	store := config.NewStore(cfg)
+	reconciler := &NodePoolReconciler{
+		Client:    c,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+		Config:    store,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "reload-pool"}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := listOverlaysByName(t, c)["pref-reload-pool-1"]; !ok {
+		t.Fatal("expected overlay pref-reload-pool-1 to be created")
+	}
+
+	// A reload that narrows the adjustment range invalidates the preference
+	reloaded := cfg.DeepCopy()
+	reloaded.Preferences.MinAdjustment = ptr.To(-25.0)
+	if _, err := store.Update(reloaded); err != nil {
+		t.Fatalf("failed to update config: %v", err)
+	}
+	if requests := reconciler.allNodePools(ctx, store.Current()); len(requests) != 1 || requests[0] != req {
+		t.Fatalf("expected a config reload to enqueue %v, got %v", req, requests)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+		t.Errorf("expected overlay to be deleted after reload, got %v", overlays)
+	}
+}
+
+func TestPreferenceReconcilers_PreferencesDisabledAtRuntime(t *testing.T) {
+	ctx := context.Background()
+
+	nodePool := testLabeledNodePool("platform-a", map[string]string{"team": "platform"})
+	nodePool.Annotations = map[string]string{
+		"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-10%",
+	}
+	costOverlay := &karpenterv1alpha1.NodeOverlay{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cost-aware-ri-m5-xlarge-us-west-2",
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "veneer"},
+		},
+	}
+	vpref := testVeneerPreference()
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(nodePool, costOverlay, vpref).
+		WithStatusSubresource(vpref).
+		Build()
+
+	cfg := &config.Config{
+		PrometheusURL: "http://prometheus:9090",
+		AWS:           config.AWSConfig{AccountID: "123456789012", Region: "us-west-2"},
+		Preferences: config.PreferencesConfig{
+			Enabled:            true,
+			ClusterPreferences: []config.ClusterPreference{testClusterPreference},
+		},
+	}
+	store := config.NewStore(cfg)
+	nodePoolReconciler := &NodePoolReconciler{
+		Client:    c,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+		Config:    store,
+	}
+	veneerPreferenceReconciler := newVeneerPreferenceReconciler(c)
+	veneerPreferenceReconciler.Config = store
+	clusterPreferenceReconciler := newClusterPreferenceReconciler(c)
+	clusterPreferenceReconciler.Config = store
+
+	reconcileAll := func() {
+		t.Helper()
+		if _, err := nodePoolReconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: client.ObjectKeyFromObject(nodePool),
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reconcileVeneerPreference(t, veneerPreferenceReconciler, vpref.Name)
+		reconcileClusterPreferences(t, clusterPreferenceReconciler)
+	}
+	setEnabled := func(enabled bool) {
+		t.Helper()
+		next := cfg.DeepCopy()
+		next.Preferences.Enabled = enabled
+		change, err := store.Update(next)
+		if err != nil {
+			t.Fatalf("failed to update config: %v", err)
+		}
+		if len(change.RestartRequired) != 0 {
+			t.Fatalf("expected preferences.enabled to apply without a restart, got %v", change.RestartRequired)
+		}
+	}
+
+	// Generate preference overlays from all three sources while preferences are enabled
+	reconcileAll()
+	if overlays := listOverlaysByName(t, c); len(overlays) != 4 {
+		t.Fatalf("expected 3 preference overlays and 1 cost overlay, got %v", overlays)
+	}
+
+	// Disabling preferences removes every preference overlay and the NodePool's status
+	setEnabled(false)
+	reconcileAll()
+
+	overlays := listOverlaysByName(t, c)
+	if len(overlays) != 1 {
+		t.Errorf("expected only the cost overlay to remain, got %v", overlays)
+	}
+	if _, ok := overlays[costOverlay.Name]; !ok {
+		t.Errorf("expected cost overlay %s to be left alone", costOverlay.Name)
+	}
+
+	var gotNodePool karpenterv1.NodePool
+	if err := c.Get(ctx, client.ObjectKeyFromObject(nodePool), &gotNodePool); err != nil {
+		t.Fatalf("failed to get NodePool: %v", err)
+	}
+	if value, exists := gotNodePool.Annotations[preference.AnnotationPreferencesStatus]; exists {
+		t.Errorf("expected preferences status to be removed, got %q", value)
+	}
+
+	var gotPref veneerv1alpha1.VeneerPreference
+	if err := c.Get(ctx, client.ObjectKeyFromObject(vpref), &gotPref); err != nil {
+		t.Fatalf("failed to get VeneerPreference: %v", err)
+	}
+	ready := meta.FindStatusCondition(gotPref.Status.Conditions, veneerv1alpha1.ConditionTypeReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != veneerv1alpha1.ReasonPreferencesDisabled {
+		t.Errorf("expected Ready=False with reason %s, got %+v", veneerv1alpha1.ReasonPreferencesDisabled, ready)
+	}
+	if len(gotPref.Status.Overlays) != 0 {
+		t.Errorf("expected no overlays in status, got %v", gotPref.Status.Overlays)
+	}
+
+	// Enabling them again regenerates the overlays
+	setEnabled(true)
+	reconcileAll()
+	if overlays := listOverlaysByName(t, c); len(overlays) != 4 {
+		t.Errorf("expected 3 preference overlays and 1 cost overlay, got %v", overlays)
+	}
+}
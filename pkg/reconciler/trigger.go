@@ -0,0 +1,144 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	veneermetrics "github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/prometheus"
+)
+
+// TriggerPath is the path the metrics reconciler's trigger handler is served on.
+const TriggerPath = "/reconcile"
+
+// triggerMinInterval is the minimum time between reconciles requested over HTTP. Requests
+// within it are rejected, so the endpoint can't be used to reconcile in a loop.
+const triggerMinInterval = 10 * time.Second
+
+// freshnessTriggerDataTypes are the types of Lumina data whose refresh triggers a reconcile:
+// the capacity data overlays are decided from, and the on-demand prices RI overlays use.
+// Spot prices refresh every few seconds, so they are picked up by the next reconcile instead.
+var freshnessTriggerDataTypes = []prometheus.DataType{
+	prometheus.DataTypeSavingsPlans,
+	prometheus.DataTypeReservedInstances,
+	prometheus.DataTypePricing,
+}
+
+// pollDataFreshness queries the age of each type of Lumina data in freshnessTriggerDataTypes
+// and reports whether any of it was refreshed since the last poll, i.e. its age went down.
+// ages holds the ages seen by the last poll and is updated in place; data without a previous
+// age is only recorded.
+//
+// Query failures are logged and leave the previous age in place, so a refresh during an
+// outage is still detected afterwards.
+func (r *MetricsReconciler) pollDataFreshness(ctx context.Context, ages map[prometheus.DataType]float64) bool {
+	refreshed := false
+	for _, dataType := range freshnessTriggerDataTypes {
+		startTime := time.Now()
+		age, err := r.PrometheusClient.DataFreshness(ctx, dataType)
+		duration := time.Since(startTime).Seconds()
+
+		if err != nil {
+			if r.Metrics != nil {
+				r.Metrics.RecordPrometheusQuery(veneermetrics.QueryTypeDataFreshness, duration, 0, err)
+			}
+			r.Logger.V(1).Info("Failed to poll Lumina data freshness", "data_type", dataType, "error", err.Error())
+			continue
+		}
+		if r.Metrics != nil {
+			r.Metrics.RecordPrometheusQuery(veneermetrics.QueryTypeDataFreshness, duration, 1, nil)
+		}
+
+		previous, seen := ages[dataType]
+		ages[dataType] = age
+		if seen && age < previous {
+			r.Logger.Info("Lumina published new data",
+				"data_type", dataType,
+				"age_seconds", age,
+				"previous_age_seconds", previous,
+			)
+			refreshed = true
+		}
+	}
+	return refreshed
+}
+
+// Trigger requests an immediate reconcile. It never blocks: requests made while one is
+// already pending are coalesced into it. The reconcile runs once the reconciler has started,
+// which only happens on the leader when leader election is enabled.
+func (r *MetricsReconciler) Trigger() {
+	select {
+	case r.triggerChannel() <- struct{}{}:
+	default:
+	}
+}
+
+// triggerChannel returns the channel holding a pending Trigger request, creating it if needed.
+func (r *MetricsReconciler) triggerChannel() chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.trigger == nil {
+		r.trigger = make(chan struct{}, 1)
+	}
+	return r.trigger
+}
+
+// allowHTTPTrigger reports whether a reconcile requested over HTTP at now is allowed, i.e.
+// at least triggerMinInterval passed since the last allowed one, and records it if so.
+// Otherwise it returns how long to wait.
+func (r *MetricsReconciler) allowHTTPTrigger(now time.Time) (bool, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if wait := triggerMinInterval - now.Sub(r.lastHTTPTrigger); !r.lastHTTPTrigger.IsZero() && wait > 0 {
+		return false, wait
+	}
+	r.lastHTTPTrigger = now
+	return true, 0
+}
+
+// TriggerHandler returns an HTTP handler that requests an immediate reconcile with Trigger
+// on POST. It responds 202 Accepted without waiting for the reconcile to run, or 429 Too
+// Many Requests with a Retry-After header when a reconcile was requested less than
+// triggerMinInterval ago.
+func (r *MetricsReconciler) TriggerHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if ok, wait := r.allowHTTPTrigger(time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "reconcile requested too recently", http.StatusTooManyRequests)
+			return
+		}
+
+		r.Trigger()
+		r.Logger.V(1).Info("Reconcile requested over HTTP", "remote_addr", req.RemoteAddr)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = fmt.Fprintln(w, "reconcile requested")
+	})
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/internal/testutil"
+	"github.com/nextdoor/veneer/pkg/prometheus"
+)
+
+// freshnessFixture returns a lumina_data_freshness_seconds response for the given data type.
+func freshnessFixture(dataType prometheus.DataType, age string) testutil.MetricFixture {
+	query := fmt.Sprintf(`lumina_data_freshness_seconds{account_id="123456789012", data_type="%s"}`, dataType)
+	return testutil.MetricFixture{
+		query: fmt.Sprintf(`{
+			"status": "success",
+			"data": {
+				"resultType": "vector",
+				"result": [{
+					"metric": {"account_id": "123456789012", "data_type": "%s"},
+					"value": [1640000000, "%s"]
+				}]
+			}
+		}`, dataType, age),
+	}
+}
+
+func TestMetricsReconciler_PollDataFreshness(t *testing.T) {
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	client, err := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+	if err != nil {
+		t.Fatalf("Failed to create Prometheus client: %v", err)
+	}
+	reconciler := &MetricsReconciler{
+		PrometheusClient: client,
+		Logger:           logr.Discard(),
+	}
+	ctx := context.Background()
+	ages := make(map[prometheus.DataType]float64)
+
+	// Pricing data is missing, so only SP and RI ages are recorded
+	server.SetMetrics(
+		freshnessFixture(prometheus.DataTypeSavingsPlans, "600"),
+		freshnessFixture(prometheus.DataTypeReservedInstances, "600"),
+	)
+	if reconciler.pollDataFreshness(ctx, ages) {
+		t.Error("expected the first poll to only record the data ages")
+	}
+	if len(ages) != 2 {
+		t.Errorf("expected 2 recorded ages, got %v", ages)
+	}
+
+	steps := []struct {
+		name          string
+		fixtures      []testutil.MetricFixture
+		wantRefreshed bool
+	}{
+		{
+			name: "data aged",
+			fixtures: []testutil.MetricFixture{
+				freshnessFixture(prometheus.DataTypeSavingsPlans, "630"),
+				freshnessFixture(prometheus.DataTypeReservedInstances, "630"),
+			},
+			wantRefreshed: false,
+		},
+		{
+			name: "savings plans refreshed",
+			fixtures: []testutil.MetricFixture{
+				freshnessFixture(prometheus.DataTypeSavingsPlans, "5"),
+				freshnessFixture(prometheus.DataTypeReservedInstances, "660"),
+			},
+			wantRefreshed: true,
+		},
+		{
+			name: "first pricing age is only recorded",
+			fixtures: []testutil.MetricFixture{
+				freshnessFixture(prometheus.DataTypeSavingsPlans, "35"),
+				freshnessFixture(prometheus.DataTypeReservedInstances, "690"),
+				freshnessFixture(prometheus.DataTypePricing, "3600"),
+			},
+			wantRefreshed: false,
+		},
+		{
+			name: "pricing refreshed",
+			fixtures: []testutil.MetricFixture{
+				freshnessFixture(prometheus.DataTypeSavingsPlans, "65"),
+				freshnessFixture(prometheus.DataTypeReservedInstances, "720"),
+				freshnessFixture(prometheus.DataTypePricing, "10"),
+			},
+			wantRefreshed: true,
+		},
+	}
+
+	for _, step := range steps {
+		server.SetMetrics(step.fixtures...)
+		if got := reconciler.pollDataFreshness(ctx, ages); got != step.wantRefreshed {
+			t.Errorf("%s: pollDataFreshness() = %v, want %v", step.name, got, step.wantRefreshed)
+		}
+	}
+	if ages[prometheus.DataTypeSavingsPlans] != 65 || ages[prometheus.DataTypePricing] != 10 {
+		t.Errorf("expected the latest ages to be recorded, got %v", ages)
+	}
+}
+
+func TestMetricsReconciler_TriggerCoalesces(t *testing.T) {
+	reconciler := &MetricsReconciler{Logger: logr.Discard()}
+
+	reconciler.Trigger()
+	reconciler.Trigger()
+
+	trigger := reconciler.triggerChannel()
+	if len(trigger) != 1 {
+		t.Fatalf("expected 1 pending reconcile request, got %d", len(trigger))
+	}
+	<-trigger
+	if len(trigger) != 0 {
+		t.Errorf("expected no pending reconcile requests, got %d", len(trigger))
+	}
+}
+
+func TestMetricsReconciler_TriggerHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		wantStatus  int
+		wantPending int
+	}{
+		{
+			name:        "POST requests a reconcile",
+			method:      http.MethodPost,
+			wantStatus:  http.StatusAccepted,
+			wantPending: 1,
+		},
+		{
+			name:        "GET is rejected",
+			method:      http.MethodGet,
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantPending: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &MetricsReconciler{Logger: logr.Discard()}
+
+			rec := httptest.NewRecorder()
+			reconciler.TriggerHandler().ServeHTTP(rec, httptest.NewRequest(tt.method, TriggerPath, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Allow = %q, want %q", rec.Header().Get("Allow"), http.MethodPost)
+			}
+			if got := len(reconciler.triggerChannel()); got != tt.wantPending {
+				t.Errorf("expected %d pending reconcile requests, got %d", tt.wantPending, got)
+			}
+		})
+	}
+}
+
+func TestMetricsReconciler_TriggerHandlerRateLimited(t *testing.T) {
+	reconciler := &MetricsReconciler{Logger: logr.Discard()}
+	handler := reconciler.TriggerHandler()
+	post := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, TriggerPath, nil))
+		return rec
+	}
+
+	if rec := post(); rec.Code != http.StatusAccepted {
+		t.Fatalf("first request: status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	<-reconciler.triggerChannel()
+
+	// A second request within triggerMinInterval is rejected and requests no reconcile
+	rec := post()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second request: status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter == "" || retryAfter == "0" {
+		t.Errorf("expected a Retry-After header, got %q", retryAfter)
+	}
+	if got := len(reconciler.triggerChannel()); got != 0 {
+		t.Errorf("expected no pending reconcile requests, got %d", got)
+	}
+
+	// Once triggerMinInterval has passed, requests are accepted again
+	reconciler.mu.Lock()
+	reconciler.lastHTTPTrigger = time.Now().Add(-triggerMinInterval)
+	reconciler.mu.Unlock()
+	if rec := post(); rec.Code != http.StatusAccepted {
+		t.Errorf("request after the interval: status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+}
+
+func TestMetricsReconciler_StartReconcilesOnTrigger(t *testing.T) {
+	server := testutil.NewMockPrometheusServer()
+	defer server.Close()
+
+	server.SetMetrics(
+		testutil.LuminaMetricsWithSPCapacity(),
+		freshnessFixture(prometheus.DataTypeSavingsPlans, "30"),
+		freshnessFixture(prometheus.DataTypeReservedInstances, "30"),
+	)
+
+	client, err := prometheus.NewClient(server.URL, "123456789012", "us-west-2", logr.Discard())
+	if err != nil {
+		t.Fatalf("Failed to create Prometheus client: %v", err)
+	}
+
+	// Neither the ticker nor the freshness poll fire during the test
+	reconciler := &MetricsReconciler{
+		PrometheusClient: client,
+		Logger:           logr.Discard(),
+		Interval:         time.Hour,
+		PollInterval:     time.Hour,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- reconciler.Start(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Start() returned unexpected error: %v", err)
+		}
+	}()
+
+	// waitForReconcileAfter waits for a reconcile to finish after the given time.
+	waitForReconcileAfter := func(after time.Time) time.Time {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if last := reconciler.Status().Time; last.After(after) {
+				return last
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("timed out waiting for a reconcile")
+		return time.Time{}
+	}
+
+	// The initial reconcile runs on startup
+	initial := waitForReconcileAfter(time.Time{})
+
+	reconciler.Trigger()
+	waitForReconcileAfter(initial)
+}
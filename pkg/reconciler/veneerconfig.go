@@ -0,0 +1,475 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	veneerv1alpha1 "github.com/nextdoor/veneer/pkg/apis/v1alpha1"
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/overlay"
+	"github.com/nextdoor/veneer/pkg/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// veneerConfigStatusInterval is how often the VeneerConfig status is refreshed with the
+// latest reconcile time, data freshness, and overlay count.
+const veneerConfigStatusInterval = time.Minute
+
+// veneerConfigRequest is the request for the only VeneerConfig the controller reads.
+var veneerConfigRequest = reconcile.Request{
+	NamespacedName: types.NamespacedName{Name: veneerv1alpha1.VeneerConfigName},
+}
+
+// VeneerConfigReconciler applies the VeneerConfig named default on top of the config file,
+// and reports the configuration in effect in its status.
+//
+// When the VeneerConfig is created or updated, this reconciler:
+// 1. Sets its spec as the overrides of the config store, which validates the merged result
+// 2. Notifies the components that don't read the store of the change
+// 3. Reports the effective configuration, the last metrics reconcile, Lumina data freshness,
+// and the number of managed overlays in its status, refreshed every minute
+//
+// When the VeneerConfig is deleted, the config file applies on its own again.
+//
+// It runs on every replica, since the webhook serves from non-leaders too, but only the
+// leader writes the status. If the VeneerConfig is invalid, the configuration in effect
+// before it changed is kept and the Ready condition reports the error.
+type VeneerConfigReconciler struct {
+	// Client is the Kubernetes client for managing resources
+	client.Client
+
+	// Logger is the structured logger for this reconciler
+	Logger logr.Logger
+
+	// Store is the config store the VeneerConfig's settings are applied to.
+	Store *config.Store
+
+	// OverlaysDisabled is the --overlay-disabled flag, which takes precedence over the
+	// VeneerConfig like it does over the config file.
+	OverlaysDisabled bool
+
+	// OnChange is called after the VeneerConfig changed the configuration in effect.
+	// Optional: nothing is notified when nil.
+	OnChange func(config.Change)
+
+	// ConfigReloaded receives an event for every applied config reload, which refreshes the
+	// status so it shows reloaded config file values. Optional: reloads aren't watched when nil.
+	ConfigReloaded <-chan event.TypedGenericEvent[*config.Config]
+
+	// MetricsReconciler supplies the last reconcile time and Lumina data freshness reported in
+	// the status. Optional: they are omitted when nil.
+	MetricsReconciler *MetricsReconciler
+
+	// Elected is closed when this replica becomes the leader (see manager.Manager.Elected).
+	// Optional: the status is always written when nil.
+	Elected <-chan struct{}
+
+	// Metrics holds the Prometheus metrics for recording rejected configurations.
+	// Optional: no metrics are recorded when nil.
+	Metrics *metrics.Metrics
+
+	// rejectedGeneration is the last VeneerConfig generation reported as invalid, so the
+	// periodic status refresh doesn't report it again.
+	rejectedGeneration int64
+}
+
+// Reconcile handles VeneerConfig create/update/delete events.
+//
+// +kubebuilder:rbac:groups=veneer.io,resources=veneerconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=veneer.io,resources=veneerconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodeoverlays,verbs=get;list;watch
+func (r *VeneerConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Logger.WithValues("veneerconfig", req.Name)
+
+	var vconfig veneerv1alpha1.VeneerConfig
+	if err := r.Get(ctx, req.NamespacedName, &vconfig); err != nil {
+		if errors.IsNotFound(err) {
+			// VeneerConfig was deleted (or never created) - revert to the config file
+			change, err := r.Store.SetOverrides(nil)
+			if err != nil {
+				log.Error(err, "Failed to revert to the config file")
+				return ctrl.Result{}, err
+			}
+			if !change.Empty() {
+				log.Info("VeneerConfig deleted, reverted to the config file")
+				r.notify(change)
+			}
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get VeneerConfig")
+		return ctrl.Result{}, err
+	}
+
+	// An invalid spec is rejected by the store, keeping the configuration currently in effect
+	change, specErr := r.Store.SetOverrides(VeneerConfigOverrides(&vconfig.Spec, r.OverlaysDisabled))
+	if specErr != nil {
+		// The VeneerConfig is requeued periodically, so only report each invalid generation once
+		if r.rejectedGeneration != vconfig.Generation {
+			r.rejectedGeneration = vconfig.Generation
+			log.Error(specErr, "Invalid VeneerConfig, keeping the current configuration")
+			if r.Metrics != nil {
+				r.Metrics.RecordConfigReload(metrics.ResultError)
+			}
+		}
+	} else {
+		r.notify(change)
+	}
+
+	// Non-leaders keep requeueing too, so a new leader starts refreshing the status
+	if r.elected() {
+		if err := r.updateStatus(ctx, &vconfig, specErr); err != nil {
+			log.Error(err, "Failed to update VeneerConfig status")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: veneerConfigStatusInterval}, nil
+}
+
+// notify passes a configuration change to OnChange, unless nothing changed.
+func (r *VeneerConfigReconciler) notify(change config.Change) {
+	if r.OnChange != nil && !change.Empty() {
+		r.OnChange(change)
+	}
+}
+
+// elected reports whether this replica is the leader.
+func (r *VeneerConfigReconciler) elected() bool {
+	if r.Elected == nil {
+		return true
+	}
+	select {
+	case <-r.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// updateStatus reports the configuration in effect, the state of the metrics reconciler, and
+// the Ready condition in the VeneerConfig's status, writing it only when it changed.
+func (r *VeneerConfigReconciler) updateStatus(
+	ctx context.Context,
+	vconfig *veneerv1alpha1.VeneerConfig,
+	specErr error,
+) error {
+	original := vconfig.Status.DeepCopy()
+
+	var overlayList karpenterv1alpha1.NodeOverlayList
+	if err := r.List(ctx, &overlayList, client.MatchingLabels{
+		overlay.LabelManagedBy: overlay.LabelManagedByValue,
+	}); err != nil {
+		return fmt.Errorf("failed to list NodeOverlays: %w", err)
+	}
+
+	vconfig.Status.ObservedGeneration = vconfig.Generation
+	vconfig.Status.Effective = EffectiveVeneerConfig(r.Store.Current())
+	vconfig.Status.RestartRequired = r.Store.RestartRequired()
+	vconfig.Status.OverlayCount = int32(len(overlayList.Items))
+	if r.MetricsReconciler != nil {
+		status := r.MetricsReconciler.Status()
+		if !status.Time.IsZero() {
+			vconfig.Status.LastReconcileTime = ptr.To(metav1.NewTime(status.Time).Rfc3339Copy())
+		}
+		vconfig.Status.DataFreshness = dataFreshnessStatus(status.DataFreshness)
+	}
+
+	condition := metav1.Condition{
+		Type:               veneerv1alpha1.ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             veneerv1alpha1.ReasonApplied,
+		Message:            "Configuration applied",
+		ObservedGeneration: vconfig.Generation,
+	}
+	switch {
+	case specErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = veneerv1alpha1.ReasonInvalidSpec
+		condition.Message = specErr.Error()
+	case len(vconfig.Status.RestartRequired) > 0:
+		condition.Message = fmt.Sprintf("Configuration applied; %s take effect after a restart",
+			strings.Join(vconfig.Status.RestartRequired, ", "))
+	}
+	meta.SetStatusCondition(&vconfig.Status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(original, &vconfig.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, vconfig)
+}
+
+// dataFreshnessStatus converts the metrics reconciler's data freshness to the VeneerConfig
+// status representation, sorted by data type.
+func dataFreshnessStatus(
+	freshness map[prometheus.DataType]DataFreshness,
+) []veneerv1alpha1.DataFreshness {
+	if len(freshness) == 0 {
+		return nil
+	}
+	out := make([]veneerv1alpha1.DataFreshness, 0, len(freshness))
+	for dataType, f := range freshness {
+		out = append(out, veneerv1alpha1.DataFreshness{
+			Type:        string(dataType),
+			CollectedAt: metav1.NewTime(f.CollectedAt).Rfc3339Copy(),
+			Stale:       f.Stale,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
+
+// SetupWithManager sets up the controller with the Manager.
+//
+// Only the VeneerConfig named default is reconciled, and only when its spec changes; status
+// refreshes are requeued. Config reloads reconcile it too.
+func (r *VeneerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&veneerv1alpha1.VeneerConfig{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == veneerv1alpha1.VeneerConfigName
+			}),
+			predicate.GenerationChangedPredicate{},
+		)).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)})
+	if r.ConfigReloaded != nil {
+		b = b.WatchesRawSource(source.Channel(r.ConfigReloaded,
+			handler.TypedEnqueueRequestsFromMapFunc(
+				func(context.Context, *config.Config) []reconcile.Request {
+					return []reconcile.Request{veneerConfigRequest}
+				})))
+	}
+	return b.Complete(r)
+}
+
+// VeneerConfigOverrides returns config store overrides that apply a VeneerConfig spec.
+// overlaysDisabled is the --overlay-disabled flag, which takes precedence over the spec.
+func VeneerConfigOverrides(spec *veneerv1alpha1.VeneerConfigSpec, overlaysDisabled bool) func(*config.Config) {
+	spec = spec.DeepCopy()
+	return func(cfg *config.Config) {
+		applyVeneerConfigSpec(cfg, spec)
+		if overlaysDisabled {
+			cfg.Overlays.Disabled = true
+		}
+	}
+}
+
+// applyVeneerConfigSpec overrides the settings of cfg that are set in spec.
+func applyVeneerConfigSpec(cfg *config.Config, spec *veneerv1alpha1.VeneerConfigSpec) {
+	override(&cfg.PrometheusURL, spec.PrometheusURL)
+	override(&cfg.LogLevel, spec.LogLevel)
+
+	if aws := spec.AWS; aws != nil {
+		override(&cfg.AWS.AccountID, aws.AccountID)
+		override(&cfg.AWS.Region, aws.Region)
+	}
+
+	if rc := spec.Reconcile; rc != nil {
+		overrideDuration(&cfg.Reconcile.Interval, rc.Interval)
+		overrideDuration(&cfg.Reconcile.PollInterval, rc.PollInterval)
+		if f := rc.MaxFreshness; f != nil {
+			overrideDuration(&cfg.Reconcile.MaxFreshness.SavingsPlans, f.SavingsPlans)
+			overrideDuration(&cfg.Reconcile.MaxFreshness.ReservedInstances, f.ReservedInstances)
+			overrideDuration(&cfg.Reconcile.MaxFreshness.SpotPricing, f.SpotPricing)
+			overrideDuration(&cfg.Reconcile.MaxFreshness.Pricing, f.Pricing)
+		}
+	}
+
+	if o := spec.Overlays; o != nil {
+		override(&cfg.Overlays.Disabled, o.Disabled)
+		override(&cfg.Overlays.UtilizationThreshold, o.UtilizationThreshold)
+		overridePtr(&cfg.Overlays.UtilizationCreateThreshold, o.UtilizationCreateThreshold)
+		overridePtr(&cfg.Overlays.UtilizationDeleteThreshold, o.UtilizationDeleteThreshold)
+		overrideDuration(&cfg.Overlays.MinStateDuration, o.MinStateDuration)
+		if w := o.Weights; w != nil {
+			overrideInt(&cfg.Overlays.Weights.ReservedInstance, w.ReservedInstance)
+			overrideInt(&cfg.Overlays.Weights.EC2InstanceSavingsPlan, w.EC2InstanceSavingsPlan)
+			overrideInt(&cfg.Overlays.Weights.ComputeSavingsPlan, w.ComputeSavingsPlan)
+		}
+		if n := o.Naming; n != nil {
+			override(&cfg.Overlays.Naming.ReservedInstancePrefix, n.ReservedInstancePrefix)
+			override(&cfg.Overlays.Naming.EC2InstanceSavingsPlanPrefix, n.EC2InstanceSavingsPlanPrefix)
+			override(&cfg.Overlays.Naming.ComputeSavingsPlanPrefix, n.ComputeSavingsPlanPrefix)
+		}
+		if d := o.Discounts; d != nil {
+			overridePtr(&cfg.Overlays.Discounts.ReservedInstance, d.ReservedInstance)
+			overridePtr(&cfg.Overlays.Discounts.EC2InstanceSavingsPlan, d.EC2InstanceSavingsPlan)
+			overridePtr(&cfg.Overlays.Discounts.ComputeSavingsPlan, d.ComputeSavingsPlan)
+		}
+		override(&cfg.Overlays.ReservedInstanceMatching, o.ReservedInstanceMatching)
+		override(&cfg.Overlays.ForceConflicts, o.ForceConflicts)
+	}
+
+	if p := spec.Preferences; p != nil {
+		override(&cfg.Preferences.Enabled, p.Enabled)
+		overridePtr(&cfg.Preferences.MinAdjustment, p.MinAdjustment)
+		overridePtr(&cfg.Preferences.MaxAdjustment, p.MaxAdjustment)
+		overridePtr(&cfg.Preferences.MaxAbsoluteAdjustment, p.MaxAbsoluteAdjustment)
+		if p.ClusterPreferences != nil {
+			prefs := make([]config.ClusterPreference, 0, len(p.ClusterPreferences))
+			for _, pref := range p.ClusterPreferences {
+				prefs = append(prefs, config.ClusterPreference{
+					Name:             pref.Name,
+					NodePoolSelector: pref.NodePoolSelector,
+					Preference:       pref.Preference,
+					Weight:           pref.Weight,
+				})
+			}
+			cfg.Preferences.ClusterPreferences = prefs
+		}
+		if p.SupportedLabels != nil {
+			cfg.Preferences.SupportedLabels = slices.Clone(p.SupportedLabels)
+		}
+	}
+
+	if wh := spec.Webhook; wh != nil {
+		override(&cfg.Webhook.Enabled, wh.Enabled)
+		overrideInt(&cfg.Webhook.Port, wh.Port)
+		override(&cfg.Webhook.CertDir, wh.CertDir)
+		override(&cfg.Webhook.FailOpen, wh.FailOpen)
+		override(&cfg.Webhook.WarnOnly, wh.WarnOnly)
+	}
+}
+
+// override sets *dst to *src when src is set.
+func override[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// overridePtr sets *dst to a copy of src when src is set, for settings where unset means
+// the default.
+func overridePtr[T any](dst **T, src *T) {
+	if src != nil {
+		*dst = ptr.To(*src)
+	}
+}
+
+// overrideInt sets *dst to *src when src is set.
+func overrideInt(dst *int, src *int32) {
+	if src != nil {
+		*dst = int(*src)
+	}
+}
+
+// overrideDuration sets *dst to *src when src is set.
+func overrideDuration(dst *time.Duration, src *metav1.Duration) {
+	if src != nil {
+		*dst = src.Duration
+	}
+}
+
+// clonePtr returns a copy of p, or nil when p is nil.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	return ptr.To(*p)
+}
+
+// EffectiveVeneerConfig returns cfg as a fully populated VeneerConfig spec, for reporting the
+// configuration in effect.
+func EffectiveVeneerConfig(cfg *config.Config) *veneerv1alpha1.VeneerConfigSpec {
+	spec := &veneerv1alpha1.VeneerConfigSpec{
+		PrometheusURL: ptr.To(cfg.PrometheusURL),
+		LogLevel:      ptr.To(cfg.LogLevel),
+		AWS: &veneerv1alpha1.AWSSettings{
+			AccountID: ptr.To(cfg.AWS.AccountID),
+			Region:    ptr.To(cfg.AWS.Region),
+		},
+		Reconcile: &veneerv1alpha1.ReconcileSettings{
+			Interval:     &metav1.Duration{Duration: cfg.Reconcile.Interval},
+			PollInterval: &metav1.Duration{Duration: cfg.Reconcile.PollInterval},
+			MaxFreshness: &veneerv1alpha1.MaxFreshnessSettings{
+				SavingsPlans:      &metav1.Duration{Duration: cfg.Reconcile.MaxFreshness.SavingsPlans},
+				ReservedInstances: &metav1.Duration{Duration: cfg.Reconcile.MaxFreshness.ReservedInstances},
+				SpotPricing:       &metav1.Duration{Duration: cfg.Reconcile.MaxFreshness.SpotPricing},
+				Pricing:           &metav1.Duration{Duration: cfg.Reconcile.MaxFreshness.Pricing},
+			},
+		},
+		Overlays: &veneerv1alpha1.OverlaySettings{
+			Disabled:                   ptr.To(cfg.Overlays.Disabled),
+			UtilizationThreshold:       ptr.To(cfg.Overlays.UtilizationThreshold),
+			UtilizationCreateThreshold: clonePtr(cfg.Overlays.UtilizationCreateThreshold),
+			UtilizationDeleteThreshold: clonePtr(cfg.Overlays.UtilizationDeleteThreshold),
+			MinStateDuration:           &metav1.Duration{Duration: cfg.Overlays.MinStateDuration},
+			Weights: &veneerv1alpha1.OverlayWeights{
+				ReservedInstance:       ptr.To(int32(cfg.Overlays.Weights.ReservedInstance)),
+				EC2InstanceSavingsPlan: ptr.To(int32(cfg.Overlays.Weights.EC2InstanceSavingsPlan)),
+				ComputeSavingsPlan:     ptr.To(int32(cfg.Overlays.Weights.ComputeSavingsPlan)),
+			},
+			Naming: &veneerv1alpha1.OverlayNaming{
+				ReservedInstancePrefix:       ptr.To(cfg.Overlays.Naming.ReservedInstancePrefix),
+				EC2InstanceSavingsPlanPrefix: ptr.To(cfg.Overlays.Naming.EC2InstanceSavingsPlanPrefix),
+				ComputeSavingsPlanPrefix:     ptr.To(cfg.Overlays.Naming.ComputeSavingsPlanPrefix),
+			},
+			Discounts: &veneerv1alpha1.OverlayDiscounts{
+				ReservedInstance:       clonePtr(cfg.Overlays.Discounts.ReservedInstance),
+				EC2InstanceSavingsPlan: clonePtr(cfg.Overlays.Discounts.EC2InstanceSavingsPlan),
+				ComputeSavingsPlan:     clonePtr(cfg.Overlays.Discounts.ComputeSavingsPlan),
+			},
+			ReservedInstanceMatching: ptr.To(cfg.Overlays.ReservedInstanceMatching),
+			ForceConflicts:           ptr.To(cfg.Overlays.ForceConflicts),
+		},
+		Preferences: &veneerv1alpha1.PreferenceSettings{
+			Enabled:               ptr.To(cfg.Preferences.Enabled),
+			MinAdjustment:         clonePtr(cfg.Preferences.MinAdjustment),
+			MaxAdjustment:         clonePtr(cfg.Preferences.MaxAdjustment),
+			MaxAbsoluteAdjustment: clonePtr(cfg.Preferences.MaxAbsoluteAdjustment),
+			SupportedLabels:       slices.Clone(cfg.Preferences.SupportedLabels),
+		},
+		Webhook: &veneerv1alpha1.WebhookSettings{
+			Enabled:  ptr.To(cfg.Webhook.Enabled),
+			Port:     ptr.To(int32(cfg.Webhook.Port)),
+			CertDir:  ptr.To(cfg.Webhook.CertDir),
+			FailOpen: ptr.To(cfg.Webhook.FailOpen),
+			WarnOnly: ptr.To(cfg.Webhook.WarnOnly),
+		},
+	}
+	for _, pref := range cfg.Preferences.ClusterPreferences {
+		spec.Preferences.ClusterPreferences = append(spec.Preferences.ClusterPreferences,
+			veneerv1alpha1.ClusterPreference{
+				Name:             pref.Name,
+				NodePoolSelector: pref.NodePoolSelector,
+				Preference:       pref.Preference,
+				Weight:           pref.Weight,
+			})
+	}
+	return spec
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	veneerv1alpha1 "github.com/nextdoor/veneer/pkg/apis/v1alpha1"
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/overlay"
+	"github.com/nextdoor/veneer/pkg/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// testVeneerConfigBase returns a valid config file configuration.
+func testVeneerConfigBase() *config.Config {
+	return &config.Config{
+		PrometheusURL: "http://prometheus:9090",
+		AWS: config.AWSConfig{
+			AccountID: "123456789012",
+			Region:    "us-west-2",
+		},
+		Overlays: config.OverlayManagementConfig{
+			UtilizationThreshold: 95,
+			Weights:              config.OverlayWeightsConfig{ReservedInstance: 30},
+		},
+		Preferences: config.PreferencesConfig{Enabled: true},
+	}
+}
+
+func testVeneerConfig(spec veneerv1alpha1.VeneerConfigSpec) *veneerv1alpha1.VeneerConfig {
+	return &veneerv1alpha1.VeneerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: veneerv1alpha1.VeneerConfigName, Generation: 1},
+		Spec:       spec,
+	}
+}
+
+func testManagedOverlay(name string, managed bool) *karpenterv1alpha1.NodeOverlay {
+	nodeOverlay := &karpenterv1alpha1.NodeOverlay{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if managed {
+		nodeOverlay.Labels = map[string]string{overlay.LabelManagedBy: overlay.LabelManagedByValue}
+	}
+	return nodeOverlay
+}
+
+func reconcileVeneerConfig(t *testing.T, r *VeneerConfigReconciler) ctrl.Result {
+	t.Helper()
+	result, err := r.Reconcile(context.Background(), veneerConfigRequest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+func getVeneerConfig(t *testing.T, c client.Client) *veneerv1alpha1.VeneerConfig {
+	t.Helper()
+	var vconfig veneerv1alpha1.VeneerConfig
+	if err := c.Get(context.Background(), veneerConfigRequest.NamespacedName, &vconfig); err != nil {
+		t.Fatalf("failed to get VeneerConfig: %v", err)
+	}
+	return &vconfig
+}
+
+func TestVeneerConfigReconciler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             veneerv1alpha1.VeneerConfigSpec
+		overlaysDisabled bool
+		wantReason       string
+		wantChanged      []string
+		validate         func(*testing.T, *config.Config, *veneerv1alpha1.VeneerConfigStatus)
+	}{
+		{
+			name:       "empty spec keeps the config file",
+			wantReason: veneerv1alpha1.ReasonApplied,
+			validate: func(t *testing.T, cfg *config.Config, status *veneerv1alpha1.VeneerConfigStatus) {
+				if !reflect.DeepEqual(cfg, testVeneerConfigBase()) {
+					t.Errorf("expected the config file configuration, got %+v", cfg)
+				}
+				if got := *status.Effective.Overlays.UtilizationThreshold; got != 95 {
+					t.Errorf("effective utilizationThreshold = %f, want 95", got)
+				}
+			},
+		},
+		{
+			name: "spec overrides the config file",
+			spec: veneerv1alpha1.VeneerConfigSpec{
+				Overlays: &veneerv1alpha1.OverlaySettings{
+					UtilizationThreshold: ptr.To(80.0),
+					MinStateDuration:     &metav1.Duration{Duration: time.Minute},
+					Weights:              &veneerv1alpha1.OverlayWeights{ComputeSavingsPlan: ptr.To(int32(5))},
+				},
+				Preferences: &veneerv1alpha1.PreferenceSettings{
+					ClusterPreferences: []veneerv1alpha1.ClusterPreference{
+						{Name: "prefer-spot", Preference: "karpenter.sh/capacity-type=spot adjust=-10%"},
+					},
+				},
+			},
+			wantReason: veneerv1alpha1.ReasonApplied,
+			wantChanged: []string{
+				"overlays.utilizationThreshold",
+				"overlays.minStateDuration",
+				"overlays.weights.computeSavingsPlan",
+				"preferences.clusterPreferences",
+			},
+			validate: func(t *testing.T, cfg *config.Config, status *veneerv1alpha1.VeneerConfigStatus) {
+				if cfg.Overlays.UtilizationThreshold != 80 || cfg.Overlays.MinStateDuration != time.Minute {
+					t.Errorf("expected overridden overlay settings, got %+v", cfg.Overlays)
+				}
+				if cfg.Overlays.Weights.ReservedInstance != 30 || cfg.Overlays.Weights.ComputeSavingsPlan != 5 {
+					t.Errorf("expected unset weights to keep config file values, got %+v", cfg.Overlays.Weights)
+				}
+				if got := status.Effective.Preferences.ClusterPreferences; len(got) != 1 || got[0].Name != "prefer-spot" {
+					t.Errorf("expected effective cluster preferences to be reported, got %v", got)
+				}
+			},
+		},
+		{
+			name: "startup settings require a restart",
+			spec: veneerv1alpha1.VeneerConfigSpec{
+				AWS: &veneerv1alpha1.AWSSettings{Region: ptr.To("us-east-1")},
+			},
+			wantReason: veneerv1alpha1.ReasonApplied,
+			validate: func(t *testing.T, cfg *config.Config, status *veneerv1alpha1.VeneerConfigStatus) {
+				if cfg.AWS.Region != "us-west-2" {
+					t.Errorf("AWS.Region = %q, want the startup value", cfg.AWS.Region)
+				}
+				if !reflect.DeepEqual(status.RestartRequired, []string{"aws.region"}) {
+					t.Errorf("RestartRequired = %v, want [aws.region]", status.RestartRequired)
+				}
+			},
+		},
+		{
+			name: "invalid spec keeps the current configuration",
+			spec: veneerv1alpha1.VeneerConfigSpec{
+				Overlays:    &veneerv1alpha1.OverlaySettings{UtilizationThreshold: ptr.To(80.0)},
+				Preferences: &veneerv1alpha1.PreferenceSettings{MaxAdjustment: ptr.To(-150.0)},
+			},
+			wantReason: veneerv1alpha1.ReasonInvalidSpec,
+			validate: func(t *testing.T, cfg *config.Config, _ *veneerv1alpha1.VeneerConfigStatus) {
+				if cfg.Overlays.UtilizationThreshold != 95 {
+					t.Errorf("UtilizationThreshold = %f, want the config file value", cfg.Overlays.UtilizationThreshold)
+				}
+			},
+		},
+		{
+			name: "overlay-disabled flag takes precedence",
+			spec: veneerv1alpha1.VeneerConfigSpec{
+				Overlays: &veneerv1alpha1.OverlaySettings{Disabled: ptr.To(false)},
+			},
+			overlaysDisabled: true,
+			wantReason:       veneerv1alpha1.ReasonApplied,
+			wantChanged:      []string{"overlays.disabled"},
+			validate: func(t *testing.T, cfg *config.Config, _ *veneerv1alpha1.VeneerConfigStatus) {
+				if !cfg.Overlays.Disabled {
+					t.Error("expected overlays to stay disabled")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vconfig := testVeneerConfig(tt.spec)
+			c := fake.NewClientBuilder().
+				WithScheme(setupTestScheme(t)).
+				WithObjects(
+					vconfig,
+					testManagedOverlay("cost-aware-ri-m5.xlarge-us-west-2", true),
+					testManagedOverlay("pref-platform-1", true),
+					testManagedOverlay("other-overlay", false),
+				).
+				WithStatusSubresource(vconfig).
+				Build()
+
+			var changed []string
+			store := config.NewStore(testVeneerConfigBase())
+			r := &VeneerConfigReconciler{
+				Client:           c,
+				Logger:           logr.Discard(),
+				Store:            store,
+				OverlaysDisabled: tt.overlaysDisabled,
+				OnChange: func(change config.Change) {
+					changed = append(changed, change.Applied...)
+				},
+			}
+
+			result := reconcileVeneerConfig(t, r)
+			if result.RequeueAfter != veneerConfigStatusInterval {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, veneerConfigStatusInterval)
+			}
+			if len(changed) != len(tt.wantChanged) || (len(changed) > 0 && !reflect.DeepEqual(changed, tt.wantChanged)) {
+				t.Errorf("OnChange applied = %v, want %v", changed, tt.wantChanged)
+			}
+
+			status := getVeneerConfig(t, c).Status
+			ready := meta.FindStatusCondition(status.Conditions, veneerv1alpha1.ConditionTypeReady)
+			if ready == nil || ready.Reason != tt.wantReason {
+				t.Fatalf("expected Ready condition with reason %s, got %+v", tt.wantReason, ready)
+			}
+			if status.ObservedGeneration != vconfig.Generation {
+				t.Errorf("ObservedGeneration = %d, want %d", status.ObservedGeneration, vconfig.Generation)
+			}
+			if status.OverlayCount != 2 {
+				t.Errorf("OverlayCount = %d, want 2 managed overlays", status.OverlayCount)
+			}
+			if tt.validate != nil {
+				tt.validate(t, store.Current(), &status)
+			}
+		})
+	}
+}
+
+func TestVeneerConfigReconciler_Reconcile_Deleted(t *testing.T) {
+	vconfig := testVeneerConfig(veneerv1alpha1.VeneerConfigSpec{
+		Overlays: &veneerv1alpha1.OverlaySettings{UtilizationThreshold: ptr.To(80.0)},
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(vconfig).
+		WithStatusSubresource(vconfig).
+		Build()
+
+	var changes int
+	store := config.NewStore(testVeneerConfigBase())
+	r := &VeneerConfigReconciler{
+		Client:   c,
+		Logger:   logr.Discard(),
+		Store:    store,
+		OnChange: func(config.Change) { changes++ },
+	}
+
+	reconcileVeneerConfig(t, r)
+	if got := store.Current().Overlays.UtilizationThreshold; got != 80 {
+		t.Fatalf("UtilizationThreshold = %f, want 80 from the VeneerConfig", got)
+	}
+
+	if err := c.Delete(context.Background(), vconfig); err != nil {
+		t.Fatalf("failed to delete VeneerConfig: %v", err)
+	}
+	reconcileVeneerConfig(t, r)
+	if got := store.Current().Overlays.UtilizationThreshold; got != 95 {
+		t.Errorf("UtilizationThreshold = %f, want 95 from the config file after deletion", got)
+	}
+	if changes != 2 {
+		t.Errorf("expected OnChange to be called for the override and the revert, got %d calls", changes)
+	}
+}
+
+func TestVeneerConfigReconciler_Reconcile_MetricsStatus(t *testing.T) {
+	vconfig := testVeneerConfig(veneerv1alpha1.VeneerConfigSpec{})
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(vconfig).
+		WithStatusSubresource(vconfig).
+		Build()
+
+	reconciledAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	metricsReconciler := &MetricsReconciler{}
+	metricsReconciler.status = ReconcileStatus{
+		Time: reconciledAt,
+		DataFreshness: map[prometheus.DataType]DataFreshness{
+			prometheus.DataTypeSavingsPlans:      {CollectedAt: reconciledAt.Add(-30 * time.Minute)},
+			prometheus.DataTypeReservedInstances: {CollectedAt: reconciledAt.Add(-2 * time.Hour), Stale: true},
+		},
+	}
+
+	r := &VeneerConfigReconciler{
+		Client:            c,
+		Logger:            logr.Discard(),
+		Store:             config.NewStore(testVeneerConfigBase()),
+		MetricsReconciler: metricsReconciler,
+	}
+	reconcileVeneerConfig(t, r)
+
+	status := getVeneerConfig(t, c).Status
+	if status.LastReconcileTime == nil || !status.LastReconcileTime.Time.Equal(reconciledAt) {
+		t.Errorf("LastReconcileTime = %v, want %s", status.LastReconcileTime, reconciledAt)
+	}
+	want := []veneerv1alpha1.DataFreshness{
+		{Type: "reserved_instances", CollectedAt: metav1.NewTime(reconciledAt.Add(-2 * time.Hour)), Stale: true},
+		{Type: "savings_plans", CollectedAt: metav1.NewTime(reconciledAt.Add(-30 * time.Minute))},
+	}
+	if len(status.DataFreshness) != len(want) {
+		t.Fatalf("DataFreshness = %+v, want %+v", status.DataFreshness, want)
+	}
+	for i := range want {
+		got := status.DataFreshness[i]
+		if got.Type != want[i].Type || !got.CollectedAt.Equal(&want[i].CollectedAt) || got.Stale != want[i].Stale {
+			t.Errorf("DataFreshness[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestVeneerConfigReconciler_Reconcile_NotElected(t *testing.T) {
+	vconfig := testVeneerConfig(veneerv1alpha1.VeneerConfigSpec{
+		Overlays: &veneerv1alpha1.OverlaySettings{UtilizationThreshold: ptr.To(80.0)},
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(vconfig).
+		WithStatusSubresource(vconfig).
+		Build()
+
+	store := config.NewStore(testVeneerConfigBase())
+	r := &VeneerConfigReconciler{
+		Client:  c,
+		Logger:  logr.Discard(),
+		Store:   store,
+		Elected: make(chan struct{}),
+	}
+
+	result := reconcileVeneerConfig(t, r)
+	if got := store.Current().Overlays.UtilizationThreshold; got != 80 {
+		t.Errorf("UtilizationThreshold = %f, want 80: non-leaders must apply the VeneerConfig", got)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("expected non-leaders to requeue, so a new leader refreshes the status")
+	}
+	if status := getVeneerConfig(t, c).Status; status.ObservedGeneration != 0 || len(status.Conditions) != 0 {
+		t.Errorf("expected non-leaders not to write the status, got %+v", status)
+	}
+}
+
+func TestEffectiveVeneerConfig(t *testing.T) {
+	cfg := testVeneerConfigBase()
+	cfg.LogLevel = "debug"
+	cfg.Reconcile.Interval = 10 * time.Minute
+	cfg.Reconcile.PollInterval = time.Minute
+	cfg.Reconcile.MaxFreshness.Pricing = 48 * time.Hour
+	cfg.Overlays.MinStateDuration = 15 * time.Minute
+	cfg.Overlays.Discounts.ComputeSavingsPlan = ptr.To(28.0)
+	cfg.Preferences.SupportedLabels = []string{"team"}
+	cfg.Preferences.ClusterPreferences = []config.ClusterPreference{
+		{Name: "prefer-arm", NodePoolSelector: "team=platform", Preference: "kubernetes.io/arch=arm64 adjust=-10%", Weight: 2},
+	}
+	cfg.Webhook = config.WebhookConfig{Enabled: true, Port: 9443, FailOpen: true}
+
+	// The effective spec sets every field, so applying it to an empty config reproduces cfg
+	got := &config.Config{}
+	applyVeneerConfigSpec(got, EffectiveVeneerConfig(cfg))
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("round trip mismatch:\n got: %+v\nwant: %+v", got, cfg)
+	}
+}
@@ -0,0 +1,546 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	veneerv1alpha1 "github.com/nextdoor/veneer/pkg/apis/v1alpha1"
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/metrics"
+	"github.com/nextdoor/veneer/pkg/preference"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// VeneerPreferenceReconciler watches VeneerPreferences and generates one preference-based
+// NodeOverlay per selected NodePool, using the same generator as NodePool annotations.
+//
+// When a VeneerPreference is created or updated, or a NodePool's labels change, this reconciler:
+// 1. Validates the preference spec
+// 2. Lists the NodePools matching the preference's nodePoolSelector
+// 3. Creates new overlays, updates existing ones, and deletes stale ones
+// 4. Reports the generated overlays and a Ready condition in the preference's status
+//
+// VeneerPreference overlays are labeled with their source VeneerPreference rather than a
+// source NodePool, so they coexist with overlays generated from NodePool annotations.
+type VeneerPreferenceReconciler struct {
+	// Client is the Kubernetes client for managing resources
+	client.Client
+
+	// Logger is the structured logger for this reconciler
+	Logger logr.Logger
+
+	// Generator creates NodeOverlay specs from preferences
+	Generator *preference.Generator
+
+	// Metrics holds the Prometheus metrics for recording reconciler behavior
+	Metrics *metrics.Metrics
+
+	// Config supplies the controller configuration. Optional: defaults apply when nil.
+	Config config.Provider
+
+	// ConfigReloaded receives an event for every applied config reload, which re-reconciles
+	// every VeneerPreference so changed preference settings take effect.
+	// Optional: reloads aren't watched when nil.
+	ConfigReloaded <-chan event.TypedGenericEvent[*config.Config]
+
+	// Recorder emits Kubernetes Events on VeneerPreferences and their overlays.
+	// Optional: no events are recorded when nil.
+	Recorder events.EventRecorder
+}
+
+// Reconcile handles VeneerPreference create/update/delete events.
+//
+// +kubebuilder:rbac:groups=veneer.io,resources=veneerpreferences,verbs=get;list;watch
+// +kubebuilder:rbac:groups=veneer.io,resources=veneerpreferences/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=karpenter.sh,resources=nodeoverlays,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+func (r *VeneerPreferenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Logger.WithValues("veneerpreference", req.Name)
+
+	var vpref veneerv1alpha1.VeneerPreference
+	if err := r.Get(ctx, req.NamespacedName, &vpref); err != nil {
+		if errors.IsNotFound(err) {
+			// VeneerPreference was deleted - clean up its overlays
+			log.Info("VeneerPreference deleted, cleaning up preference overlays")
+			return r.cleanupOverlaysForPreference(ctx, req.Name, "Source VeneerPreference %s deleted")
+		}
+		log.Error(err, "Failed to get VeneerPreference")
+		return ctrl.Result{}, err
+	}
+
+	// With preferences disabled (preferences.enabled: false), the preference keeps no overlays.
+	// Config reloads re-reconcile every VeneerPreference, so this takes effect without a restart.
+	if !preferencesEnabled(r.Config) {
+		log.V(1).Info("Preferences disabled, cleaning up preference overlays")
+		result, err := r.cleanupOverlaysForPreference(ctx, vpref.Name,
+			"Preferences disabled, overlay from VeneerPreference %s removed")
+		if err != nil {
+			return result, err
+		}
+		if err := r.updateStatus(ctx, &vpref, nil, true, nil, 0); err != nil {
+			log.Error(err, "Failed to update VeneerPreference status")
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	// Generate desired overlays, one per selected NodePool. An invalid spec generates none,
+	// so overlays from a previously valid spec are removed. Overlays whose name or labels
+	// would be invalid, e.g. because the names they're built from are too long, are left out.
+	var desiredOverlays []*karpenterv1alpha1.NodeOverlay
+	pref, specErr := r.preferenceFromSpec(&vpref.Spec)
+	if specErr == nil && r.Generator != nil {
+		nodePools, err := r.selectNodePools(ctx, &vpref.Spec.NodePoolSelector)
+		if err != nil {
+			log.Error(err, "Failed to list NodePools")
+			return ctrl.Result{}, err
+		}
+		var invalid []string
+		for _, nodePoolName := range nodePools {
+			nodePoolPref := pref
+			nodePoolPref.NodePoolName = nodePoolName
+			desiredOverlay := r.Generator.GenerateForResource(vpref.Name, nodePoolPref, vpref.Spec.Weight)
+			if err := preference.ValidateOverlay(desiredOverlay); err != nil {
+				invalid = append(invalid, fmt.Sprintf("NodePool %s: %s", nodePoolName, err))
+				continue
+			}
+			desiredOverlays = append(desiredOverlays, desiredOverlay)
+		}
+		if len(invalid) > 0 {
+			specErr = fmt.Errorf("cannot generate NodeOverlays: %s", strings.Join(invalid, "; "))
+		}
+	}
+	if specErr != nil {
+		log.Error(specErr, "Invalid VeneerPreference spec")
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, metrics.ErrorTypeValidation)
+		}
+		recordEvent(r.Recorder, &vpref, corev1.EventTypeWarning, EventReasonInvalidPreference, eventActionParse,
+			"%s", specErr.Error())
+	}
+
+	existingOverlays, err := r.listOverlaysForPreference(ctx, vpref.Name)
+	if err != nil {
+		log.Error(err, "Failed to list existing preference overlays")
+		return ctrl.Result{}, err
+	}
+
+	errorCount := r.reconcileOverlays(ctx, log, &vpref, desiredOverlays, existingOverlays)
+
+	if err := r.updateStatus(ctx, &vpref, desiredOverlays, false, specErr, errorCount); err != nil {
+		log.Error(err, "Failed to update VeneerPreference status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// preferenceFromSpec validates a VeneerPreference spec and converts it to a Preference
+// without a NodePool. Requirements are checked with the same rules as annotation matchers,
+// and the adjustment must be within the configured range.
+func (r *VeneerPreferenceReconciler) preferenceFromSpec(
+	spec *veneerv1alpha1.VeneerPreferenceSpec,
+) (preference.Preference, error) {
+	if _, err := metav1.LabelSelectorAsSelector(&spec.NodePoolSelector); err != nil {
+		return preference.Preference{}, fmt.Errorf("invalid nodePoolSelector: %w", err)
+	}
+	if len(spec.Requirements) == 0 {
+		return preference.Preference{}, fmt.Errorf("at least one requirement is required")
+	}
+
+	var prefsConfig config.PreferencesConfig
+	if cfg := currentConfig(r.Config); cfg != nil {
+		prefsConfig = cfg.Preferences
+	}
+	supportedLabels := preference.NewLabelSet(prefsConfig.SupportedLabels)
+
+	matchers := make([]preference.LabelMatcher, 0, len(spec.Requirements))
+	for i, req := range spec.Requirements {
+		matcher := preference.LabelMatcher{
+			Key:      req.Key,
+			Operator: preference.Operator(req.Operator),
+			Values:   req.Values,
+		}
+		if err := preference.ValidateMatcher(matcher, supportedLabels); err != nil {
+			return preference.Preference{}, fmt.Errorf("requirements[%d]: %w", i, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	pref := preference.Preference{
+		Number:         int(spec.Weight),
+		Matchers:       matchers,
+		Adjustment:     float64(spec.Adjustment),
+		AdjustmentType: preference.AdjustmentPercent,
+	}
+	minAdjustment, maxAdjustment := prefsConfig.AdjustmentRange()
+	if err := pref.CheckAdjustment(minAdjustment, maxAdjustment, prefsConfig.AbsoluteAdjustmentLimit()); err != nil {
+		return preference.Preference{}, err
+	}
+	return pref, nil
+}
+
+// selectNodePools returns the sorted names of the NodePools matching a selector,
+// leaving out NodePools that opted out of preferences.
+func (r *VeneerPreferenceReconciler) selectNodePools(
+	ctx context.Context, selector *metav1.LabelSelector,
+) ([]string, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodePoolList karpenterv1.NodePoolList
+	if err := r.List(ctx, &nodePoolList); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for i := range nodePoolList.Items {
+		if labelSelector.Matches(labels.Set(nodePoolList.Items[i].Labels)) &&
+			!preference.PreferencesDisabled(nodePoolList.Items[i].Annotations) {
+			names = append(names, nodePoolList.Items[i].Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// listOverlaysForPreference returns all overlays generated from a VeneerPreference.
+func (r *VeneerPreferenceReconciler) listOverlaysForPreference(
+	ctx context.Context, name string,
+) ([]karpenterv1alpha1.NodeOverlay, error) {
+	var overlayList karpenterv1alpha1.NodeOverlayList
+	if err := r.List(ctx, &overlayList, client.MatchingLabels{
+		preference.LabelManagedBy:        preference.LabelManagedByValue,
+		preference.LabelPreferenceType:   preference.LabelPreferenceTypeValue,
+		preference.LabelSourcePreference: name,
+	}); err != nil {
+		return nil, err
+	}
+	return overlayList.Items, nil
+}
+
+// reconcileOverlays compares desired vs existing overlays, performs CRUD operations,
+// and returns the number of overlays that could not be written.
+func (r *VeneerPreferenceReconciler) reconcileOverlays(
+	ctx context.Context,
+	log logr.Logger,
+	vpref *veneerv1alpha1.VeneerPreference,
+	desired []*karpenterv1alpha1.NodeOverlay,
+	existing []karpenterv1alpha1.NodeOverlay,
+) int {
+	existingByName := make(map[string]*karpenterv1alpha1.NodeOverlay)
+	for i := range existing {
+		existingByName[existing[i].Name] = &existing[i]
+	}
+
+	desiredByName := make(map[string]*karpenterv1alpha1.NodeOverlay)
+	for _, overlay := range desired {
+		desiredByName[overlay.Name] = overlay
+	}
+
+	var createCount, updateCount, deleteCount, errorCount int
+
+	// Create or update desired overlays
+	for name, desiredOverlay := range desiredByName {
+		existingOverlay, exists := existingByName[name]
+		nodePoolName := desiredOverlay.Labels[preference.LabelTargetNodePool]
+
+		r.setOwnerReferences(desiredOverlay, vpref)
+
+		if !exists {
+			if err := applyOverlay(ctx, r.Client, desiredOverlay, nil, forceConflicts(r.Config)); err != nil {
+				log.Error(err, "Failed to create preference overlay", "overlay", name)
+				if r.Metrics != nil {
+					r.Metrics.RecordOverlayOperationError(metrics.OperationCreate, applyErrorType(err))
+				}
+				errorCount++
+				continue
+			}
+			log.Info("Created preference overlay", "overlay", name, "nodepool", nodePoolName)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperation(metrics.OperationCreate, metrics.CapacityTypePreference)
+			}
+			recordEvent(r.Recorder, desiredOverlay, corev1.EventTypeNormal, EventReasonOverlayCreated, eventActionCreate,
+				"Created from VeneerPreference %s for NodePool %s", vpref.Name, nodePoolName)
+			createCount++
+			continue
+		}
+
+		if !overlayNeedsUpdate(existingOverlay, desiredOverlay) {
+			log.V(2).Info("Preference overlay already up to date", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperation(metrics.OperationUnchanged, metrics.CapacityTypePreference)
+			}
+			continue
+		}
+
+		if err := applyOverlay(ctx, r.Client, desiredOverlay, existingOverlay, forceConflicts(r.Config)); err != nil {
+			log.Error(err, "Failed to update preference overlay", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperationError(metrics.OperationUpdate, applyErrorType(err))
+			}
+			recordEvent(r.Recorder, existingOverlay, corev1.EventTypeWarning, EventReasonOverlayUpdateFailed,
+				eventActionUpdate, "%s", err.Error())
+			errorCount++
+			continue
+		}
+		if !overlayChanged(existingOverlay, desiredOverlay) {
+			log.V(2).Info("Preference overlay apply made no changes", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperation(metrics.OperationUnchanged, metrics.CapacityTypePreference)
+			}
+			continue
+		}
+		log.V(1).Info("Updated preference overlay", "overlay", name, "nodepool", nodePoolName)
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperation(metrics.OperationUpdate, metrics.CapacityTypePreference)
+		}
+		recordEvent(r.Recorder, desiredOverlay, corev1.EventTypeNormal, EventReasonOverlayUpdated, eventActionUpdate,
+			"Updated from VeneerPreference %s for NodePool %s", vpref.Name, nodePoolName)
+		updateCount++
+	}
+
+	// Delete stale overlays: NodePools no longer selected, or an invalid spec
+	for name, existingOverlay := range existingByName {
+		if _, desired := desiredByName[name]; desired {
+			continue
+		}
+		if err := r.Delete(ctx, existingOverlay); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete stale preference overlay", "overlay", name)
+			if r.Metrics != nil {
+				r.Metrics.RecordOverlayOperationError(metrics.OperationDelete, metrics.ErrorTypeAPI)
+			}
+			errorCount++
+			continue
+		}
+		log.Info("Deleted stale preference overlay", "overlay", name)
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperation(metrics.OperationDelete, metrics.CapacityTypePreference)
+		}
+		recordEvent(r.Recorder, existingOverlay, corev1.EventTypeNormal, EventReasonOverlayDeleted, eventActionDelete,
+			"NodePool %s no longer selected by VeneerPreference %s",
+			existingOverlay.Labels[preference.LabelTargetNodePool], vpref.Name)
+		deleteCount++
+	}
+
+	if errorCount > 0 {
+		recordEvent(r.Recorder, vpref, corev1.EventTypeWarning, EventReasonPreferencesSyncFailed, eventActionSync,
+			"Failed to write %d preference overlays (%d created, %d updated, %d deleted)",
+			errorCount, createCount, updateCount, deleteCount)
+	} else if createCount > 0 || updateCount > 0 || deleteCount > 0 {
+		log.Info("Preference overlay reconciliation complete",
+			"created", createCount,
+			"updated", updateCount,
+			"deleted", deleteCount,
+		)
+		recordEvent(r.Recorder, vpref, corev1.EventTypeNormal, EventReasonPreferencesSynced, eventActionSync,
+			"Preference overlays synced: %d created, %d updated, %d deleted",
+			createCount, updateCount, deleteCount)
+	}
+
+	return errorCount
+}
+
+// updateStatus reports the generated overlays and the Ready condition in the
+// VeneerPreference's status, writing it only when it changed. disabled reports that
+// preferences are disabled, so no overlays are generated.
+func (r *VeneerPreferenceReconciler) updateStatus(
+	ctx context.Context,
+	vpref *veneerv1alpha1.VeneerPreference,
+	desired []*karpenterv1alpha1.NodeOverlay,
+	disabled bool,
+	specErr error,
+	errorCount int,
+) error {
+	original := vpref.Status.DeepCopy()
+
+	overlays := make([]veneerv1alpha1.GeneratedOverlay, 0, len(desired))
+	for _, overlay := range desired {
+		overlays = append(overlays, veneerv1alpha1.GeneratedOverlay{
+			Name:     overlay.Name,
+			NodePool: overlay.Labels[preference.LabelTargetNodePool],
+		})
+	}
+	sort.Slice(overlays, func(i, j int) bool { return overlays[i].Name < overlays[j].Name })
+	if len(overlays) == 0 {
+		overlays = nil
+	}
+
+	condition := metav1.Condition{
+		Type:               veneerv1alpha1.ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             veneerv1alpha1.ReasonSynced,
+		Message:            fmt.Sprintf("%d NodeOverlays generated", len(overlays)),
+		ObservedGeneration: vpref.Generation,
+	}
+	switch {
+	case disabled:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = veneerv1alpha1.ReasonPreferencesDisabled
+		condition.Message = "Preferences are disabled in the Veneer configuration (preferences.enabled: false)"
+	case specErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = veneerv1alpha1.ReasonInvalidSpec
+		condition.Message = specErr.Error()
+	case errorCount > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = veneerv1alpha1.ReasonSyncFailed
+		condition.Message = fmt.Sprintf("Failed to write %d NodeOverlays", errorCount)
+	}
+
+	vpref.Status.ObservedGeneration = vpref.Generation
+	vpref.Status.Overlays = overlays
+	meta.SetStatusCondition(&vpref.Status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(original, &vpref.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, vpref)
+}
+
+// setOwnerReferences sets the VeneerPreference as the owner of a NodeOverlay, so overlays
+// are garbage collected when the VeneerPreference is deleted. Both are cluster-scoped.
+func (r *VeneerPreferenceReconciler) setOwnerReferences(
+	overlay *karpenterv1alpha1.NodeOverlay, vpref *veneerv1alpha1.VeneerPreference,
+) {
+	overlay.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: veneerv1alpha1.GroupVersion.String(),
+			Kind:       "VeneerPreference",
+			Name:       vpref.Name,
+			UID:        vpref.UID,
+		},
+	}
+}
+
+// cleanupOverlaysForPreference deletes all overlays generated from a VeneerPreference that was
+// deleted, or when preferences are disabled. noteFormat explains why in the OverlayDeleted
+// event, with the VeneerPreference name as its only argument.
+func (r *VeneerPreferenceReconciler) cleanupOverlaysForPreference(
+	ctx context.Context, name, noteFormat string,
+) (ctrl.Result, error) {
+	log := r.Logger.WithValues("veneerpreference", name)
+
+	overlays, err := r.listOverlaysForPreference(ctx, name)
+	if err != nil {
+		log.Error(err, "Failed to list preference overlays for cleanup")
+		return ctrl.Result{}, err
+	}
+
+	var deleteCount, errorCount int
+	for i := range overlays {
+		if err := r.Delete(ctx, &overlays[i]); err != nil {
+			if !errors.IsNotFound(err) {
+				log.Error(err, "Failed to delete preference overlay during cleanup", "overlay", overlays[i].Name)
+				if r.Metrics != nil {
+					r.Metrics.RecordOverlayOperationError(metrics.OperationDelete, metrics.ErrorTypeAPI)
+				}
+				errorCount++
+			}
+			continue
+		}
+		log.Info("Deleted preference overlay during cleanup", "overlay", overlays[i].Name)
+		if r.Metrics != nil {
+			r.Metrics.RecordOverlayOperation(metrics.OperationDelete, metrics.CapacityTypePreference)
+		}
+		recordEvent(r.Recorder, &overlays[i], corev1.EventTypeNormal, EventReasonOverlayDeleted, eventActionDelete,
+			noteFormat, name)
+		deleteCount++
+	}
+
+	if deleteCount > 0 || errorCount > 0 {
+		log.Info("Cleaned up preference overlays for VeneerPreference",
+			"deleted", deleteCount,
+			"errors", errorCount,
+		)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// preferencesForNodePool maps a NodePool event to every VeneerPreference, since any of
+// them may start or stop selecting the NodePool.
+func (r *VeneerPreferenceReconciler) preferencesForNodePool(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.allPreferences(ctx, "NodePool event")
+}
+
+// preferencesForConfigReload maps a config reload to every VeneerPreference.
+func (r *VeneerPreferenceReconciler) preferencesForConfigReload(
+	ctx context.Context, _ *config.Config,
+) []reconcile.Request {
+	return r.allPreferences(ctx, "config reload")
+}
+
+// allPreferences returns a request for every VeneerPreference. trigger names the event
+// being mapped, for the error log.
+func (r *VeneerPreferenceReconciler) allPreferences(ctx context.Context, trigger string) []reconcile.Request {
+	var vprefList veneerv1alpha1.VeneerPreferenceList
+	if err := r.List(ctx, &vprefList); err != nil {
+		r.Logger.Error(err, "Failed to list VeneerPreferences for "+trigger)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(vprefList.Items))
+	for i := range vprefList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: vprefList.Items[i].Name},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+//
+// NodePool creates, deletes, label changes, and preference opt-outs re-reconcile every
+// VeneerPreference; other NodePool updates (e.g., status) can't change which NodePools are selected.
+// Config reloads re-reconcile every VeneerPreference too.
+func (r *VeneerPreferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&veneerv1alpha1.VeneerPreference{}).
+		Watches(&karpenterv1.NodePool{},
+			handler.EnqueueRequestsFromMapFunc(r.preferencesForNodePool),
+			builder.WithPredicates(nodePoolSelectionChanged))
+	if r.ConfigReloaded != nil {
+		b = b.WatchesRawSource(source.Channel(r.ConfigReloaded,
+			handler.TypedEnqueueRequestsFromMapFunc(r.preferencesForConfigReload)))
+	}
+	return b.Complete(r)
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	veneerv1alpha1 "github.com/nextdoor/veneer/pkg/apis/v1alpha1"
+	"github.com/nextdoor/veneer/pkg/preference"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	karpenterv1alpha1 "sigs.k8s.io/karpenter/pkg/apis/v1alpha1"
+)
+
+// testVeneerPreference returns a VeneerPreference selecting NodePools labeled team=platform.
+func testVeneerPreference() *veneerv1alpha1.VeneerPreference {
+	return &veneerv1alpha1.VeneerPreference{
+		ObjectMeta: metav1.ObjectMeta{Name: "prefer-graviton", Generation: 1},
+		Spec: veneerv1alpha1.VeneerPreferenceSpec{
+			NodePoolSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+			Requirements: []veneerv1alpha1.LabelMatcher{
+				{Key: preference.LabelInstanceFamily, Operator: "In", Values: []string{"m7g", "c7g"}},
+				{Key: preference.LabelArch, Operator: "In", Values: []string{"arm64"}},
+			},
+			Adjustment: -20,
+			Weight:     5,
+		},
+	}
+}
+
+func testLabeledNodePool(name string, labels map[string]string) *karpenterv1.NodePool {
+	return &karpenterv1.NodePool{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newVeneerPreferenceReconciler(c client.Client) *VeneerPreferenceReconciler {
+	return &VeneerPreferenceReconciler{
+		Client:    c,
+		Logger:    logr.Discard(),
+		Generator: preference.NewGenerator(),
+	}
+}
+
+func reconcileVeneerPreference(t *testing.T, r *VeneerPreferenceReconciler, name string) {
+	t.Helper()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func listOverlaysByName(t *testing.T, c client.Client) map[string]*karpenterv1alpha1.NodeOverlay {
+	t.Helper()
+	var overlayList karpenterv1alpha1.NodeOverlayList
+	if err := c.List(context.Background(), &overlayList); err != nil {
+		t.Fatalf("failed to list overlays: %v", err)
+	}
+	overlays := make(map[string]*karpenterv1alpha1.NodeOverlay, len(overlayList.Items))
+	for i := range overlayList.Items {
+		overlays[overlayList.Items[i].Name] = &overlayList.Items[i]
+	}
+	return overlays
+}
+
+func TestVeneerPreferenceReconciler_Reconcile_CreateOverlays(t *testing.T) {
+	vpref := testVeneerPreference()
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(
+			vpref,
+			testLabeledNodePool("platform-a", map[string]string{"team": "platform"}),
+			testLabeledNodePool("platform-b", map[string]string{"team": "platform"}),
+			testLabeledNodePool("batch", map[string]string{"team": "data"}),
+		).
+		WithStatusSubresource(vpref).
+		Build()
+
+	reconcileVeneerPreference(t, newVeneerPreferenceReconciler(c), vpref.Name)
+
+	overlays := listOverlaysByName(t, c)
+	if len(overlays) != 2 {
+		t.Fatalf("expected 2 overlays, got %d: %v", len(overlays), overlays)
+	}
+	for _, nodePool := range []string{"platform-a", "platform-b"} {
+		name := preference.OverlayNameForResource(vpref.Name, nodePool)
+		overlay, ok := overlays[name]
+		if !ok {
+			t.Errorf("expected overlay %s to be created", name)
+			continue
+		}
+		if *overlay.Spec.Weight != 5 {
+			t.Errorf("overlay %s: expected weight 5, got %d", name, *overlay.Spec.Weight)
+		}
+		if *overlay.Spec.PriceAdjustment != "-20%" {
+			t.Errorf("overlay %s: expected price adjustment -20%%, got %s", name, *overlay.Spec.PriceAdjustment)
+		}
+		if overlay.Labels[preference.LabelSourcePreference] != vpref.Name {
+			t.Errorf("overlay %s: expected source-preference label %s, got %v", name, vpref.Name, overlay.Labels)
+		}
+		if len(overlay.OwnerReferences) != 1 || overlay.OwnerReferences[0].Kind != "VeneerPreference" {
+			t.Errorf("overlay %s: expected VeneerPreference owner reference, got %v", name, overlay.OwnerReferences)
+		}
+	}
+
+	var got veneerv1alpha1.VeneerPreference
+	if err := c.Get(context.Background(), types.NamespacedName{Name: vpref.Name}, &got); err != nil {
+		t.Fatalf("failed to get VeneerPreference: %v", err)
+	}
+	if len(got.Status.Overlays) != 2 || got.Status.Overlays[0].NodePool != "platform-a" ||
+		got.Status.Overlays[1].NodePool != "platform-b" {
+		t.Errorf("unexpected status overlays: %v", got.Status.Overlays)
+	}
+	if got.Status.ObservedGeneration != 1 {
+		t.Errorf("expected observed generation 1, got %d", got.Status.ObservedGeneration)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, veneerv1alpha1.ConditionTypeReady) {
+		t.Errorf("expected Ready condition to be true, got %v", got.Status.Conditions)
+	}
+}
+
+func TestVeneerPreferenceReconciler_Reconcile_SideBySideWithAnnotations(t *testing.T) {
+	vpref := testVeneerPreference()
+	nodePool := testLabeledNodePool("platform-a", map[string]string{"team": "platform"})
+	nodePool.Annotations = map[string]string{
+		"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7a adjust=-10%",
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(vpref, nodePool).
+		WithStatusSubresource(vpref).
+		Build()
+
+	reconcileVeneerPreference(t, newVeneerPreferenceReconciler(c), vpref.Name)
+
+	nodePoolReconciler := &NodePoolReconciler{Client: c, Logger: logr.Discard(), Generator: preference.NewGenerator()}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: nodePool.Name}}
+	if _, err := nodePoolReconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Reconciling again must not delete the other source's overlays
+	reconcileVeneerPreference(t, newVeneerPreferenceReconciler(c), vpref.Name)
+
+	overlays := listOverlaysByName(t, c)
+	for _, name := range []string{"pref-platform-a-1", preference.OverlayNameForResource(vpref.Name, "platform-a")} {
+		if _, ok := overlays[name]; !ok {
+			t.Errorf("expected overlay %s to exist, got %v", name, overlays)
+		}
+	}
+}
+
+func TestVeneerPreferenceReconciler_Reconcile_DeletesUnselectedOverlays(t *testing.T) {
+	vpref := testVeneerPreference()
+	nodePool := testLabeledNodePool("platform-a", map[string]string{"team": "platform"})
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(vpref, nodePool).
+		WithStatusSubresource(vpref).
+		Build()
+	r := newVeneerPreferenceReconciler(c)
+
+	reconcileVeneerPreference(t, r, vpref.Name)
+	if len(listOverlaysByName(t, c)) != 1 {
+		t.Fatal("expected 1 overlay after initial reconcile")
+	}
+
+	// The NodePool moves to another team
+	nodePool.Labels = map[string]string{"team": "data"}
+	if err := c.Update(context.Background(), nodePool); err != nil {
+		t.Fatalf("failed to update NodePool: %v", err)
+	}
+
+	reconcileVeneerPreference(t, r, vpref.Name)
+	if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+		t.Errorf("expected overlay to be deleted, got %v", overlays)
+	}
+}
+
+func TestVeneerPreferenceReconciler_Reconcile_InvalidSpec(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*veneerv1alpha1.VeneerPreferenceSpec)
+	}{
+		{
+			name: "unsupported label key",
+			mutate: func(spec *veneerv1alpha1.VeneerPreferenceSpec) {
+				spec.Requirements[0].Key = "example.com/team"
+			},
+		},
+		{
+			name: "non-numeric Gt value",
+			mutate: func(spec *veneerv1alpha1.VeneerPreferenceSpec) {
+				spec.Requirements[0] = veneerv1alpha1.LabelMatcher{
+					Key: preference.LabelInstanceCPU, Operator: "Gt", Values: []string{"large"},
+				}
+			},
+		},
+		{
+			name: "adjustment outside the configured range",
+			mutate: func(spec *veneerv1alpha1.VeneerPreferenceSpec) {
+				spec.Adjustment = -150
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vpref := testVeneerPreference()
+			c := fake.NewClientBuilder().
+				WithScheme(setupTestScheme(t)).
+				WithObjects(vpref, testLabeledNodePool("platform-a", map[string]string{"team": "platform"})).
+				WithStatusSubresource(vpref).
+				Build()
+			r := newVeneerPreferenceReconciler(c)
+
+			// A valid spec generates an overlay, which an invalid spec then removes
+			reconcileVeneerPreference(t, r, vpref.Name)
+
+			var current veneerv1alpha1.VeneerPreference
+			if err := c.Get(context.Background(), types.NamespacedName{Name: vpref.Name}, &current); err != nil {
+				t.Fatalf("failed to get VeneerPreference: %v", err)
+			}
+			tt.mutate(&current.Spec)
+			if err := c.Update(context.Background(), &current); err != nil {
+				t.Fatalf("failed to update VeneerPreference: %v", err)
+			}
+
+			reconcileVeneerPreference(t, r, vpref.Name)
+
+			if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+				t.Errorf("expected no overlays for an invalid spec, got %v", overlays)
+			}
+			var got veneerv1alpha1.VeneerPreference
+			if err := c.Get(context.Background(), types.NamespacedName{Name: vpref.Name}, &got); err != nil {
+				t.Fatalf("failed to get VeneerPreference: %v", err)
+			}
+			ready := meta.FindStatusCondition(got.Status.Conditions, veneerv1alpha1.ConditionTypeReady)
+			if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != veneerv1alpha1.ReasonInvalidSpec {
+				t.Errorf("expected Ready=False with reason %s, got %v", veneerv1alpha1.ReasonInvalidSpec, ready)
+			}
+			if len(got.Status.Overlays) != 0 {
+				t.Errorf("expected no status overlays, got %v", got.Status.Overlays)
+			}
+		})
+	}
+}
+
+func TestVeneerPreferenceReconciler_Reconcile_OverlayNameTooLong(t *testing.T) {
+	vpref := testVeneerPreference()
+	longNodePool := strings.Repeat("a", 250)
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(
+			vpref,
+			testLabeledNodePool("platform-a", map[string]string{"team": "platform"}),
+			testLabeledNodePool(longNodePool, map[string]string{"team": "platform"}),
+		).
+		WithStatusSubresource(vpref).
+		Build()
+
+	reconcileVeneerPreference(t, newVeneerPreferenceReconciler(c), vpref.Name)
+
+	// The overlay for the NodePool with a usable name is still generated
+	overlays := listOverlaysByName(t, c)
+	if _, ok := overlays[preference.OverlayNameForResource(vpref.Name, "platform-a")]; !ok || len(overlays) != 1 {
+		t.Errorf("expected only the platform-a overlay, got %v", overlays)
+	}
+
+	var got veneerv1alpha1.VeneerPreference
+	if err := c.Get(context.Background(), types.NamespacedName{Name: vpref.Name}, &got); err != nil {
+		t.Fatalf("failed to get VeneerPreference: %v", err)
+	}
+	ready := meta.FindStatusCondition(got.Status.Conditions, veneerv1alpha1.ConditionTypeReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != veneerv1alpha1.ReasonInvalidSpec {
+		t.Fatalf("expected Ready=False with reason %s, got %v", veneerv1alpha1.ReasonInvalidSpec, ready)
+	}
+	if !strings.Contains(ready.Message, "NodePool "+longNodePool) {
+		t.Errorf("expected the condition message to name the NodePool, got %q", ready.Message)
+	}
+	if len(got.Status.Overlays) != 1 || got.Status.Overlays[0].NodePool != "platform-a" {
+		t.Errorf("unexpected status overlays: %v", got.Status.Overlays)
+	}
+}
+
+func TestVeneerPreferenceReconciler_Reconcile_Deleted(t *testing.T) {
+	vpref := testVeneerPreference()
+	c := fake.NewClientBuilder().
+		WithScheme(setupTestScheme(t)).
+		WithObjects(vpref, testLabeledNodePool("platform-a", map[string]string{"team": "platform"})).
+		WithStatusSubresource(vpref).
+		Build()
+	r := newVeneerPreferenceReconciler(c)
+
+	reconcileVeneerPreference(t, r, vpref.Name)
+	if len(listOverlaysByName(t, c)) != 1 {
+		t.Fatal("expected 1 overlay after initial reconcile")
+	}
+
+	if err := c.Delete(context.Background(), vpref); err != nil {
+		t.Fatalf("failed to delete VeneerPreference: %v", err)
+	}
+	reconcileVeneerPreference(t, r, vpref.Name)
+
+	if overlays := listOverlaysByName(t, c); len(overlays) != 0 {
+		t.Errorf("expected overlays to be cleaned up, got %v", overlays)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook provides the admission webhooks served by the Veneer controller manager.
+//
+// The NodePool validator rejects (or warns about) veneer.io/preference.N annotations that
+// the NodePool reconciler would otherwise only report after the fact, in logs and events.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/pkg/config"
+	"github.com/nextdoor/veneer/pkg/preference"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// NodePoolValidatorPath is the path the NodePool preference validator is served at.
+const NodePoolValidatorPath = "/validate-karpenter-sh-v1-nodepool"
+
+// NodePoolValidator is a validating admission webhook that parses veneer.io/preference.N
+// annotations when NodePools are created or updated, using the same parser and adjustment
+// range as the NodePool reconciler.
+//
+// On update, only annotations whose value changed are checked, so NodePools that already
+// carry invalid annotations can still be updated by other actors (e.g., Karpenter).
+type NodePoolValidator struct {
+	// Config supplies the controller configuration. Optional: defaults apply when nil.
+	Config config.Provider
+
+	// Logger is the structured logger for this webhook
+	Logger logr.Logger
+}
+
+var _ admission.Handler = &NodePoolValidator{}
+
+// Handle validates the preference annotations of a NodePool admission request.
+//
+// Invalid annotations are rejected, or admitted with warnings when webhook.warnOnly is set.
+// Requests the webhook can't evaluate are admitted with a warning when webhook.failOpen is
+// set, and rejected otherwise.
+func (v *NodePoolValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	log := v.Logger.WithValues("nodepool", req.Name, "operation", req.Operation)
+
+	var nodePool metav1.PartialObjectMetadata
+	if err := json.Unmarshal(req.Object.Raw, &nodePool); err != nil {
+		return v.failure(log, fmt.Errorf("failed to decode NodePool: %w", err))
+	}
+
+	var oldAnnotations map[string]string
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		var oldNodePool metav1.PartialObjectMetadata
+		if err := json.Unmarshal(req.OldObject.Raw, &oldNodePool); err != nil {
+			return v.failure(log, fmt.Errorf("failed to decode old NodePool: %w", err))
+		}
+		oldAnnotations = oldNodePool.Annotations
+	}
+
+	problems := v.validate(nodePool.Name, nodePool.Annotations, oldAnnotations)
+	if len(problems) == 0 {
+		return admission.Allowed("")
+	}
+
+	if v.webhookConfig().WarnOnly {
+		log.Info("Admitting NodePool with invalid preference annotations (warn-only mode)", "problems", problems)
+		return admission.Allowed("").WithWarnings(problems...)
+	}
+
+	log.Info("Rejecting NodePool with invalid preference annotations", "problems", problems)
+	return admission.Denied("invalid Veneer preference annotations: " + strings.Join(problems, "; "))
+}
+
+// validate returns a message for each invalid preference annotation, sorted for stable output.
+// When oldAnnotations is non-nil, annotations whose value didn't change are not reported.
+func (v *NodePoolValidator) validate(nodePoolName string, annotations, oldAnnotations map[string]string) []string {
+	var prefsConfig config.PreferencesConfig
+	if cfg := v.currentConfig(); cfg != nil {
+		prefsConfig = cfg.Preferences
+	}
+	prefs, errs := preference.ParseNodePoolPreferences(annotations, nodePoolName,
+		preference.NewLabelSet(prefsConfig.SupportedLabels))
+	minAdjustment, maxAdjustment := prefsConfig.AdjustmentRange()
+	_, rangeErrs := preference.CheckAdjustmentRange(prefs, minAdjustment, maxAdjustment,
+		prefsConfig.AbsoluteAdjustmentLimit())
+	errs = append(errs, rangeErrs...)
+
+	var problems []string
+	for _, err := range errs {
+		var parseErr preference.ParseError
+		if oldAnnotations != nil && errors.As(err, &parseErr) {
+			if oldValue, existed := oldAnnotations[parseErr.AnnotationKey]; existed &&
+				oldValue == annotations[parseErr.AnnotationKey] {
+				continue
+			}
+		}
+		problems = append(problems, err.Error())
+	}
+	sort.Strings(problems)
+
+	return problems
+}
+
+// failure builds the response for a request the webhook couldn't evaluate.
+func (v *NodePoolValidator) failure(log logr.Logger, err error) admission.Response {
+	log.Error(err, "Failed to validate NodePool preference annotations")
+	if v.webhookConfig().FailOpen {
+		return admission.Allowed("").WithWarnings(
+			fmt.Sprintf("Veneer could not validate preference annotations: %v", err))
+	}
+	return admission.Errored(http.StatusBadRequest, err)
+}
+
+// webhookConfig returns the webhook configuration, or its defaults when Config is nil.
+func (v *NodePoolValidator) webhookConfig() config.WebhookConfig {
+	cfg := v.currentConfig()
+	if cfg == nil {
+		return config.WebhookConfig{FailOpen: config.DefaultWebhookFailOpen}
+	}
+	return cfg.Webhook
+}
+
+// currentConfig returns the configuration currently in effect, or nil when Config is nil.
+func (v *NodePoolValidator) currentConfig() *config.Config {
+	if v.Config == nil {
+		return nil
+	}
+	return v.Config.Current()
+}
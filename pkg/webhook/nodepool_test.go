@@ -0,0 +1,236 @@
+/*
+Copyright 2025 Veneer Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/nextdoor/veneer/pkg/config"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+// nodePoolRaw returns the JSON encoding of a NodePool with the given annotations.
+func nodePoolRaw(t *testing.T, annotations map[string]string) []byte {
+	t.Helper()
+	raw, err := json.Marshal(&karpenterv1.NodePool{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "karpenter.sh/v1", Kind: "NodePool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "general-purpose", Annotations: annotations},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal NodePool: %v", err)
+	}
+	return raw
+}
+
+func TestNodePoolValidator_Handle(t *testing.T) {
+	valid := map[string]string{
+		"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7g adjust=-20%",
+	}
+	unsupportedKey := map[string]string{
+		"veneer.io/preference.1": "example.com/team=platform adjust=-20%",
+	}
+	outOfRange := map[string]string{
+		"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7g adjust=-50%",
+	}
+
+	tests := []struct {
+		name        string
+		webhook     config.WebhookConfig
+		operation   admissionv1.Operation
+		annotations map[string]string
+		old         map[string]string
+		raw         []byte
+		wantAllowed bool
+		wantCode    int32
+		wantMessage string
+		wantWarning string
+	}{
+		{
+			name:        "valid preference is allowed",
+			operation:   admissionv1.Create,
+			annotations: valid,
+			wantAllowed: true,
+		},
+		{
+			name:        "no preferences is allowed",
+			operation:   admissionv1.Create,
+			annotations: map[string]string{"example.com/owner": "platform"},
+			wantAllowed: true,
+		},
+		{
+			name:        "unsupported label key is denied",
+			operation:   admissionv1.Create,
+			annotations: unsupportedKey,
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+			wantMessage: "unsupported label key",
+		},
+		{
+			name:      "missing adjustment is denied",
+			operation: admissionv1.Create,
+			annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7g",
+			},
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+			wantMessage: "veneer.io/preference.1",
+		},
+		{
+			name:      "non-numeric Gt value is denied",
+			operation: admissionv1.Create,
+			annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-cpu>large adjust=-10%",
+			},
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+			wantMessage: "veneer.io/preference.1",
+		},
+		{
+			name:        "adjustment outside the configured range is denied",
+			operation:   admissionv1.Create,
+			annotations: outOfRange,
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+			wantMessage: "outside the allowed range",
+		},
+		{
+			name:      "absolute adjustment above the dollar limit is denied",
+			operation: admissionv1.Create,
+			annotations: map[string]string{
+				"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7g adjust=-20",
+			},
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+			wantMessage: "use adjust=-20% for a percentage",
+		},
+		{
+			name:        "warn-only mode allows with warnings",
+			webhook:     config.WebhookConfig{WarnOnly: true},
+			operation:   admissionv1.Create,
+			annotations: unsupportedKey,
+			wantAllowed: true,
+			wantWarning: "unsupported label key",
+		},
+		{
+			name:        "unchanged invalid preference is allowed on update",
+			operation:   admissionv1.Update,
+			annotations: unsupportedKey,
+			old:         unsupportedKey,
+			wantAllowed: true,
+		},
+		{
+			name:        "changed invalid preference is denied on update",
+			operation:   admissionv1.Update,
+			annotations: unsupportedKey,
+			old:         valid,
+			wantAllowed: false,
+			wantCode:    http.StatusForbidden,
+			wantMessage: "unsupported label key",
+		},
+		{
+			name:        "undecodable object is allowed when failing open",
+			webhook:     config.WebhookConfig{FailOpen: true},
+			operation:   admissionv1.Create,
+			raw:         []byte("{not json"),
+			wantAllowed: true,
+			wantWarning: "could not validate",
+		},
+		{
+			name:        "undecodable object is rejected when failing closed",
+			webhook:     config.WebhookConfig{FailOpen: false},
+			operation:   admissionv1.Create,
+			raw:         []byte("{not json"),
+			wantAllowed: false,
+			wantCode:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Preferences: config.PreferencesConfig{MinAdjustment: ptr.To(-30.0), MaxAdjustment: ptr.To(30.0)},
+				Webhook:     tt.webhook,
+			}
+			validator := &NodePoolValidator{Config: cfg, Logger: logr.Discard()}
+
+			raw := tt.raw
+			if raw == nil {
+				raw = nodePoolRaw(t, tt.annotations)
+			}
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Name:      "general-purpose",
+				Operation: tt.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			}}
+			if tt.old != nil {
+				req.OldObject = runtime.RawExtension{Raw: nodePoolRaw(t, tt.old)}
+			}
+
+			resp := validator.Handle(context.Background(), req)
+
+			if resp.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v (result: %+v)", resp.Allowed, tt.wantAllowed, resp.Result)
+			}
+			if tt.wantCode != 0 && resp.Result.Code != tt.wantCode {
+				t.Errorf("Result.Code = %d, want %d", resp.Result.Code, tt.wantCode)
+			}
+			if tt.wantMessage != "" && !strings.Contains(resp.Result.Message, tt.wantMessage) {
+				t.Errorf("Result.Message = %q, want it to contain %q", resp.Result.Message, tt.wantMessage)
+			}
+			if tt.wantWarning != "" {
+				if len(resp.Warnings) == 0 || !strings.Contains(strings.Join(resp.Warnings, "; "), tt.wantWarning) {
+					t.Errorf("Warnings = %v, want one containing %q", resp.Warnings, tt.wantWarning)
+				}
+			} else if len(resp.Warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", resp.Warnings)
+			}
+		})
+	}
+}
+
+func TestNodePoolValidator_NilConfig(t *testing.T) {
+	validator := &NodePoolValidator{Logger: logr.Discard()}
+
+	// Defaults allow any adjustment from -100% to +100% and fail open
+	resp := validator.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object: runtime.RawExtension{Raw: nodePoolRaw(t, map[string]string{
+			"veneer.io/preference.1": "karpenter.k8s.aws/instance-family=c7g adjust=-90%",
+		})},
+	}})
+	if !resp.Allowed {
+		t.Errorf("expected valid preference to be allowed with default config, got %+v", resp.Result)
+	}
+
+	resp = validator.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte("{not json")},
+	}})
+	if !resp.Allowed || len(resp.Warnings) == 0 {
+		t.Errorf("expected undecodable object to be allowed with a warning by default, got %+v", resp)
+	}
+}